@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -9,9 +10,17 @@ import (
 
 // Config holds all application configuration
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	JWT        JWTConfig
+	Trash      TrashConfig
+	Comments   CommentsConfig
+	Moderation ModerationConfig
+	Storage    StorageConfig
+	I18n       I18nConfig
+	Engagement EngagementConfig
+	Site       SiteConfig
+	Analytics  AnalyticsConfig
 }
 
 // ServerConfig holds server configuration
@@ -19,6 +28,7 @@ type ServerConfig struct {
 	Host                 string
 	Port                 string
 	LimitCountPerRequest float64
+	RequireIfMatch       bool
 }
 
 // DatabaseConfig holds database configuration
@@ -39,6 +49,96 @@ type JWTConfig struct {
 	Secret               string
 	AccessTokenDuration  time.Duration
 	RefreshTokenDuration time.Duration
+	// Admins lists the emails of users who may read and change any post
+	Admins []string
+}
+
+// TrashConfig holds soft-delete retention configuration
+type TrashConfig struct {
+	RetentionDays int
+	PurgeInterval time.Duration
+}
+
+// CommentsConfig holds comment threading and moderation configuration
+type CommentsConfig struct {
+	MaxDepth        int
+	RequireApproval bool
+}
+
+// ModerationConfig holds the configuration of the built-in content moderator
+type ModerationConfig struct {
+	Enabled bool
+	// Words lists the words and phrases that hold posts and comments for moderation
+	Words []string
+	// MaxLinks is the most links a post or comment may have without being held
+	MaxLinks int
+}
+
+// SiteConfig describes the public site, as presented in feeds and the sitemap
+type SiteConfig struct {
+	Title       string
+	Description string
+	// BaseURL is the absolute URL the API is served at, without a trailing slash
+	BaseURL string
+	// FeedSize is the number of most recent posts listed in feeds
+	FeedSize int
+	// SitemapRefresh is how long the sitemap is served before it is updated
+	// with the posts changed since
+	SitemapRefresh time.Duration
+}
+
+// EngagementConfig holds reaction and bookmark configuration
+type EngagementConfig struct {
+	// Reactions is the set of emoji users may react to posts with
+	Reactions []string
+}
+
+// AnalyticsConfig holds post view recording configuration
+type AnalyticsConfig struct {
+	// DedupeWindow is how long repeated views of a post by one visitor count once
+	DedupeWindow  time.Duration
+	FlushInterval time.Duration
+	// BatchSize is the number of distinct post days buffered before an early flush
+	BatchSize int
+	// BufferSize is the number of views queued before new ones are dropped
+	BufferSize int
+}
+
+// I18nConfig holds the locales posts are translated to
+type I18nConfig struct {
+	// DefaultLocale is the locale posts themselves are written in
+	DefaultLocale string
+	// Fallbacks maps a locale to the locales tried after it, as in pt-BR to pt-PT
+	Fallbacks map[string][]string
+}
+
+// StorageConfig holds attachment storage configuration
+type StorageConfig struct {
+	// Driver is "local" or "s3"
+	Driver        string
+	LocalDir      string
+	MaxUploadSize int64
+	AllowedTypes  []string
+	URLTTL        time.Duration
+	SigningKey    string
+	S3            S3Config
+	Derivatives   DerivativesConfig
+}
+
+// DerivativesConfig holds the sizes and formats cover images are resized to
+type DerivativesConfig struct {
+	Widths  []int
+	Formats []string
+	Quality int
+}
+
+// S3Config holds the settings of an S3-compatible storage service
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
 }
 
 // Load loads configuration from file
@@ -55,6 +155,35 @@ func Load(configPath ...string) (*Config, error) {
 	v.SetDefault("jwt.secret", "change-me-in-production")
 	v.SetDefault("jwt.accessTokenExpireDuration", 1)
 	v.SetDefault("jwt.refreshTokenExpireDuration", 24)
+	v.SetDefault("trash.retentionDays", 30)
+	v.SetDefault("trash.purgeIntervalMinutes", 60)
+	v.SetDefault("comments.maxDepth", 5)
+	v.SetDefault("comments.requireApproval", true)
+	v.SetDefault("moderation.enabled", true)
+	v.SetDefault("moderation.words", []string{"viagra", "casino", "payday loan", "free money"})
+	v.SetDefault("moderation.maxLinks", 3)
+	v.SetDefault("site.title", "UGin")
+	v.SetDefault("site.baseURL", "http://localhost:8081")
+	v.SetDefault("site.feedSize", 20)
+	v.SetDefault("site.sitemapRefreshSeconds", 60)
+	v.SetDefault("analytics.dedupeWindowMinutes", 30)
+	v.SetDefault("analytics.flushIntervalSeconds", 10)
+	v.SetDefault("analytics.batchSize", 500)
+	v.SetDefault("analytics.bufferSize", 10000)
+	v.SetDefault("i18n.defaultLocale", "en")
+	v.SetDefault("engagement.reactions", []string{"👍", "❤️", "🎉", "😄", "😮", "😢"})
+	v.SetDefault("storage.driver", "local")
+	v.SetDefault("storage.localDir", "uploads")
+	v.SetDefault("storage.maxUploadSizeMB", 10)
+	v.SetDefault("storage.allowedTypes", []string{
+		"image/jpeg", "image/png", "image/gif", "image/webp",
+		"application/pdf", "application/zip", "text/plain",
+	})
+	v.SetDefault("storage.urlTTLMinutes", 15)
+	v.SetDefault("storage.s3.region", "us-east-1")
+	v.SetDefault("storage.derivatives.widths", []int{320, 640, 1280})
+	v.SetDefault("storage.derivatives.formats", []string{"jpeg", "png", "webp"})
+	v.SetDefault("storage.derivatives.quality", 85)
 
 	// Set config file
 	v.SetConfigName("config")
@@ -84,6 +213,7 @@ func Load(configPath ...string) (*Config, error) {
 	cfg.Server.Host = v.GetString("server.host")
 	cfg.Server.Port = v.GetString("server.port")
 	cfg.Server.LimitCountPerRequest = v.GetFloat64("server.limitCountPerRequest")
+	cfg.Server.RequireIfMatch = v.GetBool("server.requireIfMatch")
 
 	// Database config
 	cfg.Database.Driver = v.GetString("database.driver")
@@ -115,6 +245,62 @@ func Load(configPath ...string) (*Config, error) {
 		refreshTokenHours = 24
 	}
 	cfg.JWT.RefreshTokenDuration = time.Hour * time.Duration(refreshTokenHours)
+	cfg.JWT.Admins = v.GetStringSlice("server.admins")
+
+	// Trash config
+	cfg.Trash.RetentionDays = v.GetInt("trash.retentionDays")
+	cfg.Trash.PurgeInterval = time.Minute * time.Duration(v.GetInt("trash.purgeIntervalMinutes"))
+
+	// Comments config
+	cfg.Comments.MaxDepth = v.GetInt("comments.maxDepth")
+	cfg.Comments.RequireApproval = v.GetBool("comments.requireApproval")
+
+	// Moderation config
+	cfg.Moderation.Enabled = v.GetBool("moderation.enabled")
+	cfg.Moderation.Words = v.GetStringSlice("moderation.words")
+	cfg.Moderation.MaxLinks = v.GetInt("moderation.maxLinks")
+
+	// Site config
+	cfg.Site.Title = v.GetString("site.title")
+	cfg.Site.Description = v.GetString("site.description")
+	cfg.Site.BaseURL = strings.TrimRight(v.GetString("site.baseURL"), "/")
+	cfg.Site.FeedSize = v.GetInt("site.feedSize")
+	cfg.Site.SitemapRefresh = time.Second * time.Duration(v.GetInt("site.sitemapRefreshSeconds"))
+
+	// I18n config
+	cfg.I18n.DefaultLocale = v.GetString("i18n.defaultLocale")
+	cfg.I18n.Fallbacks = make(map[string][]string)
+	for locale := range v.GetStringMap("i18n.fallbacks") {
+		cfg.I18n.Fallbacks[locale] = v.GetStringSlice("i18n.fallbacks." + locale)
+	}
+
+	// Engagement config
+	cfg.Engagement.Reactions = v.GetStringSlice("engagement.reactions")
+
+	// Analytics config
+	cfg.Analytics.DedupeWindow = time.Minute * time.Duration(v.GetInt("analytics.dedupeWindowMinutes"))
+	cfg.Analytics.FlushInterval = time.Second * time.Duration(v.GetInt("analytics.flushIntervalSeconds"))
+	cfg.Analytics.BatchSize = v.GetInt("analytics.batchSize")
+	cfg.Analytics.BufferSize = v.GetInt("analytics.bufferSize")
+
+	// Storage config
+	cfg.Storage.Driver = v.GetString("storage.driver")
+	cfg.Storage.LocalDir = v.GetString("storage.localDir")
+	cfg.Storage.MaxUploadSize = v.GetInt64("storage.maxUploadSizeMB") << 20
+	cfg.Storage.AllowedTypes = v.GetStringSlice("storage.allowedTypes")
+	cfg.Storage.URLTTL = time.Minute * time.Duration(v.GetInt("storage.urlTTLMinutes"))
+	cfg.Storage.SigningKey = v.GetString("storage.signingKey")
+	if cfg.Storage.SigningKey == "" {
+		cfg.Storage.SigningKey = cfg.JWT.Secret
+	}
+	cfg.Storage.S3.Endpoint = v.GetString("storage.s3.endpoint")
+	cfg.Storage.S3.Region = v.GetString("storage.s3.region")
+	cfg.Storage.S3.Bucket = v.GetString("storage.s3.bucket")
+	cfg.Storage.S3.AccessKey = v.GetString("storage.s3.accessKey")
+	cfg.Storage.S3.SecretKey = v.GetString("storage.s3.secretKey")
+	cfg.Storage.Derivatives.Widths = v.GetIntSlice("storage.derivatives.widths")
+	cfg.Storage.Derivatives.Formats = v.GetStringSlice("storage.derivatives.formats")
+	cfg.Storage.Derivatives.Quality = v.GetInt("storage.derivatives.quality")
 
 	return cfg, nil
 }
@@ -16,6 +16,7 @@ import (
 	httpHandler "github.com/yakuter/ugin/internal/handler/http"
 	"github.com/yakuter/ugin/internal/repository/gormrepo"
 	"github.com/yakuter/ugin/internal/service"
+	"github.com/yakuter/ugin/pkg/locale"
 	"github.com/yakuter/ugin/pkg/logger"
 	"gorm.io/gorm"
 )
@@ -54,6 +55,16 @@ func New() (*App, error) {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	if err := backfillPublicIDs(db); err != nil {
+		appLogger.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	if err := assignOwners(db, cfg.JWT.Admins); err != nil {
+		appLogger.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
 	return &App{
 		config: cfg,
 		logger: appLogger,
@@ -68,22 +79,118 @@ func (a *App) Run() error {
 	// Initialize repositories
 	postRepo := gormrepo.NewPostRepository(a.db)
 	userRepo := gormrepo.NewUserRepository(a.db)
+	revisionRepo := gormrepo.NewPostRevisionRepository(a.db)
+	commentRepo := gormrepo.NewCommentRepository(a.db)
+	attachmentRepo := gormrepo.NewAttachmentRepository(a.db)
+	categoryRepo := gormrepo.NewCategoryRepository(a.db)
+	seriesRepo := gormrepo.NewSeriesRepository(a.db)
+	contributorRepo := gormrepo.NewContributorRepository(a.db)
+	reviewRepo := gormrepo.NewReviewRepository(a.db)
+	moderationRepo := gormrepo.NewModerationRepository(a.db)
+	translationRepo := gormrepo.NewPostTranslationRepository(a.db)
+	engagementRepo := gormrepo.NewEngagementRepository(a.db)
+	analyticsRepo := gormrepo.NewAnalyticsRepository(a.db)
+
+	// Initialize storage
+	blobStore, err := newBlobStore(a.config.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	// Initialize locale negotiation
+	negotiator, err := locale.NewNegotiator(a.config.I18n.DefaultLocale, a.config.I18n.Fallbacks)
+	if err != nil {
+		return fmt.Errorf("failed to initialize locales: %w", err)
+	}
 
 	// Initialize services
 	authConfig := &service.AuthConfig{
 		JWTSecret:            a.config.JWT.Secret,
 		AccessTokenDuration:  a.config.JWT.AccessTokenDuration,
 		RefreshTokenDuration: a.config.JWT.RefreshTokenDuration,
+		Admins:               a.config.JWT.Admins,
+	}
+	commentConfig := &service.CommentConfig{
+		MaxDepth:        a.config.Comments.MaxDepth,
+		RequireApproval: a.config.Comments.RequireApproval,
+	}
+	// Without a moderator all content is shown right away
+	var moderator service.ContentModerator
+	if a.config.Moderation.Enabled {
+		moderator = service.NewFilterModerator(&service.ModerationConfig{
+			Words:    a.config.Moderation.Words,
+			MaxLinks: a.config.Moderation.MaxLinks,
+		})
 	}
-	postService := service.NewPostService(postRepo, a.logger)
+	postService := service.NewPostService(postRepo, revisionRepo, categoryRepo, contributorRepo, reviewRepo, moderator, moderationRepo, a.logger)
+	categoryService := service.NewCategoryService(categoryRepo, a.logger)
+	seriesService := service.NewSeriesService(seriesRepo, postRepo, contributorRepo, a.logger)
+	commentService := service.NewCommentService(commentRepo, postRepo, contributorRepo, moderationRepo, moderator, commentConfig, a.logger)
+	moderationService := service.NewModerationService(moderationRepo, postService, commentRepo, a.logger)
+	attachmentService := service.NewAttachmentService(attachmentRepo, postRepo, contributorRepo, blobStore, newAttachmentConfig(a.config.Storage), a.logger)
+	translationService := service.NewTranslationService(translationRepo, postRepo, contributorRepo, negotiator.Default(), a.logger)
+	engagementService := service.NewEngagementService(engagementRepo, postRepo, contributorRepo, userRepo, &service.EngagementConfig{Reactions: a.config.Engagement.Reactions}, a.logger)
+	analyticsService := service.NewAnalyticsService(analyticsRepo, postRepo, contributorRepo, &service.AnalyticsConfig{
+		DedupeWindow:  a.config.Analytics.DedupeWindow,
+		FlushInterval: a.config.Analytics.FlushInterval,
+		BatchSize:     a.config.Analytics.BatchSize,
+		BufferSize:    a.config.Analytics.BufferSize,
+	}, a.logger)
+	relatedService := service.NewRelatedService(postRepo, a.logger)
+	postService.Subscribe(relatedService.PostChanged)
+	suggestService := service.NewSuggestService(postRepo, a.logger)
+	postService.Subscribe(suggestService.PostChanged)
+	sitemapService := service.NewSitemapService(postRepo, &service.SitemapConfig{
+		BaseURL: a.config.Site.BaseURL,
+		Refresh: a.config.Site.SitemapRefresh,
+	}, a.logger)
 	authService := service.NewAuthService(userRepo, authConfig, a.logger)
 
 	// Initialize handlers
-	postHandler := httpHandler.NewPostHandler(postService)
+	postHandler := httpHandler.NewPostHandler(postService, attachmentService, translationService, analyticsService, negotiator, a.config.Server.RequireIfMatch)
+	commentHandler := httpHandler.NewCommentHandler(commentService)
+	categoryHandler := httpHandler.NewCategoryHandler(categoryService)
+	seriesHandler := httpHandler.NewSeriesHandler(seriesService)
+	engagementHandler := httpHandler.NewEngagementHandler(engagementService, attachmentService)
+	attachmentHandler := httpHandler.NewAttachmentHandler(attachmentService, a.config.Storage.MaxUploadSize)
 	authHandler := httpHandler.NewAuthHandler(authService)
+	feedHandler := httpHandler.NewFeedHandler(postService, httpHandler.FeedConfig{
+		Title:       a.config.Site.Title,
+		Description: a.config.Site.Description,
+		BaseURL:     a.config.Site.BaseURL,
+		Size:        a.config.Site.FeedSize,
+	})
+	sitemapHandler := httpHandler.NewSitemapHandler(sitemapService)
+	analyticsHandler := httpHandler.NewAnalyticsHandler(analyticsService)
+	relatedHandler := httpHandler.NewRelatedHandler(relatedService)
+	suggestHandler := httpHandler.NewSuggestHandler(suggestService)
+	moderationHandler := httpHandler.NewModerationHandler(moderationService)
+
+	// Backfill slugs for posts created before slugs existed
+	if _, err := postService.GenerateMissingSlugs(context.Background()); err != nil {
+		return fmt.Errorf("failed to generate post slugs: %w", err)
+	}
+	if _, err := postService.RenderMissingDescriptions(context.Background()); err != nil {
+		return fmt.Errorf("failed to render post descriptions: %w", err)
+	}
+
+	// Build the suggestion index
+	if err := suggestService.Load(context.Background()); err != nil {
+		return fmt.Errorf("failed to load suggestions: %w", err)
+	}
+
+	// Start background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go runTrashPurge(jobsCtx, a.config.Trash, postService, attachmentService, a.logger)
+	viewsDone := make(chan struct{})
+	go func() {
+		defer close(viewsDone)
+		analyticsService.Run(jobsCtx)
+	}()
 
 	// Setup router
-	router := SetupRouter(a.config, postHandler, authHandler, authService, a.logger)
+	router := SetupRouter(a.config, postHandler, commentHandler, categoryHandler, seriesHandler, engagementHandler, attachmentHandler, authHandler, feedHandler, sitemapHandler, analyticsHandler, relatedHandler, suggestHandler, moderationHandler, authService, a.logger)
 
 	// Create server
 	addr := fmt.Sprintf("%s:%s", a.config.Server.Host, a.config.Server.Port)
@@ -107,7 +214,12 @@ func (a *App) Run() error {
 	}()
 
 	// Wait for interrupt signal to gracefully shut down the server
-	return a.waitForShutdown()
+	err = a.waitForShutdown()
+
+	// Stop background jobs, letting buffered views be written
+	stopJobs()
+	<-viewsDone
+	return err
 }
 
 // waitForShutdown waits for interrupt signal and performs graceful shutdown
@@ -139,6 +251,20 @@ func autoMigrate(db *gorm.DB) error {
 		&domain.Post{},
 		&domain.Tag{},
 		&domain.User{},
+		&domain.PostRevision{},
+		&domain.PostSlug{},
+		&domain.Comment{},
+		&domain.Attachment{},
+		&domain.Category{},
+		&domain.Series{},
+		&domain.SeriesPost{},
+		&domain.PostContributor{},
+		&domain.PostReview{},
+		&domain.ModerationItem{},
+		&domain.PostTranslation{},
+		&domain.Reaction{},
+		&domain.Bookmark{},
+		&domain.PostDailyViews{},
 	)
 }
 
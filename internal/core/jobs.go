@@ -0,0 +1,39 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/yakuter/ugin/internal/config"
+	"github.com/yakuter/ugin/internal/service"
+	"github.com/yakuter/ugin/pkg/logger"
+)
+
+// runTrashPurge permanently deletes posts that have been in the trash
+// longer than the configured retention, along with their attachments,
+// until ctx is cancelled
+func runTrashPurge(ctx context.Context, cfg config.TrashConfig, postService service.PostService, attachmentService service.AttachmentService, appLogger *logger.Logger) {
+	if cfg.RetentionDays <= 0 || cfg.PurgeInterval <= 0 {
+		appLogger.Info("trash purge disabled")
+		return
+	}
+
+	retention := time.Duration(cfg.RetentionDays) * 24 * time.Hour
+	ticker := time.NewTicker(cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := postService.PurgeTrash(ctx, retention); err != nil {
+			appLogger.Error("trash purge failed", "error", err)
+		}
+		if _, err := attachmentService.DeleteOrphaned(ctx); err != nil {
+			appLogger.Error("attachment cleanup failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/pkg/uid"
+	"gorm.io/gorm"
+)
+
+// backfillBatchSize is the number of rows updated per transaction by backfills
+const backfillBatchSize = 500
+
+// backfillPublicIDs assigns public IDs to rows created before the
+// public_id column existed. New rows get theirs from BeforeCreate hooks.
+func backfillPublicIDs(db *gorm.DB) error {
+	models := []interface{}{&domain.Post{}, &domain.Tag{}, &domain.User{}}
+
+	for _, model := range models {
+		for {
+			var ids []uint
+			if err := db.Unscoped().Model(model).
+				Where("public_id IS NULL OR public_id = ''").
+				Limit(backfillBatchSize).
+				Pluck("id", &ids).Error; err != nil {
+				return fmt.Errorf("failed to find rows without public ID: %w", err)
+			}
+
+			if len(ids) == 0 {
+				break
+			}
+
+			err := db.Transaction(func(tx *gorm.DB) error {
+				for _, id := range ids {
+					publicID, err := uid.NewV7()
+					if err != nil {
+						return err
+					}
+
+					if err := tx.Unscoped().Model(model).
+						Where("id = ?", id).
+						UpdateColumn("public_id", publicID).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("failed to backfill public IDs: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// assignOwners gives an owner to posts nobody contributes to, such as those
+// created anonymously or before posts had contributors, since only admins
+// could change them otherwise. The owner is the registered user who wrote
+// the earliest revision of the post, or else the first of admins who has
+// an account. Trashed posts get one too, so they can be restored.
+func assignOwners(db *gorm.DB, admins []string) error {
+	var fallback *domain.User
+	for _, email := range admins {
+		var admin domain.User
+		err := db.Where("LOWER(email) = ? AND deleted_at IS NULL", strings.ToLower(strings.TrimSpace(email))).Take(&admin).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find admin: %w", err)
+		}
+		fallback = &admin
+		break
+	}
+
+	// Posts that get no owner are skipped by moving past their IDs
+	var lastID uint
+	for {
+		var ids []uint
+		if err := db.Unscoped().Model(&domain.Post{}).
+			Where("id > ?", lastID).
+			Where("NOT EXISTS (SELECT 1 FROM post_contributors WHERE post_contributors.post_id = posts.id)").
+			Order("id").
+			Limit(backfillBatchSize).
+			Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("failed to find posts without owner: %w", err)
+		}
+
+		if len(ids) == 0 {
+			break
+		}
+		lastID = ids[len(ids)-1]
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for _, id := range ids {
+				var owner domain.User
+				err := tx.Model(&domain.User{}).
+					Joins("JOIN post_revisions ON LOWER(post_revisions.author) = LOWER(users.email)").
+					Where("post_revisions.post_id = ? AND users.deleted_at IS NULL", id).
+					Order("post_revisions.revision").
+					Take(&owner).Error
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					if fallback == nil {
+						continue
+					}
+					owner = *fallback
+				} else if err != nil {
+					return err
+				}
+
+				if err := tx.Create(&domain.PostContributor{PostID: id, UserID: owner.ID, Role: domain.RoleOwner}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to assign post owners: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -17,7 +17,18 @@ import (
 func SetupRouter(
 	cfg *config.Config,
 	postHandler *httpHandler.PostHandler,
+	commentHandler *httpHandler.CommentHandler,
+	categoryHandler *httpHandler.CategoryHandler,
+	seriesHandler *httpHandler.SeriesHandler,
+	engagementHandler *httpHandler.EngagementHandler,
+	attachmentHandler *httpHandler.AttachmentHandler,
 	authHandler *httpHandler.AuthHandler,
+	feedHandler *httpHandler.FeedHandler,
+	sitemapHandler *httpHandler.SitemapHandler,
+	analyticsHandler *httpHandler.AnalyticsHandler,
+	relatedHandler *httpHandler.RelatedHandler,
+	suggestHandler *httpHandler.SuggestHandler,
+	moderationHandler *httpHandler.ModerationHandler,
 	authService service.AuthService,
 	appLogger *logger.Logger,
 ) *gin.Engine {
@@ -41,11 +52,15 @@ func SetupRouter(
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Syndication feeds and sitemap
+	setupFeedRoutes(router, feedHandler)
+	setupSitemapRoutes(router, sitemapHandler)
+
 	// API v1 routes
-	setupAPIv1Routes(router, postHandler, authHandler, authService)
+	setupAPIv1Routes(router, postHandler, commentHandler, categoryHandler, seriesHandler, engagementHandler, analyticsHandler, relatedHandler, suggestHandler, attachmentHandler, authHandler, authService)
 
 	// Admin routes
-	setupAdminRoutes(router)
+	setupAdminRoutes(router, analyticsHandler, moderationHandler)
 
 	return router
 }
@@ -54,6 +69,14 @@ func SetupRouter(
 func setupAPIv1Routes(
 	router *gin.Engine,
 	postHandler *httpHandler.PostHandler,
+	commentHandler *httpHandler.CommentHandler,
+	categoryHandler *httpHandler.CategoryHandler,
+	seriesHandler *httpHandler.SeriesHandler,
+	engagementHandler *httpHandler.EngagementHandler,
+	analyticsHandler *httpHandler.AnalyticsHandler,
+	relatedHandler *httpHandler.RelatedHandler,
+	suggestHandler *httpHandler.SuggestHandler,
+	attachmentHandler *httpHandler.AttachmentHandler,
 	authHandler *httpHandler.AuthHandler,
 	authService service.AuthService,
 ) {
@@ -68,14 +91,114 @@ func setupAPIv1Routes(
 			auth.POST("/check", authHandler.CheckToken)
 		}
 
-		// Post routes (public)
+		// Post routes. Reading is public, writing needs a JWT.
 		posts := v1.Group("/posts")
 		{
 			posts.GET("", postHandler.List)
+			posts.GET("/export", postHandler.Export)
 			posts.GET("/:id", postHandler.GetByID)
-			posts.POST("", postHandler.Create)
-			posts.PUT("/:id", postHandler.Update)
-			posts.DELETE("/:id", postHandler.Delete)
+			posts.POST("", httpHandler.JWTAuth(authService), postHandler.Create)
+			posts.POST("/bulk", httpHandler.JWTAuth(authService), postHandler.Bulk)
+			posts.POST("/import", httpHandler.JWTAuth(authService), postHandler.Import)
+			posts.PUT("/:id", httpHandler.JWTAuth(authService), postHandler.Update)
+			posts.PATCH("/:id", httpHandler.JWTAuth(authService), postHandler.Patch)
+			posts.DELETE("/:id", httpHandler.JWTAuth(authService), postHandler.Delete)
+			posts.GET("/:id/revisions", httpHandler.JWTAuth(authService), postHandler.ListRevisions)
+			posts.GET("/:id/revisions/:rev/diff", httpHandler.JWTAuth(authService), postHandler.DiffRevisions)
+			posts.POST("/:id/revisions/:rev/restore", httpHandler.JWTAuth(authService), postHandler.RestoreRevision)
+			posts.POST("/:id/restore", httpHandler.JWTAuth(authService), postHandler.Restore)
+			posts.GET("/:id/comments", commentHandler.List)
+			posts.POST("/:id/comments", commentHandler.Create)
+			posts.GET("/:id/attachments", attachmentHandler.List)
+			posts.POST("/:id/attachments", httpHandler.JWTAuth(authService), attachmentHandler.Upload)
+			posts.PUT("/:id/cover", httpHandler.JWTAuth(authService), attachmentHandler.Cover)
+			posts.GET("/:id/translations", postHandler.ListTranslations)
+			posts.PUT("/:id/translations/:locale", httpHandler.JWTAuth(authService), postHandler.PutTranslation)
+			posts.DELETE("/:id/translations/:locale", httpHandler.JWTAuth(authService), postHandler.DeleteTranslation)
+			posts.PUT("/:id/reaction", httpHandler.JWTAuth(authService), engagementHandler.React)
+			posts.DELETE("/:id/reaction", httpHandler.JWTAuth(authService), engagementHandler.Unreact)
+			posts.PUT("/:id/bookmark", httpHandler.JWTAuth(authService), engagementHandler.Bookmark)
+			posts.DELETE("/:id/bookmark", httpHandler.JWTAuth(authService), engagementHandler.Unbookmark)
+			posts.GET("/:id/stats", analyticsHandler.Stats)
+			posts.GET("/:id/related", relatedHandler.Related)
+			posts.GET("/:id/contributors", httpHandler.JWTAuth(authService), postHandler.ListContributors)
+			posts.POST("/:id/contributors", httpHandler.JWTAuth(authService), postHandler.InviteContributor)
+			posts.DELETE("/:id/contributors/:user_id", httpHandler.JWTAuth(authService), postHandler.RemoveContributor)
+			posts.GET("/:id/review", httpHandler.JWTAuth(authService), postHandler.GetReview)
+			posts.POST("/:id/review/submit", httpHandler.JWTAuth(authService), postHandler.SubmitForReview)
+			posts.PUT("/:id/review/reviewer", httpHandler.JWTAuth(authService), postHandler.AssignReviewer)
+			posts.POST("/:id/review/approve", httpHandler.JWTAuth(authService), postHandler.Approve)
+			posts.POST("/:id/review/request-changes", httpHandler.JWTAuth(authService), postHandler.RequestChanges)
+			posts.POST("/:id/review/publish", httpHandler.JWTAuth(authService), postHandler.Publish)
+			posts.POST("/:id/review/comments", httpHandler.JWTAuth(authService), postHandler.CommentOnReview)
+		}
+
+		// Trash routes (JWT protected)
+		trash := v1.Group("/trash")
+		trash.Use(httpHandler.JWTAuth(authService))
+		{
+			trash.GET("/posts", postHandler.ListTrash)
+		}
+
+		// Comment moderation routes (JWT protected)
+		comments := v1.Group("/comments")
+		comments.Use(httpHandler.JWTAuth(authService))
+		{
+			comments.PUT("/:id", commentHandler.Update)
+			comments.DELETE("/:id", commentHandler.Delete)
+		}
+
+		// Category routes. Reading the tree is public, changing it needs a JWT.
+		categories := v1.Group("/categories")
+		{
+			categories.GET("", categoryHandler.List)
+			categories.GET("/:id", categoryHandler.GetByID)
+			categories.POST("", httpHandler.JWTAuth(authService), categoryHandler.Create)
+			categories.PUT("/:id", httpHandler.JWTAuth(authService), categoryHandler.Update)
+			categories.POST("/:id/move", httpHandler.JWTAuth(authService), categoryHandler.Move)
+			categories.DELETE("/:id", httpHandler.JWTAuth(authService), categoryHandler.Delete)
+		}
+
+		// Series routes. Reading is public, changing a series needs a JWT.
+		series := v1.Group("/series")
+		{
+			series.GET("", seriesHandler.List)
+			series.GET("/:id", seriesHandler.GetByID)
+			series.POST("", httpHandler.JWTAuth(authService), seriesHandler.Create)
+			series.PUT("/:id", httpHandler.JWTAuth(authService), seriesHandler.Update)
+			series.DELETE("/:id", httpHandler.JWTAuth(authService), seriesHandler.Delete)
+			series.POST("/:id/posts", httpHandler.JWTAuth(authService), seriesHandler.AddPost)
+			series.DELETE("/:id/posts/:post_id", httpHandler.JWTAuth(authService), seriesHandler.RemovePost)
+			series.PUT("/:id/order", httpHandler.JWTAuth(authService), seriesHandler.Reorder)
+		}
+
+		// Engagement routes (JWT protected)
+		v1.GET("/reactions", httpHandler.JWTAuth(authService), engagementHandler.Reactions)
+		users := v1.Group("/users")
+		users.Use(httpHandler.JWTAuth(authService))
+		{
+			users.GET("/me/bookmarks", engagementHandler.ListBookmarks)
+		}
+
+		// Archive routes
+		archive := v1.Group("/archive")
+		{
+			archive.GET("", postHandler.Archive)
+			archive.GET("/:year/:month", postHandler.ListArchive)
+		}
+
+		// Suggestion routes
+		suggest := v1.Group("/suggest")
+		{
+			suggest.GET("", suggestHandler.Suggest)
+			suggest.POST("/tags", suggestHandler.SuggestTags)
+		}
+
+		// Attachment routes. Downloads are authorized by their signed URL.
+		attachments := v1.Group("/attachments")
+		{
+			attachments.GET("/:id/download", attachmentHandler.Download)
+			attachments.DELETE("/:id", httpHandler.JWTAuth(authService), attachmentHandler.Delete)
 		}
 
 		// Post routes (JWT protected)
@@ -83,16 +206,61 @@ func setupAPIv1Routes(
 		postsJWT.Use(httpHandler.JWTAuth(authService))
 		{
 			postsJWT.GET("", postHandler.List)
+			postsJWT.GET("/export", postHandler.Export)
 			postsJWT.GET("/:id", postHandler.GetByID)
 			postsJWT.POST("", postHandler.Create)
+			postsJWT.POST("/bulk", postHandler.Bulk)
+			postsJWT.POST("/import", postHandler.Import)
 			postsJWT.PUT("/:id", postHandler.Update)
+			postsJWT.PATCH("/:id", postHandler.Patch)
 			postsJWT.DELETE("/:id", postHandler.Delete)
+			postsJWT.GET("/:id/revisions", postHandler.ListRevisions)
+			postsJWT.GET("/:id/revisions/:rev/diff", postHandler.DiffRevisions)
+			postsJWT.POST("/:id/revisions/:rev/restore", postHandler.RestoreRevision)
+			postsJWT.POST("/:id/restore", postHandler.Restore)
+			postsJWT.GET("/:id/comments", commentHandler.List)
+			postsJWT.POST("/:id/comments", commentHandler.Create)
+			postsJWT.GET("/:id/attachments", attachmentHandler.List)
+			postsJWT.POST("/:id/attachments", attachmentHandler.Upload)
+			postsJWT.PUT("/:id/cover", attachmentHandler.Cover)
+			postsJWT.GET("/:id/translations", postHandler.ListTranslations)
+			postsJWT.PUT("/:id/translations/:locale", postHandler.PutTranslation)
+			postsJWT.DELETE("/:id/translations/:locale", postHandler.DeleteTranslation)
+			postsJWT.PUT("/:id/reaction", engagementHandler.React)
+			postsJWT.DELETE("/:id/reaction", engagementHandler.Unreact)
+			postsJWT.PUT("/:id/bookmark", engagementHandler.Bookmark)
+			postsJWT.DELETE("/:id/bookmark", engagementHandler.Unbookmark)
+			postsJWT.GET("/:id/stats", analyticsHandler.Stats)
+			postsJWT.GET("/:id/related", relatedHandler.Related)
+			postsJWT.GET("/:id/contributors", postHandler.ListContributors)
+			postsJWT.POST("/:id/contributors", postHandler.InviteContributor)
+			postsJWT.DELETE("/:id/contributors/:user_id", postHandler.RemoveContributor)
+			postsJWT.GET("/:id/review", postHandler.GetReview)
+			postsJWT.POST("/:id/review/submit", postHandler.SubmitForReview)
+			postsJWT.PUT("/:id/review/reviewer", postHandler.AssignReviewer)
+			postsJWT.POST("/:id/review/approve", postHandler.Approve)
+			postsJWT.POST("/:id/review/request-changes", postHandler.RequestChanges)
+			postsJWT.POST("/:id/review/publish", postHandler.Publish)
+			postsJWT.POST("/:id/review/comments", postHandler.CommentOnReview)
 		}
 	}
 }
 
+// setupFeedRoutes sets up the RSS, Atom and JSON feeds of recent posts
+func setupFeedRoutes(router *gin.Engine, feedHandler *httpHandler.FeedHandler) {
+	router.GET("/feed.rss", feedHandler.RSS)
+	router.GET("/feed.atom", feedHandler.Atom)
+	router.GET("/feed.json", feedHandler.JSON)
+}
+
+// setupSitemapRoutes sets up the sitemap and the numbered sitemaps of its index
+func setupSitemapRoutes(router *gin.Engine, sitemapHandler *httpHandler.SitemapHandler) {
+	router.GET("/sitemap.xml", sitemapHandler.Sitemap)
+	router.GET("/sitemaps/:page", sitemapHandler.Page)
+}
+
 // setupAdminRoutes sets up admin routes with basic auth
-func setupAdminRoutes(router *gin.Engine) {
+func setupAdminRoutes(router *gin.Engine, analyticsHandler *httpHandler.AnalyticsHandler, moderationHandler *httpHandler.ModerationHandler) {
 	authorized := router.Group("/admin", gin.BasicAuth(gin.Accounts{
 		"username1": "password1",
 		"username2": "password2",
@@ -100,5 +268,9 @@ func setupAdminRoutes(router *gin.Engine) {
 	}))
 	{
 		authorized.GET("/dashboard", httpHandler.Dashboard)
+		authorized.GET("/analytics/top-posts", analyticsHandler.TopPosts)
+		authorized.GET("/moderation", moderationHandler.List)
+		authorized.POST("/moderation/:id/approve", moderationHandler.Approve)
+		authorized.POST("/moderation/:id/reject", moderationHandler.Reject)
 	}
 }
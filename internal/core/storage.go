@@ -0,0 +1,43 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/yakuter/ugin/internal/config"
+	"github.com/yakuter/ugin/internal/service"
+	"github.com/yakuter/ugin/pkg/blob"
+)
+
+// newBlobStore creates the attachment store selected by the storage driver
+func newBlobStore(cfg config.StorageConfig) (service.BlobStore, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return blob.NewLocalStore(cfg.LocalDir)
+	case "s3":
+		return blob.NewS3Store(blob.S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %s", cfg.Driver)
+	}
+}
+
+// newAttachmentConfig builds the attachment service configuration
+func newAttachmentConfig(cfg config.StorageConfig) *service.AttachmentConfig {
+	return &service.AttachmentConfig{
+		MaxSize:      cfg.MaxUploadSize,
+		AllowedTypes: cfg.AllowedTypes,
+		URLTTL:       cfg.URLTTL,
+		SigningKey:   []byte(cfg.SigningKey),
+		DownloadPath: "/api/v1/attachments/:id/download",
+		Derivatives: service.DerivativeConfig{
+			Widths:  cfg.Derivatives.Widths,
+			Formats: cfg.Derivatives.Formats,
+			Quality: cfg.Derivatives.Quality,
+		},
+	}
+}
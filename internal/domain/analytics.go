@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// PostDailyViews counts the views of a post on one UTC day
+type PostDailyViews struct {
+	ID     uint      `json:"-" gorm:"primarykey"`
+	PostID uint      `json:"-" gorm:"uniqueIndex:idx_post_daily_views;not null"`
+	Day    time.Time `json:"-" gorm:"uniqueIndex:idx_post_daily_views;index;not null"`
+	Views  int64     `json:"-" gorm:"not null;default:0"`
+}
+
+// TableName overrides the table name for PostDailyViews
+func (PostDailyViews) TableName() string {
+	return "post_daily_views"
+}
+
+// DailyViews is the number of views of a day in a time series
+type DailyViews struct {
+	Date  string `json:"date" example:"2025-03-01"`
+	Views int64  `json:"views" example:"42"`
+}
+
+// PostStats summarizes the views of a post
+type PostStats struct {
+	PostID string `json:"post_id" example:"0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e"`
+	// TotalViews counts every recorded view of the post
+	TotalViews int64 `json:"total_views" example:"1200"`
+	// Views counts the views within the requested range
+	Views int64 `json:"views" example:"42"`
+	// Daily lists the views of every day in the range, oldest first
+	Daily []DailyViews `json:"daily"`
+}
+
+// PostViewCount is a post with its number of views in a time range
+type PostViewCount struct {
+	PostID string `json:"post_id" example:"0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e"`
+	Name   string `json:"name" example:"Hello World"`
+	Slug   string `json:"slug" example:"hello-world"`
+	Views  int64  `json:"views" example:"42"`
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Attachment kinds
+const (
+	AttachmentFile  = "file"
+	AttachmentCover = "cover"
+	// AttachmentDerivative is a resized copy of a cover image
+	AttachmentDerivative = "derivative"
+)
+
+// Attachment is an uploaded file bound to a post. A post has any number of
+// file attachments and at most one cover image, which comes with resized
+// derivatives.
+type Attachment struct {
+	ID          uint      `json:"-" gorm:"primarykey"`
+	PublicID    string    `json:"id" gorm:"type:varchar(36);uniqueIndex" example:"0190a5f2-7c21-7b22-8c3d-7e8f9a0b1c2d"`
+	CreatedAt   time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	PostID      uint      `json:"-" gorm:"index;not null"`
+	ParentID    *uint     `json:"-" gorm:"index"`
+	Kind        string    `json:"kind" gorm:"type:varchar(20);not null" example:"file"`
+	Filename    string    `json:"filename" gorm:"type:varchar(255);not null" example:"slides.pdf"`
+	ContentType string    `json:"content_type" gorm:"type:varchar(100);not null" example:"application/pdf"`
+	Size        int64     `json:"size" gorm:"not null" example:"482133"`
+	SHA256      string    `json:"sha256" gorm:"type:varchar(64)" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	StorageKey  string    `json:"-" gorm:"type:varchar(255);not null"`
+	Width       int       `json:"width,omitempty" example:"640"`
+	Height      int       `json:"height,omitempty" example:"360"`
+
+	// Derivatives are the resized copies of a cover image, smallest first
+	Derivatives []*Attachment `json:"derivatives,omitempty" gorm:"-"`
+
+	// URL is a signed download link, valid until URLExpiresAt
+	URL          string     `json:"url,omitempty" gorm:"-"`
+	URLExpiresAt *time.Time `json:"url_expires_at,omitempty" gorm:"-"`
+}
+
+// TableName overrides the table name for Attachment
+func (Attachment) TableName() string {
+	return "attachments"
+}
+
+// BeforeCreate assigns the public ID of a new attachment
+func (a *Attachment) BeforeCreate(tx *gorm.DB) error {
+	return assignPublicID(&a.PublicID)
+}
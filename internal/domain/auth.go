@@ -22,5 +22,7 @@ type TokenClaims struct {
 	Email    string `json:"email"`
 	UserUUID string `json:"user_uuid"`
 	UUID     string `json:"uuid"`
+	// Admin is set for users listed as admins in the configuration
+	Admin bool `json:"admin"`
 }
 
@@ -0,0 +1,30 @@
+package domain
+
+// Bulk operation kinds
+const (
+	BulkCreate = "create"
+	BulkUpdate = "update"
+	BulkDelete = "delete"
+)
+
+// BulkOperation is one create, update or delete of a bulk request
+type BulkOperation struct {
+	Op string `json:"op" example:"update"`
+	// ID is the public ID or slug of the post to update or delete
+	ID string `json:"id,omitempty" example:"getting-started-with-go"`
+	// Version, when set, must match the stored version of the post
+	Version uint `json:"version,omitempty" example:"1"`
+	// Post holds the new content for create and update
+	Post *Post `json:"post,omitempty"`
+}
+
+// BulkResult is the outcome of one bulk operation
+type BulkResult struct {
+	Index   int    `json:"index" example:"0"`
+	Op      string `json:"op" example:"update"`
+	ID      string `json:"id,omitempty" example:"0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e"`
+	Version uint   `json:"version,omitempty" example:"2"`
+	Status  int    `json:"status" example:"200"`
+	Error   string `json:"error,omitempty"`
+	Err     error  `json:"-"`
+}
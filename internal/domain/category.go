@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Category is a node in the tree of categories posts are filed under
+type Category struct {
+	ID          uint      `json:"-" gorm:"primarykey"`
+	PublicID    string    `json:"id" gorm:"type:varchar(36);uniqueIndex" example:"0190a5f2-7c21-7c3d-8a4b-5c6d7e8f9a0b"`
+	CreatedAt   time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	ParentID    *uint     `json:"-" gorm:"index"`
+	Name        string    `json:"name" gorm:"type:varchar(255);not null" example:"Programming"`
+	Slug        string    `json:"slug" gorm:"type:varchar(255);uniqueIndex" example:"programming"`
+	Description string    `json:"description" gorm:"type:text" example:"Articles about writing software"`
+	// Path is the materialized path of the category: the IDs from the root
+	// down to the category itself, as in "/1/4/9/". The category's subtree
+	// is every category whose path starts with its own.
+	Path string `json:"-" gorm:"type:varchar(1024);index;not null"`
+	// Depth is 0 for root categories and grows by one per level
+	Depth int `json:"depth" gorm:"not null;default:0" example:"0"`
+
+	// ParentPublicID is read along with the category
+	ParentPublicID string      `json:"parent_id,omitempty" gorm:"->;-:migration"`
+	Children       []*Category `json:"children,omitempty" gorm:"-"`
+}
+
+// TableName overrides the table name for Category
+func (Category) TableName() string {
+	return "categories"
+}
+
+// BeforeCreate assigns the public ID of a new category
+func (c *Category) BeforeCreate(tx *gorm.DB) error {
+	return assignPublicID(&c.PublicID)
+}
+
+// ChildPath returns the materialized path of a child of the category with the given ID
+func (c *Category) ChildPath(id uint) string {
+	path := c.Path
+	if path == "" {
+		path = "/"
+	}
+	return path + strconv.FormatUint(uint64(id), 10) + "/"
+}
+
+// Contains reports whether other is the category itself or one of its descendants
+func (c *Category) Contains(other *Category) bool {
+	return strings.HasPrefix(other.Path, c.Path)
+}
+
+// CreateCategoryRequest represents the request body for creating a category
+type CreateCategoryRequest struct {
+	Name        string `json:"name" binding:"required" example:"Programming"`
+	Description string `json:"description" example:"Articles about writing software"`
+	// ParentID is the public ID or slug of the parent; root categories have none
+	ParentID string `json:"parent_id,omitempty" example:"0190a5f2-7c21-7c3d-8a4b-5c6d7e8f9a0b"`
+}
+
+// UpdateCategoryRequest represents the request body for renaming a category
+type UpdateCategoryRequest struct {
+	Name        string `json:"name" binding:"required" example:"Software Development"`
+	Description string `json:"description" example:"Articles about writing software"`
+}
+
+// MoveCategoryRequest represents the request body for moving a category and its subtree
+type MoveCategoryRequest struct {
+	// ParentID is the public ID or slug of the new parent; empty makes the category a root
+	ParentID string `json:"parent_id" example:"0190a5f2-7c21-7c3d-8a4b-5c6d7e8f9a0b"`
+}
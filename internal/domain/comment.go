@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Comment moderation states
+const (
+	CommentPending  = "pending"
+	CommentApproved = "approved"
+	CommentRejected = "rejected"
+)
+
+// Comment is a reader comment on a post, optionally replying to another comment
+type Comment struct {
+	ID        uint           `json:"-" gorm:"primarykey"`
+	PublicID  string         `json:"id" gorm:"type:varchar(36);uniqueIndex" example:"0190a5f2-7c20-7a11-9b3c-6d7e8f9a0b1c"`
+	CreatedAt time.Time      `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt time.Time      `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	PostID    uint           `json:"-" gorm:"index;not null"`
+	ParentID  *uint          `json:"-" gorm:"index"`
+	// Depth is 0 for comments on the post itself and grows by one per reply level
+	Depth  int    `json:"depth" gorm:"not null;default:0" example:"0"`
+	Author string `json:"author" gorm:"type:varchar(255);not null" example:"user@example.com"`
+	Body   string `json:"body" gorm:"type:text;not null" example:"Great article!"`
+	Status string `json:"status" gorm:"type:varchar(20);index;not null" example:"approved"`
+
+	// PostPublicID and ParentPublicID are read along with the comment
+	PostPublicID   string     `json:"post_id" gorm:"->;-:migration" example:"0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e"`
+	ParentPublicID string     `json:"parent_id,omitempty" gorm:"->;-:migration"`
+	Replies        []*Comment `json:"replies,omitempty" gorm:"-"`
+}
+
+// TableName overrides the table name for Comment
+func (Comment) TableName() string {
+	return "comments"
+}
+
+// BeforeCreate assigns the public ID of a new comment
+func (c *Comment) BeforeCreate(tx *gorm.DB) error {
+	return assignPublicID(&c.PublicID)
+}
+
+// CreateCommentRequest represents the request body for creating a comment
+type CreateCommentRequest struct {
+	// ParentID is the public ID of the comment being replied to
+	ParentID string `json:"parent_id,omitempty" example:"0190a5f2-7c20-7a11-9b3c-6d7e8f9a0b1c"`
+	// Author names anonymous commenters; signed-in users comment under their email
+	Author string `json:"author,omitempty" example:"Jane"`
+	Body   string `json:"body" binding:"required" example:"Great article!"`
+}
+
+// UpdateCommentRequest represents the request body for editing or moderating a comment
+type UpdateCommentRequest struct {
+	Body   string `json:"body,omitempty" example:"Great article, thanks!"`
+	Status string `json:"status,omitempty" binding:"omitempty,oneof=pending approved rejected" example:"approved"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// Contributor roles
+const (
+	// RoleOwner may edit and delete the post and manage its contributors
+	RoleOwner = "owner"
+	// RoleEditor may edit the post
+	RoleEditor = "editor"
+	// RoleReviewer may read the post's contributors but not change the post
+	RoleReviewer = "reviewer"
+)
+
+// PostContributor gives a user a role on a post
+type PostContributor struct {
+	ID        uint      `json:"-" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	PostID    uint      `json:"-" gorm:"uniqueIndex:idx_post_contributors;not null"`
+	UserID    uint      `json:"-" gorm:"uniqueIndex:idx_post_contributors;index;not null"`
+	Role      string    `json:"role" gorm:"type:varchar(20);not null" enums:"owner,editor,reviewer" example:"editor"`
+	// InvitedBy is the email of the owner who invited the contributor; the
+	// author of the post has none
+	InvitedBy string `json:"invited_by,omitempty" gorm:"type:varchar(255)" example:"owner@example.com"`
+
+	// UserPublicID and Email are read along with the contributor
+	UserPublicID string `json:"user_id" gorm:"->;-:migration" example:"0190a5f2-7c40-7b2c-8d3e-4f5a6b7c8d9e"`
+	Email        string `json:"email" gorm:"->;-:migration" example:"editor@example.com"`
+}
+
+// TableName overrides the table name for PostContributor
+func (PostContributor) TableName() string {
+	return "post_contributors"
+}
+
+// InviteContributorRequest represents the request body for inviting a user to a post
+type InviteContributorRequest struct {
+	Email string `json:"email" binding:"required,email" example:"editor@example.com"`
+	Role  string `json:"role" binding:"required" enums:"owner,editor,reviewer" example:"editor"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// Reaction is the emoji a user reacted to a post with; each user has at
+// most one reaction per post
+type Reaction struct {
+	ID        uint      `json:"-" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	UserID    uint      `json:"-" gorm:"uniqueIndex:idx_reaction_user_post;not null"`
+	PostID    uint      `json:"-" gorm:"uniqueIndex:idx_reaction_user_post;index;not null"`
+	Emoji     string    `json:"emoji" gorm:"type:varchar(32);not null" example:"👍"`
+
+	// PostPublicID is filled in when the reaction is saved
+	PostPublicID string `json:"post_id" gorm:"-" example:"0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e"`
+}
+
+// TableName overrides the table name for Reaction
+func (Reaction) TableName() string {
+	return "reactions"
+}
+
+// Bookmark records that a user saved a post for later
+type Bookmark struct {
+	ID        uint      `json:"-" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UserID    uint      `json:"-" gorm:"uniqueIndex:idx_bookmark_user_post;not null"`
+	PostID    uint      `json:"-" gorm:"uniqueIndex:idx_bookmark_user_post;index;not null"`
+
+	// PostPublicID is filled in when the bookmark is saved
+	PostPublicID string `json:"post_id" gorm:"-" example:"0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e"`
+}
+
+// TableName overrides the table name for Bookmark
+func (Bookmark) TableName() string {
+	return "bookmarks"
+}
+
+// ReactionRequest represents the request body for reacting to a post
+type ReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required" example:"👍"`
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Moderation queue states
+const (
+	ModerationPending  = "pending"
+	ModerationApproved = "approved"
+	ModerationRejected = "rejected"
+)
+
+// Kinds of content held for moderation
+const (
+	ModerationPost    = "post"
+	ModerationComment = "comment"
+)
+
+// ModerationItem is a post or comment the content moderator flagged, held
+// until a moderator approves or rejects it
+type ModerationItem struct {
+	ID        uint      `json:"-" gorm:"primarykey"`
+	PublicID  string    `json:"id" gorm:"type:varchar(36);uniqueIndex" example:"0190a5f2-7c60-7e2f-9a3b-4c5d6e7f8a9b"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	Kind      string    `json:"kind" gorm:"type:varchar(20);not null;index:idx_moderation_target" enums:"post,comment" example:"comment"`
+	TargetID  uint      `json:"-" gorm:"not null;index:idx_moderation_target"`
+	// TargetPublicID is the public ID of the held post or comment
+	TargetPublicID string `json:"target_id" gorm:"type:varchar(36);not null" example:"0190a5f2-7c20-7a11-9b3c-6d7e8f9a0b1c"`
+	// Excerpt is the start of the flagged content
+	Excerpt string   `json:"excerpt" gorm:"type:text" example:"Best casino bonuses at http://spam.example"`
+	Reasons []string `json:"reasons" gorm:"type:text;serializer:json"`
+	// ReleaseStatus is the status a held post returns to when approved
+	ReleaseStatus string     `json:"-" gorm:"type:varchar(20)"`
+	Status        string     `json:"status" gorm:"type:varchar(20);not null;index" enums:"pending,approved,rejected" example:"pending"`
+	ModeratedBy   string     `json:"moderated_by,omitempty" gorm:"type:varchar(255)" example:"username1"`
+	ModeratedAt   *time.Time `json:"moderated_at,omitempty" example:"2023-01-01T00:00:00Z"`
+}
+
+// TableName overrides the table name for ModerationItem
+func (ModerationItem) TableName() string {
+	return "moderation_items"
+}
+
+// BeforeCreate assigns the public ID of a new moderation item
+func (m *ModerationItem) BeforeCreate(tx *gorm.DB) error {
+	return assignPublicID(&m.PublicID)
+}
@@ -1,27 +1,90 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"github.com/yakuter/ugin/pkg/uid"
+	"gorm.io/gorm"
+)
+
+// Description formats
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
 
 // Post represents a blog post or article
 type Post struct {
-	ID          uint       `json:"id" gorm:"primarykey" example:"1"`
-	CreatedAt   time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
-	UpdatedAt   time.Time  `json:"updated_at" example:"2023-01-01T00:00:00Z"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty" gorm:"index" swaggerignore:"true"`
-	Name        string     `json:"name" gorm:"type:varchar(255);not null" example:"Getting Started with Go"`
-	Description string     `json:"description" gorm:"type:text" example:"A comprehensive guide to learning Go programming language"`
-	Tags        []Tag      `json:"tags,omitempty" gorm:"foreignKey:PostID"`
+	ID              uint           `json:"-" gorm:"primarykey"`
+	PublicID        string         `json:"id" gorm:"type:varchar(36);uniqueIndex" example:"0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e"`
+	CreatedAt       time.Time      `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt       time.Time      `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+	Name            string         `json:"name" gorm:"type:varchar(255);not null" example:"Getting Started with Go"`
+	Slug            string         `json:"slug" gorm:"type:varchar(255);uniqueIndex" example:"getting-started-with-go"`
+	Description     string         `json:"description" gorm:"type:text" example:"A comprehensive guide to learning Go programming language"`
+	Format          string         `json:"format" gorm:"type:varchar(20);not null;default:plain" enums:"plain,markdown,html" example:"markdown"`
+	DescriptionHTML string         `json:"description_html" gorm:"type:text" example:"<p>A comprehensive guide to learning Go programming language</p>"`
+	Version         uint           `json:"version" gorm:"not null;default:1" example:"1"`
+	CategoryID      *uint          `json:"-" gorm:"index"`
+	Tags            []Tag          `json:"tags,omitempty" gorm:"foreignKey:PostID"`
+
+	// Status is where the post stands in review, or held for moderation.
+	// Only published posts are listed and syndicated.
+	Status string `json:"status" gorm:"type:varchar(20);not null;default:published;index" enums:"draft,in_review,changes_requested,approved,published,held" example:"published"`
+	// Reviewer is the email of the user assigned to review the post
+	Reviewer string `json:"reviewer,omitempty" gorm:"type:varchar(255)" example:"reviewer@example.com"`
+
+	// CategoryPublicID is the public ID of the post's primary category. It
+	// is given by the public ID or slug on writes and filled in on reads.
+	CategoryPublicID string `json:"category_id,omitempty" gorm:"-" example:"0190a5f2-7c21-7c3d-8a4b-5c6d7e8f9a0b"`
+	// Category is the post's primary category, filled in when the post is read
+	Category *Category `json:"category,omitempty" gorm:"-"`
+
+	// CommentCount is the number of approved comments, filled in when the post is read
+	CommentCount int64 `json:"comment_count,omitempty" gorm:"-" example:"3"`
+	// Reactions counts the reactions to the post by emoji, filled in when the post is read
+	Reactions map[string]int64 `json:"reactions,omitempty" gorm:"-"`
+	// BookmarkCount is the number of users who bookmarked the post, filled in when the post is read
+	BookmarkCount int64 `json:"bookmark_count,omitempty" gorm:"-" example:"5"`
+	// Cover is the post's cover image, filled in when the post is read
+	Cover *Attachment `json:"cover,omitempty" gorm:"-"`
+	// Series places the post within the series it belongs to, filled in
+	// when a single post is read
+	Series *SeriesNavigation `json:"series,omitempty" gorm:"-"`
+
+	// Locale is the locale the name and description are given in, set when
+	// the post is read in a negotiated locale
+	Locale string `json:"locale,omitempty" gorm:"-" example:"en"`
+	// Translation is the translation applied to the post, if any
+	Translation *PostTranslation `json:"-" gorm:"-"`
+}
+
+// TrashedPost is a post listed in the trash with the time it was deleted
+type TrashedPost struct {
+	*Post
+	DeletedAt *time.Time `json:"deleted_at" example:"2023-01-02T00:00:00Z"`
 }
 
 // Tag represents a tag associated with a post
 type Tag struct {
-	ID          uint       `json:"id" gorm:"primarykey" example:"1"`
-	CreatedAt   time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
-	UpdatedAt   time.Time  `json:"updated_at" example:"2023-01-01T00:00:00Z"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty" gorm:"index" swaggerignore:"true"`
-	PostID      uint       `json:"post_id" gorm:"index;not null" example:"1"`
-	Name        string     `json:"name" gorm:"type:varchar(255);not null" example:"golang"`
-	Description string     `json:"description" gorm:"type:text" example:"Go programming language"`
+	ID          uint           `json:"-" gorm:"primarykey"`
+	PublicID    string         `json:"id" gorm:"type:varchar(36);uniqueIndex" example:"0190a5f2-7c1f-7d01-8e2f-5a6b7c8d9e0f"`
+	CreatedAt   time.Time      `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt   time.Time      `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	PostID      uint           `json:"-" gorm:"index;not null"`
+	Name        string         `json:"name" gorm:"type:varchar(255);not null" example:"golang"`
+	Description string         `json:"description" gorm:"type:text" example:"Go programming language"`
+}
+
+// PostSlug records a slug a post used before it was renamed so old links keep working
+type PostSlug struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	PostID    uint      `json:"post_id" gorm:"index;not null"`
+	Slug      string    `json:"slug" gorm:"type:varchar(255);uniqueIndex;not null"`
 }
 
 // TableName overrides the table name for Post
@@ -29,16 +92,42 @@ func (Post) TableName() string {
 	return "posts"
 }
 
+// BeforeCreate assigns the public ID of a new post
+func (p *Post) BeforeCreate(tx *gorm.DB) error {
+	return assignPublicID(&p.PublicID)
+}
+
+// BeforeCreate assigns the public ID of a new tag
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	return assignPublicID(&t.PublicID)
+}
+
+// TableName overrides the table name for PostSlug
+func (PostSlug) TableName() string {
+	return "post_slugs"
+}
+
 // TableName overrides the table name for Tag
 func (Tag) TableName() string {
 	return "tags"
 }
 
+// ArchiveMonth is the number of posts created in a month
+type ArchiveMonth struct {
+	Year  int   `json:"year" example:"2023"`
+	Month int   `json:"month" example:"1"`
+	Count int64 `json:"count" example:"12"`
+}
+
 // CreatePostRequest represents the request body for creating a post
 type CreatePostRequest struct {
-	Name        string           `json:"name" binding:"required" example:"Getting Started with Go"`
-	Description string           `json:"description" example:"A comprehensive guide to learning Go programming language"`
-	Tags        []CreateTagRequest `json:"tags,omitempty"`
+	Name        string             `json:"name" binding:"required" example:"Getting Started with Go"`
+	Description string             `json:"description" example:"A comprehensive guide to learning Go programming language"`
+	Format      string             `json:"format,omitempty" enums:"plain,markdown,html" example:"markdown"`
+	CategoryID  string             `json:"category_id,omitempty" example:"programming"`
+	Tags        []CreateTagRequest `json:"tags,omitempty" binding:"dive"`
+	// Status is published unless the post is created as a draft for review
+	Status string `json:"status,omitempty" enums:"draft,published" example:"draft"`
 }
 
 // CreateTagRequest represents a tag in the create request
@@ -47,3 +136,16 @@ type CreateTagRequest struct {
 	Description string `json:"description" example:"Go programming language"`
 }
 
+// assignPublicID generates a public ID unless one is already set
+func assignPublicID(id *string) error {
+	if *id != "" {
+		return nil
+	}
+
+	generated, err := uid.NewV7()
+	if err != nil {
+		return err
+	}
+	*id = generated
+	return nil
+}
@@ -0,0 +1,12 @@
+package domain
+
+// RelatedPost is a post found similar to another one
+type RelatedPost struct {
+	PostID string `json:"post_id" example:"0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e"`
+	Name   string `json:"name" example:"Hello World"`
+	Slug   string `json:"slug" example:"hello-world"`
+	// Score combines shared tags and text similarity, from 0 to 1
+	Score float64 `json:"score" example:"0.42"`
+	// SharedTags lists the tags both posts have
+	SharedTags []string `json:"shared_tags,omitempty"`
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Post review states. Posts are published unless created as drafts, which
+// go through review before they are published.
+const (
+	PostDraft            = "draft"
+	PostInReview         = "in_review"
+	PostChangesRequested = "changes_requested"
+	PostApproved         = "approved"
+	PostPublished        = "published"
+	// PostHeld posts were flagged by the content moderator and wait for a
+	// moderator to approve them
+	PostHeld = "held"
+)
+
+// Review actions recorded in a post's review history
+const (
+	ReviewSubmit         = "submit"
+	ReviewAssign         = "assign"
+	ReviewApprove        = "approve"
+	ReviewRequestChanges = "request_changes"
+	ReviewPublish        = "publish"
+	ReviewComment        = "comment"
+)
+
+// PostReview is one step in the review of a post: a change of its status
+// or reviewer, or a comment left along the way
+type PostReview struct {
+	ID        uint      `json:"-" gorm:"primarykey"`
+	PublicID  string    `json:"id" gorm:"type:varchar(36);uniqueIndex" example:"0190a5f2-7c50-7d1e-8f2a-3b4c5d6e7f8a"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	PostID    uint      `json:"-" gorm:"index;not null"`
+	// Author is the email of the user who took the step
+	Author string `json:"author" gorm:"type:varchar(255);not null" example:"reviewer@example.com"`
+	Action string `json:"action" gorm:"type:varchar(20);not null" enums:"submit,assign,approve,request_changes,publish,comment" example:"request_changes"`
+	// Status is the status of the post after the step
+	Status string `json:"status" gorm:"type:varchar(20);not null" example:"changes_requested"`
+	// Reviewer is the reviewer assigned to the post after the step
+	Reviewer string `json:"reviewer,omitempty" gorm:"type:varchar(255)" example:"reviewer@example.com"`
+	Comment  string `json:"comment,omitempty" gorm:"type:text" example:"Please add an example for Windows."`
+}
+
+// TableName overrides the table name for PostReview
+func (PostReview) TableName() string {
+	return "post_reviews"
+}
+
+// BeforeCreate assigns the public ID of a new review step
+func (r *PostReview) BeforeCreate(tx *gorm.DB) error {
+	return assignPublicID(&r.PublicID)
+}
+
+// Review is the review state of a post along with its history
+type Review struct {
+	Status   string        `json:"status" example:"in_review"`
+	Reviewer string        `json:"reviewer,omitempty" example:"reviewer@example.com"`
+	History  []*PostReview `json:"history"`
+}
+
+// SubmitReviewRequest represents the request body for submitting a post for review
+type SubmitReviewRequest struct {
+	// Reviewer is required unless the post already has one
+	Reviewer string `json:"reviewer,omitempty" binding:"omitempty,email" example:"reviewer@example.com"`
+	Comment  string `json:"comment,omitempty" example:"Ready for a first look."`
+}
+
+// AssignReviewerRequest represents the request body for assigning a reviewer to a post
+type AssignReviewerRequest struct {
+	Reviewer string `json:"reviewer" binding:"required,email" example:"reviewer@example.com"`
+	Comment  string `json:"comment,omitempty" example:"Taking over while Alice is away."`
+}
+
+// ReviewRequest represents the request body for approving, requesting
+// changes to, publishing or commenting on a post under review
+type ReviewRequest struct {
+	Comment string `json:"comment,omitempty" example:"Looks good to me."`
+}
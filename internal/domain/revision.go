@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/yakuter/ugin/pkg/diff"
+)
+
+// PostRevision is a full snapshot of a post taken every time it is saved
+type PostRevision struct {
+	ID          uint          `json:"-" gorm:"primarykey"`
+	CreatedAt   time.Time     `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	PostID      uint          `json:"-" gorm:"uniqueIndex:idx_post_revision;not null"`
+	Revision    int           `json:"revision" gorm:"uniqueIndex:idx_post_revision;not null" example:"2"`
+	Author      string        `json:"author" gorm:"type:varchar(255)" example:"user@example.com"`
+	Name        string        `json:"name" gorm:"type:varchar(255);not null" example:"Getting Started with Go"`
+	Description string        `json:"description" gorm:"type:text" example:"A comprehensive guide to learning Go programming language"`
+	Format      string        `json:"format,omitempty" gorm:"type:varchar(20)" example:"markdown"`
+	Tags        []RevisionTag `json:"tags" gorm:"type:text;serializer:json"`
+}
+
+// RevisionTag is the snapshot of a tag stored with a revision
+type RevisionTag struct {
+	Name        string `json:"name" example:"golang"`
+	Description string `json:"description" example:"Go programming language"`
+}
+
+// TableName overrides the table name for PostRevision
+func (PostRevision) TableName() string {
+	return "post_revisions"
+}
+
+// RevisionDiff holds the line diff between two revisions of a post
+type RevisionDiff struct {
+	PostID      string      `json:"post_id" example:"0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e"`
+	From        int         `json:"from" example:"1"`
+	To          int         `json:"to" example:"2"`
+	Name        []diff.Line `json:"name"`
+	Description []diff.Line `json:"description"`
+	Tags        []diff.Line `json:"tags"`
+}
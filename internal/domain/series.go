@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Series is an ordered collection of posts meant to be read one after another
+type Series struct {
+	ID          uint      `json:"-" gorm:"primarykey"`
+	PublicID    string    `json:"id" gorm:"type:varchar(36);uniqueIndex" example:"0190a5f2-7c30-7a1b-9c2d-3e4f5a6b7c8d"`
+	CreatedAt   time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	Name        string    `json:"name" gorm:"type:varchar(255);not null" example:"Learning Go"`
+	Description string    `json:"description" gorm:"type:text" example:"From installing Go to shipping a service"`
+	// Owner is the email of the user who created the series and may change
+	// it. Series left from before they had owners are changed by admins.
+	Owner string `json:"-" gorm:"type:varchar(255);index"`
+
+	// Posts are the posts of the series in reading order, filled in when a
+	// single series is read
+	Posts []SeriesEntry `json:"posts,omitempty" gorm:"-"`
+}
+
+// SeriesPost places a post in a series. A post belongs to at most one series.
+type SeriesPost struct {
+	ID        uint      `json:"-" gorm:"primarykey"`
+	CreatedAt time.Time `json:"-"`
+	SeriesID  uint      `json:"-" gorm:"index;not null"`
+	PostID    uint      `json:"-" gorm:"uniqueIndex;not null"`
+	// Position orders the posts of a series. Positions may have gaps left
+	// by trashed posts; entries are numbered from 1 when read.
+	Position int `json:"-" gorm:"not null"`
+}
+
+// SeriesEntry is a post as listed in its series
+type SeriesEntry struct {
+	// Position is the 1-based place of the post in the series
+	Position int    `json:"position" example:"1"`
+	PostID   string `json:"post_id" example:"0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e"`
+	Name     string `json:"name" example:"Getting Started with Go"`
+	Slug     string `json:"slug" example:"getting-started-with-go"`
+	// URL links to the post, set on the navigation of a post read alone
+	URL string `json:"url,omitempty" example:"/api/v1/posts/getting-started-with-go"`
+}
+
+// SeriesNavigation places a post within its series
+type SeriesNavigation struct {
+	ID       string `json:"id" example:"0190a5f2-7c30-7a1b-9c2d-3e4f5a6b7c8d"`
+	Name     string `json:"name" example:"Learning Go"`
+	Position int    `json:"position" example:"2"`
+	Total    int    `json:"total" example:"5"`
+	// Prev and Next are the neighbours of the post, absent at either end
+	Prev *SeriesEntry `json:"prev,omitempty"`
+	Next *SeriesEntry `json:"next,omitempty"`
+}
+
+// TableName overrides the table name for Series
+func (Series) TableName() string {
+	return "series"
+}
+
+// BeforeCreate assigns the public ID of a new series
+func (s *Series) BeforeCreate(tx *gorm.DB) error {
+	return assignPublicID(&s.PublicID)
+}
+
+// TableName overrides the table name for SeriesPost
+func (SeriesPost) TableName() string {
+	return "series_posts"
+}
+
+// SeriesRequest represents the request body for creating or updating a series
+type SeriesRequest struct {
+	Name        string `json:"name" binding:"required" example:"Learning Go"`
+	Description string `json:"description" example:"From installing Go to shipping a service"`
+}
+
+// AddSeriesPostRequest represents the request body for adding a post to a series
+type AddSeriesPostRequest struct {
+	// PostID is the public ID or slug of the post
+	PostID string `json:"post_id" binding:"required" example:"getting-started-with-go"`
+	// Position is the 1-based place to insert the post at; zero appends it
+	Position int `json:"position,omitempty" example:"2"`
+}
+
+// ReorderSeriesRequest represents the new order of the posts of a series
+type ReorderSeriesRequest struct {
+	// PostIDs lists every post of the series, by public ID or slug, in the new order
+	PostIDs []string `json:"post_ids" binding:"required" example:"getting-started-with-go,go-modules"`
+}
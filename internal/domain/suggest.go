@@ -0,0 +1,29 @@
+package domain
+
+// Suggestions are the posts and tags whose names start with a query
+type Suggestions struct {
+	Posts []PostSuggestion `json:"posts"`
+	Tags  []TagSuggestion  `json:"tags"`
+}
+
+// PostSuggestion is a post suggested by its name
+type PostSuggestion struct {
+	PostID string `json:"post_id" example:"0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e"`
+	Name   string `json:"name" example:"Hello World"`
+	Slug   string `json:"slug" example:"hello-world"`
+}
+
+// TagSuggestion is a tag suggested by its name or for a draft post
+type TagSuggestion struct {
+	Name string `json:"name" example:"golang"`
+	// Posts is the number of posts with the tag
+	Posts int `json:"posts" example:"12"`
+	// Score ranks tags proposed for a draft, from 0 to 1
+	Score float64 `json:"score,omitempty" example:"0.8"`
+}
+
+// TagSuggestionRequest represents the draft post tags are proposed for
+type TagSuggestionRequest struct {
+	Name        string `json:"name" example:"Hello World"`
+	Description string `json:"description" example:"This is a draft post"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+// File formats for importing and exporting posts
+const (
+	FileFormatNDJSON = "ndjson"
+	FileFormatCSV    = "csv"
+)
+
+// ImportResult summarizes an import of posts
+type ImportResult struct {
+	DryRun bool `json:"dry_run" example:"false"`
+	// Lines is the number of records read, excluding blank lines and the CSV header
+	Lines    int           `json:"lines" example:"3"`
+	Valid    int           `json:"valid" example:"2"`
+	Imported int           `json:"imported" example:"2"`
+	Failed   int           `json:"failed" example:"1"`
+	Errors   []ImportError `json:"errors,omitempty"`
+}
+
+// ImportError describes why one line of an import was rejected
+type ImportError struct {
+	Line  int    `json:"line" example:"3"`
+	Error string `json:"error" example:"invalid input: name is required"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// PostTranslation holds the name and description of a post in another locale
+type PostTranslation struct {
+	ID              uint      `json:"-" gorm:"primarykey"`
+	CreatedAt       time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt       time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	PostID          uint      `json:"-" gorm:"uniqueIndex:idx_post_translation;not null"`
+	Locale          string    `json:"locale" gorm:"type:varchar(35);uniqueIndex:idx_post_translation;not null" example:"de-DE"`
+	Name            string    `json:"name" gorm:"type:varchar(255);not null" example:"Erste Schritte mit Go"`
+	Description     string    `json:"description" gorm:"type:text" example:"Eine umfassende Einführung in die Programmiersprache Go"`
+	Format          string    `json:"format" gorm:"type:varchar(20);not null;default:plain" enums:"plain,markdown,html" example:"markdown"`
+	DescriptionHTML string    `json:"description_html" gorm:"type:text" example:"<p>Eine umfassende Einführung in die Programmiersprache Go</p>"`
+	// Version grows by one every time the translation is saved
+	Version uint `json:"version" gorm:"not null;default:1" example:"1"`
+}
+
+// TableName overrides the table name for PostTranslation
+func (PostTranslation) TableName() string {
+	return "post_translations"
+}
+
+// PostTranslationRequest represents the request body for saving a translation
+type PostTranslationRequest struct {
+	Name        string `json:"name" binding:"required" example:"Erste Schritte mit Go"`
+	Description string `json:"description" example:"Eine umfassende Einführung in die Programmiersprache Go"`
+	// Format defaults to the format of the post
+	Format string `json:"format,omitempty" enums:"plain,markdown,html" example:"markdown"`
+}
@@ -1,10 +1,15 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // User represents a user in the system
 type User struct {
-	ID             uint       `json:"id" gorm:"primarykey"`
+	ID             uint       `json:"-" gorm:"primarykey"`
+	PublicID       string     `json:"id" gorm:"type:varchar(36);uniqueIndex"`
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
 	DeletedAt      *time.Time `json:"deleted_at,omitempty" gorm:"index"`
@@ -17,3 +22,7 @@ func (User) TableName() string {
 	return "users"
 }
 
+// BeforeCreate assigns the public ID of a new user
+func (u *User) BeforeCreate(tx *gorm.DB) error {
+	return assignPublicID(&u.PublicID)
+}
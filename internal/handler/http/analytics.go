@@ -0,0 +1,94 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+type AnalyticsHandler struct {
+	service service.AnalyticsService
+}
+
+// NewAnalyticsHandler creates a new view analytics handler
+func NewAnalyticsHandler(service service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{service: service}
+}
+
+// Stats handles GET /posts/:id/stats
+// @Summary Post view statistics
+// @Description Get the number of views of a post, in total and per day over a range ending today (UTC). Repeated views by one visitor within the dedupe window count once, and recent views appear after the next flush.
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID or slug"
+// @Param range query string false "Number of days, such as 7d" default(30d)
+// @Success 200 {object} domain.PostStats
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/stats [get]
+func (h *AnalyticsHandler) Stats(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	stats, err := h.service.Stats(ctx, c.Param("id"), c.Query("range"))
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// TopPosts handles GET /admin/analytics/top-posts
+// @Summary Most viewed posts
+// @Description Get the posts with the most views over a range ending today (UTC)
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Security BasicAuth
+// @Param range query string false "Number of days, such as 30d" default(7d)
+// @Param limit query int false "Limit" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/analytics/top-posts [get]
+func (h *AnalyticsHandler) TopPosts(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	top, err := h.service.TopPosts(ctx, c.Query("range"), limit)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": top})
+}
+
+// error writes the response for a failed analytics request
+func (h *AnalyticsHandler) error(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+	case errors.Is(err, repository.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
+
+// visitor identifies the reader of a request, by account when signed in
+// and otherwise by address and browser, so repeated views count once
+func visitor(c *gin.Context) string {
+	if actor := service.ActorFromContext(c.Request.Context()); actor != "" {
+		return "user:" + actor
+	}
+	return "anon:" + c.ClientIP() + " " + c.Request.UserAgent()
+}
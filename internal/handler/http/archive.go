@@ -0,0 +1,89 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/repository"
+)
+
+// Archive handles GET /archive
+// @Summary Post archive
+// @Description Get the number of published posts per month they were created in (UTC), newest month first. Months without posts are left out.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/archive [get]
+func (h *PostHandler) Archive(c *gin.Context) {
+	months, err := h.service.Archive(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": months})
+}
+
+// ListArchive handles GET /archive/:year/:month
+// @Summary List posts of a month
+// @Description Get the published posts created in a month (UTC), newest first. Posts are translated as for a single post.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param year path int true "Year" example(2023)
+// @Param month path int true "Month, 1 to 12" example(1)
+// @Param Limit query int false "Limit" default(25)
+// @Param Offset query int false "Offset" default(0)
+// @Param lang query string false "Preferred locale, tried before Accept-Language"
+// @Param Accept-Language header string false "Preferred locales"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/archive/{year}/{month} [get]
+func (h *PostHandler) ListArchive(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	year, yearErr := strconv.Atoi(c.Param("year"))
+	month, monthErr := strconv.Atoi(c.Param("month"))
+	if yearErr != nil || monthErr != nil || year < 1 || month < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year or month"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("Limit", "25"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("Offset", "0"))
+
+	filter := repository.ListFilter{
+		Year:   year,
+		Month:  month,
+		Limit:  limit,
+		Offset: offset,
+		Sort:   "created_at",
+		Order:  "DESC",
+	}
+
+	posts, result, err := h.service.List(ctx, filter)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	if !h.translate(c, posts...) {
+		return
+	}
+
+	h.signCovers(c, posts...)
+	c.JSON(http.StatusOK, gin.H{
+		"data":          posts,
+		"total_data":    result.Total,
+		"filtered_data": result.Filtered,
+	})
+}
@@ -0,0 +1,220 @@
+package http
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+// multipartOverhead is the room left above the file size limit for the
+// boundaries and headers of a multipart request
+const multipartOverhead = 64 << 10
+
+type AttachmentHandler struct {
+	service service.AttachmentService
+	maxSize int64
+}
+
+// NewAttachmentHandler creates a new attachment handler. Request bodies
+// larger than maxSize, plus room for the multipart framing, are cut off.
+func NewAttachmentHandler(service service.AttachmentService, maxSize int64) *AttachmentHandler {
+	return &AttachmentHandler{service: service, maxSize: maxSize}
+}
+
+// Upload handles POST /posts/:id/attachments
+// @Summary Upload attachment
+// @Description Attach a file to a post as one of its owners or editors. The content type is detected from the file itself and must be one of the allowed types.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} domain.Attachment
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/attachments [post]
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	h.upload(c, domain.AttachmentFile)
+}
+
+// Cover handles PUT /posts/:id/cover
+// @Summary Set cover image
+// @Description Upload the post's cover image as one of its owners or editors, replacing the previous one. Only JPEG, PNG, GIF and WebP images are accepted.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Param file formData file true "Cover image"
+// @Success 201 {object} domain.Attachment
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/cover [put]
+func (h *AttachmentHandler) Cover(c *gin.Context) {
+	h.upload(c, domain.AttachmentCover)
+}
+
+func (h *AttachmentHandler) upload(c *gin.Context, kind string) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+multipartOverhead)
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "send the file as multipart/form-data in the \"file\" field"})
+		return
+	}
+
+	file, filename, err := formFile(c.Request, "file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.error(c, err)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attachment, err := h.service.Upload(c.Request.Context(), c.Param("id"), kind, filename, file)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// List handles GET /posts/:id/attachments
+// @Summary List attachments
+// @Description Get the files attached to a post with signed download URLs
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID or slug"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/attachments [get]
+func (h *AttachmentHandler) List(c *gin.Context) {
+	attachments, err := h.service.List(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	noStore(c)
+	c.JSON(http.StatusOK, gin.H{
+		"data":       attachments,
+		"total_data": len(attachments),
+	})
+}
+
+// Download handles GET /attachments/:id/download
+// @Summary Download attachment
+// @Description Serve an attachment through the signed, expiring URL returned with it
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path string true "Attachment ID"
+// @Param expires query int true "Expiry as a Unix timestamp"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/attachments/{id}/download [get]
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	attachment, content, err := h.service.Open(c.Request.Context(), c.Param("id"), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "link is invalid or has expired"})
+			return
+		}
+		h.error(c, err)
+		return
+	}
+	defer content.Close()
+
+	// Images are shown in the browser, everything else is saved
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.ContentType, "image/") {
+		disposition = "inline"
+	}
+
+	// Responses may be cached until the link expires
+	maxAge := 0
+	if expires, err := strconv.ParseInt(c.Query("expires"), 10, 64); err == nil {
+		maxAge = max(int(time.Until(time.Unix(expires, 0)).Seconds()), 0)
+	}
+
+	c.Header("Cache-Control", "private, max-age="+strconv.Itoa(maxAge))
+	c.Header("ETag", `"`+attachment.SHA256+`"`)
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}),
+	})
+}
+
+// Delete handles DELETE /attachments/:id
+// @Summary Delete attachment
+// @Description Delete an attachment and its stored file as an owner or editor of its post
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Attachment ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/attachments/{id} [delete]
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "attachment deleted successfully", "id": id})
+}
+
+// noStore keeps a response carrying signed URLs out of caches, which would
+// serve it after the URLs expired
+func noStore(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+}
+
+// error writes the response for a failed attachment operation
+func (h *AttachmentHandler) error(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytesErr), errors.Is(err, service.ErrTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large", "max_size": h.maxSize})
+	case errors.Is(err, service.ErrUnsupportedType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, repository.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
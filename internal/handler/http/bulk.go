@@ -0,0 +1,99 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+// Bulk handles POST /posts/bulk
+// @Summary Bulk create, update and delete posts
+// @Description Apply an array of create, update and delete operations. In atomic mode (default) all operations are written in one transaction and any failure rolls back the whole request. In partial mode each operation succeeds or fails on its own and its outcome is reported per item.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param mode query string false "atomic or partial" default(atomic)
+// @Param operations body []domain.BulkOperation true "Operations"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/bulk [post]
+func (h *PostHandler) Bulk(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var atomic bool
+	switch mode := c.DefaultQuery("mode", "atomic"); mode {
+	case "atomic":
+		atomic = true
+	case "partial":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be atomic or partial"})
+		return
+	}
+
+	var ops []domain.BulkOperation
+	if err := c.ShouldBindJSON(&ops); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	results, err := h.service.Bulk(ctx, ops, atomic)
+	if results == nil && err != nil {
+		if errors.Is(err, repository.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	failed := 0
+	for i := range results {
+		results[i].Status, results[i].Error = bulkStatus(results[i])
+		if results[i].Err != nil {
+			failed++
+		}
+	}
+
+	status := http.StatusOK
+	if err != nil {
+		status, _ = bulkStatus(domain.BulkResult{Err: err})
+	}
+
+	c.JSON(status, gin.H{
+		"atomic":    atomic,
+		"succeeded": len(results) - failed,
+		"failed":    failed,
+		"results":   results,
+	})
+}
+
+// bulkStatus maps the outcome of one bulk operation to an HTTP status and message
+func bulkStatus(result domain.BulkResult) (int, string) {
+	err := result.Err
+	switch {
+	case err == nil && result.Op == domain.BulkCreate:
+		return http.StatusCreated, ""
+	case err == nil:
+		return http.StatusOK, ""
+	case errors.Is(err, service.ErrBulkAborted):
+		return http.StatusFailedDependency, err.Error()
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound, "post not found"
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden, "forbidden"
+	case errors.Is(err, repository.ErrConflict):
+		return http.StatusPreconditionFailed, "post has been modified"
+	case errors.Is(err, repository.ErrInvalidInput), errors.Is(err, repository.ErrAlreadyExists):
+		return http.StatusBadRequest, err.Error()
+	default:
+		return http.StatusInternalServerError, "internal server error"
+	}
+}
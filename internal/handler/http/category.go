@@ -0,0 +1,208 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+type CategoryHandler struct {
+	service service.CategoryService
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(service service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{service: service}
+}
+
+// List handles GET /categories
+// @Summary List categories
+// @Description Get the category tree: root categories with their subcategories nested under children
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/categories [get]
+func (h *CategoryHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	categories, total, err := h.service.Tree(ctx)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       categories,
+		"total_data": total,
+	})
+}
+
+// GetByID handles GET /categories/:id
+// @Summary Get category
+// @Description Get a category by ID or slug together with its subcategories
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID or slug"
+// @Success 200 {object} domain.Category
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/categories/{id} [get]
+func (h *CategoryHandler) GetByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	category, err := h.service.GetByID(ctx, c.Param("id"))
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// Create handles POST /categories
+// @Summary Create category
+// @Description Create a root category, or a subcategory when a parent is given
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param category body domain.CreateCategoryRequest true "Category object"
+// @Success 201 {object} domain.Category
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/categories [post]
+func (h *CategoryHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	category, err := h.service.Create(ctx, &req)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// Update handles PUT /categories/:id
+// @Summary Update category
+// @Description Rename a category or change its description. Renaming moves it to a new slug.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Category ID or slug"
+// @Param category body domain.UpdateCategoryRequest true "Changes"
+// @Success 200 {object} domain.Category
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/categories/{id} [put]
+func (h *CategoryHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	category, err := h.service.Update(ctx, c.Param("id"), &req)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// Move handles POST /categories/:id/move
+// @Summary Move category
+// @Description Move a category with all of its subcategories below another category, or to the root when no parent is given
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Category ID or slug"
+// @Param move body domain.MoveCategoryRequest true "New parent"
+// @Success 200 {object} domain.Category
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/categories/{id}/move [post]
+func (h *CategoryHandler) Move(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.MoveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	category, err := h.service.Move(ctx, c.Param("id"), &req)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// Delete handles DELETE /categories/:id
+// @Summary Delete category
+// @Description Delete a category that has no subcategories and no posts
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Category ID or slug"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/categories/{id} [delete]
+func (h *CategoryHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "category deleted successfully", "id": id})
+}
+
+// error writes the response for a failed category operation
+func (h *CategoryHandler) error(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+	case errors.Is(err, repository.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "category still has subcategories or posts"})
+	case errors.Is(err, repository.ErrAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+type CommentHandler struct {
+	service service.CommentService
+}
+
+// NewCommentHandler creates a new comment handler
+func NewCommentHandler(service service.CommentService) *CommentHandler {
+	return &CommentHandler{service: service}
+}
+
+// List handles GET /posts/:id/comments
+// @Summary List comments
+// @Description Get the comments on a post as threads, oldest first. Only approved comments are listed unless a signed-in user asks for another status.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID or slug"
+// @Param status query string false "approved, pending, rejected or all" default(approved)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/comments [get]
+func (h *CommentHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	comments, total, err := h.service.List(ctx, c.Param("id"), c.Query("status"))
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       comments,
+		"total_data": total,
+	})
+}
+
+// Create handles POST /posts/:id/comments
+// @Summary Create comment
+// @Description Comment on a post or reply to one of its comments. New comments wait for moderation when approval is required.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID or slug"
+// @Param comment body domain.CreateCommentRequest true "Comment object"
+// @Success 201 {object} domain.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/comments [post]
+func (h *CommentHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	comment, err := h.service.Create(ctx, c.Param("id"), &req)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// Update handles PUT /comments/:id
+// @Summary Update comment
+// @Description Edit the body of your own comment, which is moderated again, or change its moderation status as an owner or editor of the post
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Comment ID"
+// @Param comment body domain.UpdateCommentRequest true "Changes"
+// @Success 200 {object} domain.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/comments/{id} [put]
+func (h *CommentHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	comment, err := h.service.Update(ctx, c.Param("id"), &req)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// Delete handles DELETE /comments/:id
+// @Summary Delete comment
+// @Description Delete a comment together with all replies below it, as an owner or editor of the post
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Comment ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/comments/{id} [delete]
+func (h *CommentHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	deleted, err := h.service.Delete(ctx, id)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "comment deleted successfully", "id": id, "deleted": deleted})
+}
+
+// error writes the response for a failed comment operation
+func (h *CommentHandler) error(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, repository.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, repository.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+// ListContributors handles GET /posts/:id/contributors
+// @Summary List post contributors
+// @Description Get the users with a role on a post: owners, editors and reviewers
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/contributors [get]
+func (h *PostHandler) ListContributors(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	contributors, err := h.service.ListContributors(ctx, c.Param("id"))
+	if err != nil {
+		h.contributorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": contributors})
+}
+
+// InviteContributor handles POST /posts/:id/contributors
+// @Summary Invite post contributor
+// @Description Give a registered user a role on a post. Only owners may invite; editors may then edit the post, and only owners may delete it.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Param contributor body domain.InviteContributorRequest true "User and role"
+// @Success 201 {object} domain.PostContributor
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/contributors [post]
+func (h *PostHandler) InviteContributor(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.InviteContributorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	contributor, err := h.service.InviteContributor(ctx, c.Param("id"), &req)
+	if err != nil {
+		h.contributorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, contributor)
+}
+
+// RemoveContributor handles DELETE /posts/:id/contributors/:user_id
+// @Summary Remove post contributor
+// @Description Take a user off a post. Owners may remove anyone but the last owner; other contributors may only remove themselves.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Param user_id path string true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/contributors/{user_id} [delete]
+func (h *PostHandler) RemoveContributor(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.Param("user_id")
+
+	if err := h.service.RemoveContributor(ctx, c.Param("id"), userID); err != nil {
+		h.contributorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "contributor removed successfully", "user_id": userID})
+}
+
+// contributorError writes the response for a failed contributor operation
+func (h *PostHandler) contributorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrContributorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "contributor not found"})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+	case errors.Is(err, repository.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrAlreadyExists), errors.Is(err, repository.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,207 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+type EngagementHandler struct {
+	service     service.EngagementService
+	attachments service.AttachmentService
+}
+
+// NewEngagementHandler creates a new reaction and bookmark handler. Cover
+// images of bookmarked posts are given signed URLs by attachments.
+func NewEngagementHandler(service service.EngagementService, attachments service.AttachmentService) *EngagementHandler {
+	return &EngagementHandler{service: service, attachments: attachments}
+}
+
+// Reactions handles GET /reactions
+// @Summary List reaction emoji
+// @Description Get the emoji posts may be reacted to with
+// @Tags engagement
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/reactions [get]
+func (h *EngagementHandler) Reactions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.service.Reactions()})
+}
+
+// React handles PUT /posts/:id/reaction
+// @Summary React to post
+// @Description Set your reaction to a post, replacing the one you gave before. Each user has one reaction per post.
+// @Tags engagement
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Param reaction body domain.ReactionRequest true "Reaction"
+// @Success 200 {object} domain.Reaction
+// @Success 201 {object} domain.Reaction
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/reaction [put]
+func (h *EngagementHandler) React(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	reaction, created, err := h.service.React(ctx, c.Param("id"), &req)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	if created {
+		c.JSON(http.StatusCreated, reaction)
+		return
+	}
+	c.JSON(http.StatusOK, reaction)
+}
+
+// Unreact handles DELETE /posts/:id/reaction
+// @Summary Remove reaction
+// @Description Remove your reaction to a post
+// @Tags engagement
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/reaction [delete]
+func (h *EngagementHandler) Unreact(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.service.Unreact(ctx, c.Param("id")); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "reaction deleted successfully"})
+}
+
+// Bookmark handles PUT /posts/:id/bookmark
+// @Summary Bookmark post
+// @Description Save a post to your bookmarks. Bookmarking a post again keeps the original bookmark.
+// @Tags engagement
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Success 200 {object} domain.Bookmark
+// @Success 201 {object} domain.Bookmark
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/bookmark [put]
+func (h *EngagementHandler) Bookmark(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	bookmark, created, err := h.service.Bookmark(ctx, c.Param("id"))
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	if created {
+		c.JSON(http.StatusCreated, bookmark)
+		return
+	}
+	c.JSON(http.StatusOK, bookmark)
+}
+
+// Unbookmark handles DELETE /posts/:id/bookmark
+// @Summary Remove bookmark
+// @Description Remove a post from your bookmarks
+// @Tags engagement
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/bookmark [delete]
+func (h *EngagementHandler) Unbookmark(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.service.Unbookmark(ctx, c.Param("id")); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "bookmark deleted successfully"})
+}
+
+// ListBookmarks handles GET /users/me/bookmarks
+// @Summary List bookmarks
+// @Description Get the posts you bookmarked, most recently bookmarked first
+// @Tags engagement
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Limit query int false "Limit" default(25)
+// @Param Offset query int false "Offset" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/users/me/bookmarks [get]
+func (h *EngagementHandler) ListBookmarks(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("Limit", "25"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("Offset", "0"))
+
+	posts, total, err := h.service.ListBookmarks(ctx, limit, offset)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	for _, post := range posts {
+		if post.Cover != nil {
+			h.attachments.Sign(post.Cover)
+			noStore(c)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":       posts,
+		"total_data": total,
+	})
+}
+
+// error writes the response for a failed reaction or bookmark operation
+func (h *EngagementHandler) error(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrReactionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "reaction not found"})
+	case errors.Is(err, service.ErrBookmarkNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "bookmark not found"})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+	case errors.Is(err, repository.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
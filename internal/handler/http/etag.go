@@ -0,0 +1,105 @@
+package http
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+)
+
+// postETag returns the strong entity tag of the stored version of a post
+func postETag(post *domain.Post) string {
+	return fmt.Sprintf(`"%s-%d"`, post.PublicID, post.Version)
+}
+
+// representationETag returns the entity tag of a post as read from its
+// serialized body, so it also changes with the translation, counts and
+// series links shown. Tags of the post as written start with the tag of
+// its stored version and can be used as If-Match for writes; translated
+// tags cannot.
+func representationETag(post *domain.Post, body []byte) string {
+	digest := sha256.Sum256(body)
+	if post.Translation == nil {
+		return fmt.Sprintf(`"%s-%d-%x"`, post.PublicID, post.Version, digest[:8])
+	}
+	return fmt.Sprintf(`"%s-%d.%s.%d-%x"`, post.PublicID, post.Version, post.Translation.Locale, post.Translation.Version, digest[:8])
+}
+
+// versionMatches reports whether an If-Match header value names the
+// stored version of post, either by its own tag or by the tag of the post
+// as written that was read
+func versionMatches(header string, post *domain.Post) bool {
+	if etagMatches(header, postETag(post), false) {
+		return true
+	}
+
+	prefix := strings.TrimSuffix(postETag(post), `"`) + "-"
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.HasPrefix(strings.TrimSpace(candidate), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// etagMatches reports whether an If-Match or If-None-Match header value
+// matches etag. Strong comparison is used for If-Match, so weak tags never
+// match; weak comparison is used for If-None-Match.
+func etagMatches(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion evaluates the If-Match header of a write request against
+// the stored post and returns the version the write must apply to, or 0
+// when the request is unconditional. It writes the error response and
+// returns false when the request must not proceed.
+func (h *PostHandler) ifMatchVersion(c *gin.Context, id string) (uint, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		if h.requireIfMatch {
+			c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header is required"})
+			return 0, false
+		}
+		return 0, true
+	}
+
+	current, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+			return 0, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return 0, false
+	}
+
+	if !versionMatches(header, current) {
+		c.Header("ETag", postETag(current))
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "post has been modified"})
+		return 0, false
+	}
+
+	return current.Version, true
+}
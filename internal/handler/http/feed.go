@@ -0,0 +1,158 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+	"github.com/yakuter/ugin/pkg/feed"
+)
+
+// FeedConfig describes the site feeds are published for
+type FeedConfig struct {
+	Title       string
+	Description string
+	// BaseURL is the absolute URL the API is served at, without a trailing slash
+	BaseURL string
+	// Size is the number of most recent posts listed
+	Size int
+}
+
+type FeedHandler struct {
+	posts service.PostService
+	cfg   FeedConfig
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler(posts service.PostService, cfg FeedConfig) *FeedHandler {
+	return &FeedHandler{posts: posts, cfg: cfg}
+}
+
+// RSS handles GET /feed.rss
+// @Summary RSS feed
+// @Description Get the most recent posts as an RSS 2.0 feed, optionally only those with a tag
+// @Tags feeds
+// @Produce xml
+// @Param tag query string false "Tag name"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Param If-Modified-Since header string false "Date of a cached copy"
+// @Success 200 {string} string "RSS document"
+// @Success 304 "Not modified"
+// @Failure 500 {object} map[string]string
+// @Router /feed.rss [get]
+func (h *FeedHandler) RSS(c *gin.Context) {
+	h.serve(c, feed.RSSContentType, (*feed.Feed).RSS)
+}
+
+// Atom handles GET /feed.atom
+// @Summary Atom feed
+// @Description Get the most recent posts as an Atom 1.0 feed, optionally only those with a tag
+// @Tags feeds
+// @Produce xml
+// @Param tag query string false "Tag name"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Param If-Modified-Since header string false "Date of a cached copy"
+// @Success 200 {string} string "Atom document"
+// @Success 304 "Not modified"
+// @Failure 500 {object} map[string]string
+// @Router /feed.atom [get]
+func (h *FeedHandler) Atom(c *gin.Context) {
+	h.serve(c, feed.AtomContentType, (*feed.Feed).Atom)
+}
+
+// JSON handles GET /feed.json
+// @Summary JSON Feed
+// @Description Get the most recent posts as a JSON Feed 1.1 document, optionally only those with a tag
+// @Tags feeds
+// @Produce json
+// @Param tag query string false "Tag name"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Param If-Modified-Since header string false "Date of a cached copy"
+// @Success 200 {object} map[string]interface{}
+// @Success 304 "Not modified"
+// @Failure 500 {object} map[string]string
+// @Router /feed.json [get]
+func (h *FeedHandler) JSON(c *gin.Context) {
+	h.serve(c, feed.JSONContentType, (*feed.Feed).JSON)
+}
+
+// serve writes the feed of the most recent posts encoded by encode,
+// answering conditional requests with 304 Not Modified
+func (h *FeedHandler) serve(c *gin.Context, contentType string, encode func(*feed.Feed) ([]byte, error)) {
+	ctx := c.Request.Context()
+
+	posts, _, err := h.posts.List(ctx, repository.ListFilter{
+		Tag:   c.Query("tag"),
+		Limit: h.cfg.Size,
+		Sort:  "created_at",
+		Order: "DESC",
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	f := h.build(c, posts)
+	body, err := encode(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	// The tag covers everything shown, including posts dropping out of the feed
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	lastModified := f.Updated.UTC().Truncate(time.Second)
+
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+
+	if header := c.GetHeader("If-None-Match"); header != "" {
+		if etagMatches(header, etag, true) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	} else if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !lastModified.After(since) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// build describes posts as a feed served at the request's URL
+func (h *FeedHandler) build(c *gin.Context, posts []*domain.Post) *feed.Feed {
+	f := &feed.Feed{
+		Title:       h.cfg.Title,
+		Description: h.cfg.Description,
+		Link:        h.cfg.BaseURL + "/",
+		FeedURL:     h.cfg.BaseURL + c.Request.URL.RequestURI(),
+		Updated:     time.Unix(0, 0),
+		Items:       make([]feed.Item, 0, len(posts)),
+	}
+
+	for _, post := range posts {
+		item := feed.Item{
+			ID:          "urn:uuid:" + post.PublicID,
+			Title:       post.Name,
+			Link:        h.cfg.BaseURL + "/api/v1/posts/" + post.Slug,
+			ContentHTML: post.DescriptionHTML,
+			Published:   post.CreatedAt,
+			Updated:     post.UpdatedAt,
+		}
+		for _, tag := range post.Tags {
+			item.Tags = append(item.Tags, tag.Name)
+		}
+		if post.UpdatedAt.After(f.Updated) {
+			f.Updated = post.UpdatedAt
+		}
+		f.Items = append(f.Items, item)
+	}
+
+	return f
+}
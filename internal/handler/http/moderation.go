@@ -0,0 +1,113 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+type ModerationHandler struct {
+	service service.ModerationService
+}
+
+// NewModerationHandler creates a new moderation queue handler
+func NewModerationHandler(service service.ModerationService) *ModerationHandler {
+	return &ModerationHandler{service: service}
+}
+
+// List handles GET /admin/moderation
+// @Summary List moderation queue
+// @Description Get the posts and comments the content moderator held, oldest first
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BasicAuth
+// @Param status query string false "Queue status: pending, approved or rejected" default(pending)
+// @Param limit query int false "Limit" default(25)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/moderation [get]
+func (h *ModerationHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "25"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	items, total, err := h.service.List(ctx, c.Query("status"), limit, offset)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": items, "total": total})
+}
+
+// Approve handles POST /admin/moderation/:id/approve
+// @Summary Approve held content
+// @Description Show a held post or comment. Posts return to the status they were held from.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BasicAuth
+// @Param id path string true "Moderation item ID"
+// @Success 200 {object} domain.ModerationItem
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/moderation/{id}/approve [post]
+func (h *ModerationHandler) Approve(c *gin.Context) {
+	item, err := h.service.Approve(c.Request.Context(), c.Param("id"), c.MustGet(gin.AuthUserKey).(string))
+	h.decided(c, item, err)
+}
+
+// Reject handles POST /admin/moderation/:id/reject
+// @Summary Reject held content
+// @Description Move a held post to the trash or reject a held comment
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BasicAuth
+// @Param id path string true "Moderation item ID"
+// @Success 200 {object} domain.ModerationItem
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/moderation/{id}/reject [post]
+func (h *ModerationHandler) Reject(c *gin.Context) {
+	item, err := h.service.Reject(c.Request.Context(), c.Param("id"), c.MustGet(gin.AuthUserKey).(string))
+	h.decided(c, item, err)
+}
+
+// decided writes the moderation item a decision was recorded on
+func (h *ModerationHandler) decided(c *gin.Context, item *domain.ModerationItem, err error) {
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// error writes the response for a failed moderation request
+func (h *ModerationHandler) error(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "moderation item not found"})
+	case errors.Is(err, repository.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrConflict), errors.Is(err, service.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+	"github.com/yakuter/ugin/pkg/jsonpatch"
+)
+
+// Patch handles PATCH /posts/:id
+// @Summary Patch post
+// @Description Partially update a post with a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) applied to its editable fields
+// @Tags posts
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID"
+// @Param If-Match header string false "ETag of the version being patched"
+// @Param patch body object true "Merge patch object or array of JSON Patch operations"
+// @Success 200 {object} domain.Post
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id} [patch]
+func (h *PostHandler) Patch(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	contentType := c.ContentType()
+	if contentType != jsonpatch.MergePatchType && contentType != jsonpatch.JSONPatchType {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": "content type must be " + jsonpatch.MergePatchType + " or " + jsonpatch.JSONPatchType,
+		})
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+	conditional := version != 0
+
+	current, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	// Without If-Match the patch still only applies to the version it was computed against
+	if !conditional {
+		version = current.Version
+	}
+
+	doc, err := json.Marshal(postRequestFromPost(current))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	var patched []byte
+	if contentType == jsonpatch.MergePatchType {
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	} else {
+		patched, err = jsonpatch.Apply(doc, patch)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, jsonpatch.ErrTestFailed):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, jsonpatch.ErrPathNotFound):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid patch", "details": err.Error()})
+		}
+		return
+	}
+
+	// Validate the patched result the same way a full request body would be
+	var req domain.CreatePostRequest
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid patched post", "details": err.Error()})
+		return
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid patched post", "details": err.Error()})
+		return
+	}
+
+	post := postFromRequest(&req)
+	post.Version = version
+
+	if err := h.service.Update(ctx, id, post); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			if conditional {
+				c.JSON(http.StatusPreconditionFailed, gin.H{"error": "post has been modified"})
+				return
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": "post was modified concurrently, try again"})
+			return
+		}
+		if errors.Is(err, repository.ErrInvalidInput) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.Header("ETag", postETag(post))
+	c.JSON(http.StatusOK, post)
+}
+
+// postRequestFromPost returns the editable fields of a post
+func postRequestFromPost(post *domain.Post) *domain.CreatePostRequest {
+	req := &domain.CreatePostRequest{
+		Name:        post.Name,
+		Description: post.Description,
+		Format:      post.Format,
+		CategoryID:  post.CategoryPublicID,
+		Tags:        make([]domain.CreateTagRequest, 0, len(post.Tags)),
+	}
+	for _, tag := range post.Tags {
+		req.Tags = append(req.Tags, domain.CreateTagRequest{Name: tag.Name, Description: tag.Description})
+	}
+	return req
+}
+
+// postFromRequest builds a post from its editable fields
+func postFromRequest(req *domain.CreatePostRequest) *domain.Post {
+	post := &domain.Post{
+		Name:             req.Name,
+		Description:      req.Description,
+		Format:           req.Format,
+		CategoryPublicID: req.CategoryID,
+	}
+	for _, tag := range req.Tags {
+		post.Tags = append(post.Tags, domain.Tag{Name: tag.Name, Description: tag.Description})
+	}
+	return post
+}
@@ -1,33 +1,58 @@
 package http
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
+	"path"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yakuter/ugin/internal/domain"
 	"github.com/yakuter/ugin/internal/repository"
 	"github.com/yakuter/ugin/internal/service"
+	"github.com/yakuter/ugin/pkg/locale"
 )
 
 type PostHandler struct {
-	service service.PostService
+	service        service.PostService
+	attachments    service.AttachmentService
+	translations   service.TranslationService
+	analytics      service.AnalyticsService
+	negotiator     *locale.Negotiator
+	requireIfMatch bool
 }
 
-// NewPostHandler creates a new post handler
-func NewPostHandler(service service.PostService) *PostHandler {
-	return &PostHandler{service: service}
+// NewPostHandler creates a new post handler. Cover images are given signed
+// URLs by attachments, posts are read in the locale negotiator picks from
+// their translations, and views of single posts are recorded by analytics.
+// When requireIfMatch is set, updates and deletes without an If-Match
+// header are rejected.
+func NewPostHandler(service service.PostService, attachments service.AttachmentService, translations service.TranslationService, analytics service.AnalyticsService, negotiator *locale.Negotiator, requireIfMatch bool) *PostHandler {
+	return &PostHandler{
+		service:        service,
+		attachments:    attachments,
+		translations:   translations,
+		analytics:      analytics,
+		negotiator:     negotiator,
+		requireIfMatch: requireIfMatch,
+	}
 }
 
 // GetByID handles GET /posts/:id
-// @Summary Get post by ID
-// @Description Get a single post by ID
+// @Summary Get post by ID or slug
+// @Description Get a single post by public ID or slug. Slugs the post used before being renamed redirect to the current one. The response carries the raw description with its format and the rendered description_html. The name and description are translated to the locale negotiated from lang and Accept-Language, falling back to the post as written. Posts in a series carry links to the previous and next post of the series. The cover and its derivatives carry signed URLs as in lists; posts with a cover are not cached and are sent in full even when If-None-Match matches.
 // @Tags posts
 // @Accept json
 // @Produce json
-// @Param id path string true "Post ID"
+// @Param id path string true "Post ID or slug"
+// @Param lang query string false "Preferred locale, tried before Accept-Language"
+// @Param Accept-Language header string false "Preferred locales"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} domain.Post
+// @Success 304 "Not modified"
+// @Failure 301 "Moved to the post's current slug"
+// @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/posts/{id} [get]
@@ -38,28 +63,72 @@ func (h *PostHandler) GetByID(c *gin.Context) {
 	post, err := h.service.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+			h.redirectPreviousSlug(c, id)
 			return
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	c.JSON(http.StatusOK, post)
+	if !h.translate(c, post) {
+		return
+	}
+	c.Header("Content-Language", post.Locale)
+
+	// Revalidating a cached copy is a view as well
+	h.analytics.RecordView(post.ID, visitor(c))
+
+	// The ETag is taken before the cover is signed, so it only changes
+	// with the post and not with every fresh signature
+	linkSeries(c, post)
+	body, err := json.Marshal(post)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	etag := representationETag(post, body)
+	c.Header("ETag", etag)
+
+	// A cached copy of a post with a cover holds URLs that expire, so
+	// those posts are sent again in full with new ones
+	if post.Cover == nil {
+		if header := c.GetHeader("If-None-Match"); header != "" && etagMatches(header, etag, true) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+		return
+	}
+
+	h.signCovers(c, post)
+	if body, err = json.Marshal(post); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
 }
 
 // List handles GET /posts
 // @Summary List posts
-// @Description Get all posts with pagination and filtering
+// @Description Get all posts with pagination and filtering. Filtering by category includes the posts of its subcategories. Posts are translated as for a single post. Only published posts are listed unless another status is asked for, which lists the posts the signed-in user contributes to, or every post for admins.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Param Limit query int false "Limit" default(25)
 // @Param Offset query int false "Offset" default(0)
-// @Param Sort query string false "Sort field" default(id)
+// @Param Sort query string false "Sort field: id, name, created_at, updated_at or popularity" default(id)
 // @Param Order query string false "Sort order" default(DESC)
 // @Param Search query string false "Search keyword"
+// @Param Category query string false "Category ID or slug"
+// @Param Tag query string false "Tag name"
+// @Param Status query string false "Review status: draft, in_review, changes_requested, approved, published, held or all" default(published)
+// @Param lang query string false "Preferred locale, tried before Accept-Language"
+// @Param Accept-Language header string false "Preferred locales"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/posts [get]
 func (h *PostHandler) List(c *gin.Context) {
@@ -70,19 +139,39 @@ func (h *PostHandler) List(c *gin.Context) {
 	offset, _ := strconv.Atoi(c.DefaultQuery("Offset", "0"))
 
 	filter := repository.ListFilter{
-		Search: c.Query("Search"),
-		Limit:  limit,
-		Offset: offset,
-		Sort:   c.DefaultQuery("Sort", "id"),
-		Order:  c.DefaultQuery("Order", "DESC"),
+		Search:   c.Query("Search"),
+		Category: c.Query("Category"),
+		Tag:      c.Query("Tag"),
+		Status:   c.Query("Status"),
+		Limit:    limit,
+		Offset:   offset,
+		Sort:     c.DefaultQuery("Sort", "id"),
+		Order:    c.DefaultQuery("Order", "DESC"),
 	}
 
 	posts, result, err := h.service.List(ctx, filter)
 	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+			return
+		}
+		if errors.Is(err, repository.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
+	if !h.translate(c, posts...) {
+		return
+	}
+
+	h.signCovers(c, posts...)
 	c.JSON(http.StatusOK, gin.H{
 		"data":          posts,
 		"total_data":    result.Total,
@@ -92,13 +181,15 @@ func (h *PostHandler) List(c *gin.Context) {
 
 // Create handles POST /posts
 // @Summary Create post
-// @Description Create a new post
+// @Description Create a new post; the description is rendered to sanitized HTML according to its format (plain, markdown or html). The signed-in user becomes the owner of the post. Posts are published unless created with status draft.
 // @Tags posts
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param post body domain.CreatePostRequest true "Post object"
 // @Success 201 {object} domain.Post
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/posts [post]
 func (h *PostHandler) Create(c *gin.Context) {
@@ -115,10 +206,15 @@ func (h *PostHandler) Create(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
+	c.Header("ETag", postETag(&post))
 	c.JSON(http.StatusCreated, post)
 }
 
@@ -128,11 +224,16 @@ func (h *PostHandler) Create(c *gin.Context) {
 // @Tags posts
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path string true "Post ID"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Param post body domain.CreatePostRequest true "Post object"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/posts/{id} [put]
 func (h *PostHandler) Update(c *gin.Context) {
@@ -145,11 +246,25 @@ func (h *PostHandler) Update(c *gin.Context) {
 		return
 	}
 
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+	post.Version = version
+
 	if err := h.service.Update(ctx, id, &post); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
 			return
 		}
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": "post has been modified"})
+			return
+		}
 		if errors.Is(err, repository.ErrInvalidInput) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
@@ -158,6 +273,7 @@ func (h *PostHandler) Update(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", postETag(&post))
 	c.JSON(http.StatusOK, gin.H{"message": "post updated successfully"})
 }
 
@@ -167,20 +283,38 @@ func (h *PostHandler) Update(c *gin.Context) {
 // @Tags posts
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path string true "Post ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/posts/{id} [delete]
 func (h *PostHandler) Delete(c *gin.Context) {
 	ctx := c.Request.Context()
 	id := c.Param("id")
 
-	if err := h.service.Delete(ctx, id); err != nil {
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(ctx, id, version); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
 			return
 		}
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": "post has been modified"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
@@ -188,3 +322,45 @@ func (h *PostHandler) Delete(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "post deleted successfully", "id": id})
 }
 
+// redirectPreviousSlug answers a lookup that found no post, redirecting to
+// the current slug when slug is one the post used before being renamed
+func (h *PostHandler) redirectPreviousSlug(c *gin.Context, slug string) {
+	post, err := h.service.GetByPreviousSlug(c.Request.Context(), slug)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	location := path.Join(path.Dir(c.Request.URL.Path), post.Slug)
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+	c.Redirect(http.StatusMovedPermanently, location)
+}
+
+// linkSeries points the series navigation of the post at its neighbours,
+// next to the post in the request path
+func linkSeries(c *gin.Context, post *domain.Post) {
+	if post.Series == nil {
+		return
+	}
+	for _, entry := range []*domain.SeriesEntry{post.Series.Prev, post.Series.Next} {
+		if entry != nil {
+			entry.URL = path.Join(path.Dir(c.Request.URL.Path), entry.Slug)
+		}
+	}
+}
+
+// signCovers gives the cover image of each post a signed download URL
+func (h *PostHandler) signCovers(c *gin.Context, posts ...*domain.Post) {
+	for _, post := range posts {
+		if post.Cover != nil {
+			h.attachments.Sign(post.Cover)
+			noStore(c)
+		}
+	}
+}
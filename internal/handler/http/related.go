@@ -0,0 +1,53 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+type RelatedHandler struct {
+	service service.RelatedService
+}
+
+// NewRelatedHandler creates a new related post handler
+func NewRelatedHandler(service service.RelatedService) *RelatedHandler {
+	return &RelatedHandler{service: service}
+}
+
+// Related handles GET /posts/:id/related
+// @Summary Related posts
+// @Description Get the posts most related to a post, scored by the tags they share and the TF-IDF similarity of their names and descriptions
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID or slug"
+// @Param limit query int false "Limit" default(5)
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/related [get]
+func (h *RelatedHandler) Related(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+
+	related, err := h.service.Related(ctx, c.Param("id"), limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+		case errors.Is(err, repository.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": related})
+}
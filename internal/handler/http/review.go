@@ -0,0 +1,239 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+// GetReview handles GET /posts/:id/review
+// @Summary Get post review
+// @Description Get the review status of a post, its reviewer and the history of review steps and comments
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Success 200 {object} domain.Review
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/review [get]
+func (h *PostHandler) GetReview(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	review, err := h.service.GetReview(ctx, c.Param("id"))
+	if err != nil {
+		h.reviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// SubmitForReview handles POST /posts/:id/review/submit
+// @Summary Submit post for review
+// @Description Send a draft, or a post changes were requested to, to a reviewer. The reviewer must be one of the post's reviewers or owners and is required unless the post already has one. Only owners and editors may submit.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Param review body domain.SubmitReviewRequest false "Reviewer and comment"
+// @Success 200 {object} domain.Post
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/review/submit [post]
+func (h *PostHandler) SubmitForReview(c *gin.Context) {
+	var req domain.SubmitReviewRequest
+	if !bindReview(c, &req) {
+		return
+	}
+
+	post, err := h.service.SubmitForReview(c.Request.Context(), c.Param("id"), &req)
+	h.reviewed(c, post, err)
+}
+
+// AssignReviewer handles PUT /posts/:id/review/reviewer
+// @Summary Assign post reviewer
+// @Description Hand a post that is not approved yet to another of its reviewers or owners. Only owners and editors may assign reviewers.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Param review body domain.AssignReviewerRequest true "Reviewer and comment"
+// @Success 200 {object} domain.Post
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/review/reviewer [put]
+func (h *PostHandler) AssignReviewer(c *gin.Context) {
+	var req domain.AssignReviewerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	post, err := h.service.AssignReviewer(c.Request.Context(), c.Param("id"), &req)
+	h.reviewed(c, post, err)
+}
+
+// Approve handles POST /posts/:id/review/approve
+// @Summary Approve post
+// @Description Approve a post in review so its owners can publish it. Only the assigned reviewer may approve.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Param review body domain.ReviewRequest false "Comment"
+// @Success 200 {object} domain.Post
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/review/approve [post]
+func (h *PostHandler) Approve(c *gin.Context) {
+	var req domain.ReviewRequest
+	if !bindReview(c, &req) {
+		return
+	}
+
+	post, err := h.service.Approve(c.Request.Context(), c.Param("id"), &req)
+	h.reviewed(c, post, err)
+}
+
+// RequestChanges handles POST /posts/:id/review/request-changes
+// @Summary Request changes to post
+// @Description Return a post in review to its authors with a comment saying what to change. Only the assigned reviewer may request changes.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Param review body domain.ReviewRequest true "Comment"
+// @Success 200 {object} domain.Post
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/review/request-changes [post]
+func (h *PostHandler) RequestChanges(c *gin.Context) {
+	var req domain.ReviewRequest
+	if !bindReview(c, &req) {
+		return
+	}
+
+	post, err := h.service.RequestChanges(c.Request.Context(), c.Param("id"), &req)
+	h.reviewed(c, post, err)
+}
+
+// Publish handles POST /posts/:id/review/publish
+// @Summary Publish post
+// @Description Publish an approved post, listing it and adding it to feeds and the sitemap. Only owners may publish.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Param review body domain.ReviewRequest false "Comment"
+// @Success 200 {object} domain.Post
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/review/publish [post]
+func (h *PostHandler) Publish(c *gin.Context) {
+	var req domain.ReviewRequest
+	if !bindReview(c, &req) {
+		return
+	}
+
+	post, err := h.service.Publish(c.Request.Context(), c.Param("id"), &req)
+	h.reviewed(c, post, err)
+}
+
+// CommentOnReview handles POST /posts/:id/review/comments
+// @Summary Comment on post review
+// @Description Add a comment to the review history of a post. Any contributor of the post may comment.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Param review body domain.ReviewRequest true "Comment"
+// @Success 201 {object} domain.PostReview
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/review/comments [post]
+func (h *PostHandler) CommentOnReview(c *gin.Context) {
+	var req domain.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	review, err := h.service.CommentOnReview(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.reviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// bindReview binds the optional body of a review step
+func bindReview(c *gin.Context, req interface{}) bool {
+	if c.Request.ContentLength == 0 {
+		return true
+	}
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return false
+	}
+	return true
+}
+
+// reviewed writes the post a review step was taken on
+func (h *PostHandler) reviewed(c *gin.Context, post *domain.Post, err error) {
+	if err != nil {
+		h.reviewError(c, err)
+		return
+	}
+
+	c.Header("ETag", postETag(post))
+	c.JSON(http.StatusOK, post)
+}
+
+// reviewError writes the response for a failed review step
+func (h *PostHandler) reviewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+	case errors.Is(err, repository.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "post has been modified"})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
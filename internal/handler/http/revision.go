@@ -0,0 +1,151 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+// ListRevisions handles GET /posts/:id/revisions
+// @Summary List post revisions
+// @Description Get the revision history of a post, newest first. Only contributors of the post and admins may.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID"
+// @Success 200 {array} domain.PostRevision
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/revisions [get]
+func (h *PostHandler) ListRevisions(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	revs, err := h.service.ListRevisions(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, revs)
+}
+
+// DiffRevisions handles GET /posts/:id/revisions/:rev/diff
+// @Summary Diff post revisions
+// @Description Get a line diff between a revision and another one (the previous revision by default). Only contributors of the post and admins may.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID"
+// @Param rev path int true "Revision number"
+// @Param against query int false "Revision to compare against, 0 for an empty post"
+// @Success 200 {object} domain.RevisionDiff
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/revisions/{rev}/diff [get]
+func (h *PostHandler) DiffRevisions(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision number"})
+		return
+	}
+
+	against := rev - 1
+	if value := c.Query("against"); value != "" {
+		if against, err = strconv.Atoi(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid against revision number"})
+			return
+		}
+	}
+
+	d, err := h.service.DiffRevisions(ctx, id, against, rev)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "post or revision not found"})
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		if errors.Is(err, repository.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, d)
+}
+
+// RestoreRevision handles POST /posts/:id/revisions/:rev/restore
+// @Summary Restore post revision
+// @Description Restore a post to the state of a revision, recording it as a new revision
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} domain.Post
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/revisions/{rev}/restore [post]
+func (h *PostHandler) RestoreRevision(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision number"})
+		return
+	}
+
+	post, err := h.service.RestoreRevision(ctx, id, rev)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "post or revision not found"})
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		if errors.Is(err, repository.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "post was modified concurrently, try again"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.Header("ETag", postETag(post))
+	c.JSON(http.StatusOK, post)
+}
@@ -0,0 +1,266 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+type SeriesHandler struct {
+	service service.SeriesService
+}
+
+// NewSeriesHandler creates a new series handler
+func NewSeriesHandler(service service.SeriesService) *SeriesHandler {
+	return &SeriesHandler{service: service}
+}
+
+// List handles GET /series
+// @Summary List series
+// @Description Get every series ordered by name, without their posts
+// @Tags series
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/series [get]
+func (h *SeriesHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	series, err := h.service.List(ctx)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": series})
+}
+
+// GetByID handles GET /series/:id
+// @Summary Get series
+// @Description Get a series with its posts in reading order. Trashed posts are left out.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path string true "Series ID"
+// @Success 200 {object} domain.Series
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/series/{id} [get]
+func (h *SeriesHandler) GetByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	series, err := h.service.GetByID(ctx, c.Param("id"))
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// Create handles POST /series
+// @Summary Create series
+// @Description Create an empty series owned by you
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param series body domain.SeriesRequest true "Series object"
+// @Success 201 {object} domain.Series
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/series [post]
+func (h *SeriesHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	series, err := h.service.Create(ctx, &req)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, series)
+}
+
+// Update handles PUT /series/:id
+// @Summary Update series
+// @Description Rename a series or change its description. Only the owner of the series may.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Series ID"
+// @Param series body domain.SeriesRequest true "Changes"
+// @Success 200 {object} domain.Series
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/series/{id} [put]
+func (h *SeriesHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	series, err := h.service.Update(ctx, c.Param("id"), &req)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// Delete handles DELETE /series/:id
+// @Summary Delete series
+// @Description Delete a series. Its posts are kept and belong to no series afterwards. Only the owner of the series may.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Series ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/series/{id} [delete]
+func (h *SeriesHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "series deleted successfully", "id": id})
+}
+
+// AddPost handles POST /series/:id/posts
+// @Summary Add post to series
+// @Description Insert a post at a 1-based position of the series, or append it when no position is given. A post belongs to at most one series. Needs ownership of the series and the owner or editor role on the post.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Series ID"
+// @Param post body domain.AddSeriesPostRequest true "Post and position"
+// @Success 200 {object} domain.Series
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/series/{id}/posts [post]
+func (h *SeriesHandler) AddPost(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.AddSeriesPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	series, err := h.service.AddPost(ctx, c.Param("id"), &req)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// RemovePost handles DELETE /series/:id/posts/:post_id
+// @Summary Remove post from series
+// @Description Take a post out of the series; the post itself is kept. Only the owner of the series may.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Series ID"
+// @Param post_id path string true "Post ID or slug"
+// @Success 200 {object} domain.Series
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/series/{id}/posts/{post_id} [delete]
+func (h *SeriesHandler) RemovePost(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	series, err := h.service.RemovePost(ctx, c.Param("id"), c.Param("post_id"))
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// Reorder handles PUT /series/:id/order
+// @Summary Reorder series
+// @Description Set the reading order of a series by listing each of its posts once, by ID or slug. Only the owner of the series may.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Series ID"
+// @Param order body domain.ReorderSeriesRequest true "New order"
+// @Success 200 {object} domain.Series
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/series/{id}/order [put]
+func (h *SeriesHandler) Reorder(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.ReorderSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	series, err := h.service.Reorder(ctx, c.Param("id"), &req)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// error writes the response for a failed series operation
+func (h *SeriesHandler) error(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSeriesNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "series not found"})
+	case errors.Is(err, service.ErrSeriesEntryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "post is not in the series"})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+	case errors.Is(err, repository.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/service"
+	"github.com/yakuter/ugin/pkg/sitemap"
+)
+
+type SitemapHandler struct {
+	service service.SitemapService
+}
+
+// NewSitemapHandler creates a new sitemap handler
+func NewSitemapHandler(service service.SitemapService) *SitemapHandler {
+	return &SitemapHandler{service: service}
+}
+
+// Sitemap handles GET /sitemap.xml
+// @Summary Sitemap
+// @Description Get the sitemap of all posts and tag pages, or a sitemap index of numbered sitemaps when there are more than 50,000 URLs
+// @Tags sitemap
+// @Produce xml
+// @Success 200 {string} string "Sitemap or sitemap index"
+// @Failure 500 {object} map[string]string
+// @Router /sitemap.xml [get]
+func (h *SitemapHandler) Sitemap(c *gin.Context) {
+	doc, err := h.service.Sitemap(c.Request.Context())
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, sitemap.ContentType, doc)
+}
+
+// Page handles GET /sitemaps/:page
+// @Summary Sitemap page
+// @Description Get a numbered sitemap listed in the sitemap index
+// @Tags sitemap
+// @Produce xml
+// @Param page path string true "Sitemap number followed by .xml, such as 1.xml"
+// @Success 200 {string} string "Sitemap"
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /sitemaps/{page} [get]
+func (h *SitemapHandler) Page(c *gin.Context) {
+	page, err := strconv.Atoi(strings.TrimSuffix(c.Param("page"), ".xml"))
+	if err != nil || !strings.HasSuffix(c.Param("page"), ".xml") {
+		h.error(c, service.ErrSitemapNotFound)
+		return
+	}
+
+	doc, err := h.service.Page(c.Request.Context(), page)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, sitemap.ContentType, doc)
+}
+
+// error writes the response for a failed sitemap request
+func (h *SitemapHandler) error(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSitemapNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "sitemap not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+type SuggestHandler struct {
+	service service.SuggestService
+}
+
+// NewSuggestHandler creates a new suggestion handler
+func NewSuggestHandler(service service.SuggestService) *SuggestHandler {
+	return &SuggestHandler{service: service}
+}
+
+// Suggest handles GET /suggest
+// @Summary Suggest posts and tags
+// @Description Get the posts with a word of their name, and the tags with their name, starting with q, for completing a search as it is typed. Tags used by more posts come first.
+// @Tags suggest
+// @Accept json
+// @Produce json
+// @Param q query string true "Prefix"
+// @Param limit query int false "Limit of posts and of tags" default(5)
+// @Success 200 {object} domain.Suggestions
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/suggest [get]
+func (h *SuggestHandler) Suggest(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+
+	suggestions, err := h.service.Suggest(ctx, c.Query("q"), limit)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// SuggestTags handles POST /suggest/tags
+// @Summary Suggest tags for a draft
+// @Description Propose existing tags for a post being written: the tags its text mentions, then those of the posts most similar to it
+// @Tags suggest
+// @Accept json
+// @Produce json
+// @Param draft body domain.TagSuggestionRequest true "Draft post"
+// @Param limit query int false "Limit" default(5)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/suggest/tags [post]
+func (h *SuggestHandler) SuggestTags(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.TagSuggestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+
+	tags, err := h.service.SuggestTags(ctx, &req, limit)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tags})
+}
+
+// error writes the response for a failed suggestion request
+func (h *SuggestHandler) error(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+// fileContentTypes maps import/export formats to their media types
+var fileContentTypes = map[string]string{
+	domain.FileFormatNDJSON: "application/x-ndjson",
+	domain.FileFormatCSV:    "text/csv",
+}
+
+// Export handles GET /posts/export
+// @Summary Export posts
+// @Description Stream posts with their tags as NDJSON (one post per line) or CSV (tags as a JSON array column). Only published posts are exported unless another status is asked for, which exports the posts the signed-in user contributes to, or every post for admins.
+// @Tags transfer
+// @Produce json
+// @Produce text/csv
+// @Param format query string false "ndjson or csv" default(ndjson)
+// @Param Status query string false "Review status: draft, in_review, changes_requested, approved, published, held or all" default(published)
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/v1/posts/export [get]
+func (h *PostHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", domain.FileFormatNDJSON)
+	contentType, ok := fileContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be ndjson or csv"})
+		return
+	}
+
+	c.Header("Content-Type", contentType+"; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="posts.`+format+`"`)
+	c.Status(http.StatusOK)
+
+	w := bufio.NewWriter(c.Writer)
+	err := h.service.Export(c.Request.Context(), w, format, c.Query("Status"))
+	if err != nil && !c.Writer.Written() {
+		// Nothing has been sent yet, so the export can still be refused
+		c.Header("Content-Type", "")
+		c.Header("Content-Disposition", "")
+		switch {
+		case errors.Is(err, repository.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	// Once the stream has started, a failure part way only cuts it short;
+	// the service logs it
+	if err != nil {
+		_ = c.Error(err)
+	}
+	_ = w.Flush()
+}
+
+// Import handles POST /posts/import
+// @Summary Import posts
+// @Description Create posts from an NDJSON or CSV file sent as the request body or as the "file" field of a multipart form. Every line is validated on its own and rejected lines are reported with their line number. The format is taken from the format parameter, the content type or the file extension.
+// @Tags transfer
+// @Accept json
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param format query string false "ndjson or csv"
+// @Param dry_run query bool false "Only validate the file" default(false)
+// @Param file formData file false "File to import"
+// @Success 200 {object} domain.ImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/import [post]
+func (h *PostHandler) Import(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+
+	body, filename, err := importBody(c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	format := importFormat(c.Query("format"), c.ContentType(), filename)
+	if format == "" {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "cannot tell the file format; pass format=ndjson or format=csv"})
+		return
+	}
+
+	result, err := h.service.Import(c.Request.Context(), body, format, dryRun)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "result": result})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "result": result})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// importBody returns the uploaded file of a multipart request, or the
+// request body otherwise, without buffering it
+func importBody(r *http.Request) (io.Reader, string, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, "", nil
+	}
+	return formFile(r, "file")
+}
+
+// formFile streams the file sent in the named field of a multipart form
+func formFile(r *http.Request, field string) (io.Reader, string, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, "", err
+	}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, "", fmt.Errorf("multipart form has no %q field", field)
+			}
+			return nil, "", err
+		}
+		if part.FormName() == field {
+			return part, part.FileName(), nil
+		}
+	}
+}
+
+// importFormat picks the import format from the query parameter, the
+// media type of the upload or its file extension, in that order
+func importFormat(query, contentType, filename string) string {
+	if query != "" {
+		if _, ok := fileContentTypes[query]; ok {
+			return query
+		}
+		return ""
+	}
+
+	switch contentType {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return domain.FileFormatNDJSON
+	case "text/csv":
+		return domain.FileFormatCSV
+	}
+
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".ndjson", ".jsonl":
+		return domain.FileFormatNDJSON
+	case ".csv":
+		return domain.FileFormatCSV
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+// ListTranslations handles GET /posts/:id/translations
+// @Summary List post translations
+// @Description Get every translation of a post, ordered by locale
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID or slug"
+// @Success 200 {array} domain.PostTranslation
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/translations [get]
+func (h *PostHandler) ListTranslations(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	translations, err := h.translations.List(ctx, c.Param("id"))
+	if err != nil {
+		h.translationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+// PutTranslation handles PUT /posts/:id/translations/:locale
+// @Summary Save post translation
+// @Description Create or replace the translation of a post to a locale without changing the post itself. Only owners and editors of the post may translate it. The format defaults to the post's format.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Param locale path string true "BCP 47 locale, such as de or pt-BR"
+// @Param translation body domain.PostTranslationRequest true "Translation object"
+// @Success 200 {object} domain.PostTranslation
+// @Success 201 {object} domain.PostTranslation
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/translations/{locale} [put]
+func (h *PostHandler) PutTranslation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.PostTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	translation, created, err := h.translations.Put(ctx, c.Param("id"), c.Param("locale"), &req)
+	if err != nil {
+		h.translationError(c, err)
+		return
+	}
+
+	c.Header("Content-Language", translation.Locale)
+	if created {
+		c.JSON(http.StatusCreated, translation)
+		return
+	}
+	c.JSON(http.StatusOK, translation)
+}
+
+// DeleteTranslation handles DELETE /posts/:id/translations/:locale
+// @Summary Delete post translation
+// @Description Delete the translation of a post to a locale as one of its owners or editors
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID or slug"
+// @Param locale path string true "BCP 47 locale"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/translations/{locale} [delete]
+func (h *PostHandler) DeleteTranslation(c *gin.Context) {
+	ctx := c.Request.Context()
+	loc := c.Param("locale")
+
+	if err := h.translations.Delete(ctx, c.Param("id"), loc); err != nil {
+		h.translationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "translation deleted successfully", "locale": loc})
+}
+
+// translate shows posts in the locale negotiated from the lang query
+// parameter and the Accept-Language header. It writes the error response
+// and returns false when the posts cannot be translated.
+func (h *PostHandler) translate(c *gin.Context, posts ...*domain.Post) bool {
+	c.Writer.Header().Add("Vary", "Accept-Language")
+
+	chain, err := h.negotiator.Chain(c.Query("lang"), c.GetHeader("Accept-Language"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	if err := h.translations.Translate(c.Request.Context(), chain, posts...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return false
+	}
+
+	return true
+}
+
+// translationError writes the response for a failed translation operation
+func (h *PostHandler) translationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTranslationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "translation not found"})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+	case errors.Is(err, repository.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "translation has been modified"})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+// ListTrash handles GET /trash/posts
+// @Summary List deleted posts
+// @Description Get the soft-deleted posts you own that can still be restored, most recently deleted first. Admins get every deleted post.
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Limit query int false "Limit" default(25)
+// @Param Offset query int false "Offset" default(0)
+// @Param Search query string false "Search keyword"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/trash/posts [get]
+func (h *PostHandler) ListTrash(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Parse query parameters
+	limit, _ := strconv.Atoi(c.DefaultQuery("Limit", "25"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("Offset", "0"))
+
+	filter := repository.ListFilter{
+		Search: c.Query("Search"),
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	posts, result, err := h.service.ListTrash(ctx, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	// Only the trash shows when a post was deleted
+	trashed := make([]domain.TrashedPost, len(posts))
+	for i, post := range posts {
+		trashed[i] = domain.TrashedPost{Post: post, DeletedAt: &post.DeletedAt.Time}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":          trashed,
+		"total_data":    result.Total,
+		"filtered_data": result.Filtered,
+	})
+}
+
+// Restore handles POST /posts/:id/restore
+// @Summary Restore deleted post
+// @Description Restore a soft-deleted post and its tags from the trash. Only owners of the post may restore it.
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Post ID"
+// @Success 200 {object} domain.Post
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/posts/{id}/restore [post]
+func (h *PostHandler) Restore(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	post, err := h.service.Restore(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "deleted post not found"})
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
@@ -0,0 +1,86 @@
+package gormrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"gorm.io/gorm"
+)
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsRepository creates a new post view repository
+func NewAnalyticsRepository(db *gorm.DB) repository.AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+func (r *analyticsRepository) AddViews(ctx context.Context, views []*domain.PostDailyViews) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, v := range views {
+			// Incrementing in place keeps concurrent flushes from losing views
+			res := tx.Model(&domain.PostDailyViews{}).
+				Where("post_id = ? AND day = ?", v.PostID, v.Day).
+				Update("views", gorm.Expr("views + ?", v.Views))
+			if res.Error != nil {
+				return fmt.Errorf("failed to add views: %w", res.Error)
+			}
+			if res.RowsAffected > 0 {
+				continue
+			}
+
+			row := &domain.PostDailyViews{PostID: v.PostID, Day: v.Day, Views: v.Views}
+			if err := tx.Create(row).Error; err != nil {
+				return fmt.Errorf("failed to create daily views: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *analyticsRepository) TotalViews(ctx context.Context, postID uint) (int64, error) {
+	var total int64
+
+	if err := r.db.WithContext(ctx).Model(&domain.PostDailyViews{}).
+		Where("post_id = ?", postID).
+		Select("COALESCE(SUM(views), 0)").
+		Scan(&total).Error; err != nil {
+		return 0, fmt.Errorf("failed to sum views: %w", err)
+	}
+
+	return total, nil
+}
+
+func (r *analyticsRepository) DailyViews(ctx context.Context, postID uint, from, to time.Time) ([]*domain.PostDailyViews, error) {
+	var days []*domain.PostDailyViews
+
+	if err := r.db.WithContext(ctx).
+		Where("post_id = ? AND day >= ? AND day < ?", postID, from, to).
+		Order("day").
+		Find(&days).Error; err != nil {
+		return nil, fmt.Errorf("failed to list daily views: %w", err)
+	}
+
+	return days, nil
+}
+
+func (r *analyticsRepository) TopPosts(ctx context.Context, from time.Time, limit int) ([]*domain.PostViewCount, error) {
+	var top []*domain.PostViewCount
+
+	if err := r.db.WithContext(ctx).Table("post_daily_views").
+		Select("posts.public_id AS post_id, posts.name, posts.slug, SUM(post_daily_views.views) AS views").
+		Joins("JOIN posts ON posts.id = post_daily_views.post_id AND posts.deleted_at IS NULL").
+		Where("post_daily_views.day >= ?", from).
+		Group("posts.id, posts.public_id, posts.name, posts.slug").
+		Order("views DESC, posts.id").
+		Limit(limit).
+		Scan(&top).Error; err != nil {
+		return nil, fmt.Errorf("failed to list top posts: %w", err)
+	}
+
+	return top, nil
+}
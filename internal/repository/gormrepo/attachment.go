@@ -0,0 +1,123 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"gorm.io/gorm"
+)
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+// NewAttachmentRepository creates a new attachment repository
+func NewAttachmentRepository(db *gorm.DB) repository.AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) GetByID(ctx context.Context, id string) (*domain.Attachment, error) {
+	var attachment domain.Attachment
+
+	err := r.db.WithContext(ctx).Where("public_id = ?", strings.ToLower(id)).First(&attachment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	if err := loadDerivatives(r.db.WithContext(ctx), &attachment); err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) ListByPostID(ctx context.Context, postID uint, kind string) ([]*domain.Attachment, error) {
+	var attachments []*domain.Attachment
+
+	query := r.db.WithContext(ctx).Where("post_id = ? AND parent_id IS NULL", postID)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+
+	if err := query.Order("id ASC").Find(&attachments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+
+	if err := loadDerivatives(r.db.WithContext(ctx), attachments...); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (r *attachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	if err := r.db.WithContext(ctx).Create(attachment).Error; err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return nil
+}
+
+func (r *attachmentRepository) Delete(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).
+		Where("id = ? OR parent_id = ?", id, id).
+		Delete(&domain.Attachment{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	return nil
+}
+
+func (r *attachmentRepository) ListOrphaned(ctx context.Context, limit int) ([]*domain.Attachment, error) {
+	var attachments []*domain.Attachment
+
+	// Soft-deleted posts still exist, so their attachments survive until purge
+	err := r.db.WithContext(ctx).
+		Where("post_id NOT IN (?) AND parent_id IS NULL", r.db.Unscoped().Model(&domain.Post{}).Select("id")).
+		Limit(limit).
+		Find(&attachments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orphaned attachments: %w", err)
+	}
+
+	if err := loadDerivatives(r.db.WithContext(ctx), attachments...); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// loadDerivatives sets the resized copies of each attachment
+func loadDerivatives(db *gorm.DB, attachments ...*domain.Attachment) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(attachments))
+	for i, attachment := range attachments {
+		ids[i] = attachment.ID
+	}
+
+	var derivatives []*domain.Attachment
+	if err := db.Where("parent_id IN ?", ids).
+		Order("width ASC, id ASC").
+		Find(&derivatives).Error; err != nil {
+		return fmt.Errorf("failed to load derivatives: %w", err)
+	}
+
+	byParent := make(map[uint][]*domain.Attachment, len(attachments))
+	for _, derivative := range derivatives {
+		byParent[*derivative.ParentID] = append(byParent[*derivative.ParentID], derivative)
+	}
+	for _, attachment := range attachments {
+		attachment.Derivatives = byParent[attachment.ID]
+	}
+
+	return nil
+}
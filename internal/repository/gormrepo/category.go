@@ -0,0 +1,207 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/pkg/uid"
+	"gorm.io/gorm"
+)
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepository creates a new category repository
+func NewCategoryRepository(db *gorm.DB) repository.CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+// withParent selects categories together with the public ID of their parent
+func withParent(db *gorm.DB) *gorm.DB {
+	return db.Model(&domain.Category{}).
+		Select("categories.*, parents.public_id AS parent_public_id").
+		Joins("LEFT JOIN categories parents ON parents.id = categories.parent_id")
+}
+
+// getCategory returns the category with the given public ID or slug
+func getCategory(db *gorm.DB, id string) (*domain.Category, error) {
+	var category domain.Category
+
+	query := withParent(db)
+	if uid.IsValid(id) {
+		query = query.Where("categories.public_id = ?", strings.ToLower(id))
+	} else {
+		query = query.Where("categories.slug = ?", id)
+	}
+
+	if err := query.Take(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	return &category, nil
+}
+
+func (r *categoryRepository) GetByID(ctx context.Context, id string) (*domain.Category, error) {
+	return getCategory(r.db.WithContext(ctx), id)
+}
+
+func (r *categoryRepository) List(ctx context.Context) ([]*domain.Category, error) {
+	var categories []*domain.Category
+
+	if err := withParent(r.db.WithContext(ctx)).
+		Order("categories.depth ASC, categories.name ASC, categories.id ASC").
+		Find(&categories).Error; err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (r *categoryRepository) SlugTaken(ctx context.Context, slug string, categoryID uint) (bool, error) {
+	var count int64
+
+	if err := r.db.WithContext(ctx).Model(&domain.Category{}).
+		Where("slug = ? AND id <> ?", slug, categoryID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check category slug: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *categoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		parent := &domain.Category{}
+		if category.ParentID != nil {
+			if err := tx.First(parent, *category.ParentID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return repository.ErrNotFound
+				}
+				return fmt.Errorf("failed to get parent category: %w", err)
+			}
+			category.Depth = parent.Depth + 1
+		}
+
+		// The path ends in the category's own ID, which is only known once it is stored
+		category.Path = parent.Path
+		if err := tx.Create(category).Error; err != nil {
+			return fmt.Errorf("failed to create category: %w", err)
+		}
+
+		category.Path = parent.ChildPath(category.ID)
+		if err := tx.Model(category).UpdateColumn("path", category.Path).Error; err != nil {
+			return fmt.Errorf("failed to set category path: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *categoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	err := r.db.WithContext(ctx).Model(category).
+		Select("Name", "Slug", "Description", "UpdatedAt").
+		Updates(category).Error
+	if err != nil {
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+	return nil
+}
+
+func (r *categoryRepository) Move(ctx context.Context, id uint, parent *domain.Category) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var root domain.Category
+		if err := tx.First(&root, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return repository.ErrNotFound
+			}
+			return fmt.Errorf("failed to get category: %w", err)
+		}
+
+		// Read the new parent inside the transaction so its path is current
+		newParent := &domain.Category{}
+		var parentID *uint
+		if parent != nil {
+			if err := tx.First(newParent, parent.ID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return repository.ErrNotFound
+				}
+				return fmt.Errorf("failed to get parent category: %w", err)
+			}
+			if root.Contains(newParent) {
+				return fmt.Errorf("%w: a category cannot be moved below itself", repository.ErrInvalidInput)
+			}
+			parentID = &newParent.ID
+		}
+
+		var subtree []*domain.Category
+		if err := tx.Where("path LIKE ?", root.Path+"%").Find(&subtree).Error; err != nil {
+			return fmt.Errorf("failed to load subtree: %w", err)
+		}
+
+		// Every path in the subtree swaps the old prefix for the new one
+		newPath := newParent.ChildPath(root.ID)
+		depth := 0
+		if parent != nil {
+			depth = newParent.Depth + 1
+		}
+		shift := depth - root.Depth
+
+		for _, category := range subtree {
+			updates := map[string]interface{}{
+				"path":  newPath + strings.TrimPrefix(category.Path, root.Path),
+				"depth": category.Depth + shift,
+			}
+			if category.ID == root.ID {
+				updates["parent_id"] = parentID
+			}
+			if err := tx.Model(category).UpdateColumns(updates).Error; err != nil {
+				return fmt.Errorf("failed to move category: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *categoryRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.Category{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to count subcategories: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: category has subcategories", repository.ErrConflict)
+		}
+
+		if err := tx.Model(&domain.Post{}).Where("category_id = ?", id).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to count posts: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: category has posts", repository.ErrConflict)
+		}
+
+		if err := tx.Unscoped().Model(&domain.Post{}).
+			Where("category_id = ?", id).
+			UpdateColumn("category_id", nil).Error; err != nil {
+			return fmt.Errorf("failed to uncategorize trashed posts: %w", err)
+		}
+
+		res := tx.Delete(&domain.Category{}, id)
+		if res.Error != nil {
+			return fmt.Errorf("failed to delete category: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return repository.ErrNotFound
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,106 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"gorm.io/gorm"
+)
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentRepository creates a new comment repository
+func NewCommentRepository(db *gorm.DB) repository.CommentRepository {
+	return &commentRepository{db: db}
+}
+
+// withRefs selects comments together with the public IDs of their post and parent
+func withRefs(db *gorm.DB) *gorm.DB {
+	return db.Model(&domain.Comment{}).
+		Select("comments.*, posts.public_id AS post_public_id, parents.public_id AS parent_public_id").
+		Joins("JOIN posts ON posts.id = comments.post_id AND posts.deleted_at IS NULL").
+		Joins("LEFT JOIN comments parents ON parents.id = comments.parent_id")
+}
+
+func (r *commentRepository) GetByID(ctx context.Context, id string) (*domain.Comment, error) {
+	var comment domain.Comment
+
+	err := withRefs(r.db.WithContext(ctx)).
+		Where("comments.public_id = ?", strings.ToLower(id)).
+		Take(&comment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	return &comment, nil
+}
+
+func (r *commentRepository) ListByPostID(ctx context.Context, postID uint, statuses ...string) ([]*domain.Comment, error) {
+	var comments []*domain.Comment
+
+	query := withRefs(r.db.WithContext(ctx)).Where("comments.post_id = ?", postID)
+	if len(statuses) > 0 {
+		query = query.Where("comments.status IN ?", statuses)
+	}
+
+	if err := query.Order("comments.created_at ASC, comments.id ASC").Find(&comments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+func (r *commentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	if err := r.db.WithContext(ctx).Create(comment).Error; err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	return nil
+}
+
+func (r *commentRepository) Update(ctx context.Context, comment *domain.Comment) error {
+	err := r.db.WithContext(ctx).Model(comment).
+		Select("Body", "Status", "UpdatedAt").
+		Updates(comment).Error
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	return nil
+}
+
+func (r *commentRepository) Delete(ctx context.Context, id uint) (int64, error) {
+	var deleted int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Collect the thread below the comment level by level
+		ids := []uint{id}
+		for level := []uint{id}; len(level) > 0; {
+			var replies []uint
+			if err := tx.Model(&domain.Comment{}).
+				Where("parent_id IN ?", level).
+				Pluck("id", &replies).Error; err != nil {
+				return fmt.Errorf("failed to find replies: %w", err)
+			}
+			ids = append(ids, replies...)
+			level = replies
+		}
+
+		res := tx.Where("id IN ?", ids).Delete(&domain.Comment{})
+		if res.Error != nil {
+			return fmt.Errorf("failed to delete comments: %w", res.Error)
+		}
+		deleted = res.RowsAffected
+
+		return nil
+	})
+
+	return deleted, err
+}
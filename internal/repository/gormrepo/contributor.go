@@ -0,0 +1,109 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"gorm.io/gorm"
+)
+
+type contributorRepository struct {
+	db *gorm.DB
+}
+
+// NewContributorRepository creates a new post contributor repository
+func NewContributorRepository(db *gorm.DB) repository.ContributorRepository {
+	return &contributorRepository{db: db}
+}
+
+// withUser selects contributors together with the public ID and email of their user
+func withUser(db *gorm.DB) *gorm.DB {
+	return db.Model(&domain.PostContributor{}).
+		Select("post_contributors.*, users.public_id AS user_public_id, users.email").
+		Joins("JOIN users ON users.id = post_contributors.user_id")
+}
+
+func (r *contributorRepository) List(ctx context.Context, postID uint) ([]*domain.PostContributor, error) {
+	var contributors []*domain.PostContributor
+
+	if err := withUser(r.db.WithContext(ctx)).
+		Where("post_contributors.post_id = ?", postID).
+		Order("post_contributors.id ASC").
+		Find(&contributors).Error; err != nil {
+		return nil, fmt.Errorf("failed to list contributors: %w", err)
+	}
+
+	return contributors, nil
+}
+
+func (r *contributorRepository) Add(ctx context.Context, contributor *domain.PostContributor, email string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return addContributor(tx, contributor, email)
+	})
+}
+
+// addContributor adds the user with the given email to a post inside the
+// transaction tx
+func addContributor(tx *gorm.DB, contributor *domain.PostContributor, email string) error {
+	var user domain.User
+	if err := tx.Where("email = ? AND deleted_at IS NULL", email).Take(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repository.ErrNotFound
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var count int64
+	if err := tx.Model(&domain.PostContributor{}).
+		Where("post_id = ? AND user_id = ?", contributor.PostID, user.ID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check contributor: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %s already contributes to the post", repository.ErrAlreadyExists, email)
+	}
+
+	contributor.UserID = user.ID
+	if err := tx.Create(contributor).Error; err != nil {
+		return fmt.Errorf("failed to add contributor: %w", err)
+	}
+	contributor.UserPublicID = user.PublicID
+	contributor.Email = user.Email
+
+	return nil
+}
+
+func (r *contributorRepository) Remove(ctx context.Context, postID uint, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var contributor domain.PostContributor
+		if err := withUser(tx).
+			Where("post_contributors.post_id = ? AND users.public_id = ?", postID, userID).
+			Take(&contributor).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return repository.ErrNotFound
+			}
+			return fmt.Errorf("failed to get contributor: %w", err)
+		}
+
+		if contributor.Role == domain.RoleOwner {
+			var owners int64
+			if err := tx.Model(&domain.PostContributor{}).
+				Where("post_id = ? AND role = ?", postID, domain.RoleOwner).
+				Count(&owners).Error; err != nil {
+				return fmt.Errorf("failed to count owners: %w", err)
+			}
+			if owners <= 1 {
+				return fmt.Errorf("%w: a post cannot lose its last owner", repository.ErrConflict)
+			}
+		}
+
+		if err := tx.Delete(&domain.PostContributor{}, contributor.ID).Error; err != nil {
+			return fmt.Errorf("failed to remove contributor: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,134 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"gorm.io/gorm"
+)
+
+type engagementRepository struct {
+	db *gorm.DB
+}
+
+// NewEngagementRepository creates a new reaction and bookmark repository
+func NewEngagementRepository(db *gorm.DB) repository.EngagementRepository {
+	return &engagementRepository{db: db}
+}
+
+func (r *engagementRepository) SetReaction(ctx context.Context, reaction *domain.Reaction) (bool, error) {
+	created := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing domain.Reaction
+		err := tx.Where("user_id = ? AND post_id = ?", reaction.UserID, reaction.PostID).Take(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			created = true
+			if err := tx.Create(reaction).Error; err != nil {
+				return fmt.Errorf("failed to create reaction: %w", err)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get reaction: %w", err)
+		}
+
+		existing.Emoji = reaction.Emoji
+		if err := tx.Model(&existing).Select("Emoji", "UpdatedAt").Updates(&existing).Error; err != nil {
+			return fmt.Errorf("failed to update reaction: %w", err)
+		}
+		reaction.ID = existing.ID
+		reaction.CreatedAt = existing.CreatedAt
+		reaction.UpdatedAt = existing.UpdatedAt
+		return nil
+	})
+
+	return created, err
+}
+
+func (r *engagementRepository) DeleteReaction(ctx context.Context, userID, postID uint) error {
+	res := r.db.WithContext(ctx).
+		Where("user_id = ? AND post_id = ?", userID, postID).
+		Delete(&domain.Reaction{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete reaction: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *engagementRepository) AddBookmark(ctx context.Context, bookmark *domain.Bookmark) error {
+	var existing domain.Bookmark
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND post_id = ?", bookmark.UserID, bookmark.PostID).
+		Take(&existing).Error
+	if err == nil {
+		*bookmark = existing
+		return repository.ErrAlreadyExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to get bookmark: %w", err)
+	}
+
+	if err := r.db.WithContext(ctx).Create(bookmark).Error; err != nil {
+		return fmt.Errorf("failed to create bookmark: %w", err)
+	}
+	return nil
+}
+
+func (r *engagementRepository) DeleteBookmark(ctx context.Context, userID, postID uint) error {
+	res := r.db.WithContext(ctx).
+		Where("user_id = ? AND post_id = ?", userID, postID).
+		Delete(&domain.Bookmark{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete bookmark: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *engagementRepository) ListBookmarked(ctx context.Context, userID uint, limit, offset int) ([]*domain.Post, int64, error) {
+	var posts []*domain.Post
+	var total int64
+
+	// Bookmarks of posts in the trash, or of posts that are no longer
+	// published and the user does not contribute to, are kept but not listed
+	contributed := r.db.Model(&domain.PostContributor{}).
+		Select("post_id").
+		Where("user_id = ?", userID)
+	query := r.db.WithContext(ctx).Model(&domain.Post{}).
+		Joins("JOIN bookmarks ON bookmarks.post_id = posts.id").
+		Where("bookmarks.user_id = ?", userID).
+		Where(r.db.Where("posts.status = ?", domain.PostPublished).Or("posts.id IN (?)", contributed))
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count bookmarks: %w", err)
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Select("posts.*").
+		Order("bookmarks.created_at DESC, bookmarks.id DESC").
+		Preload("Tags").
+		Find(&posts).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+
+	if err := loadDetails(r.db.WithContext(ctx), posts...); err != nil {
+		return nil, 0, err
+	}
+
+	return posts, total, nil
+}
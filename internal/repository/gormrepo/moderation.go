@@ -0,0 +1,114 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"gorm.io/gorm"
+)
+
+type moderationRepository struct {
+	db *gorm.DB
+}
+
+// NewModerationRepository creates a new moderation queue repository
+func NewModerationRepository(db *gorm.DB) repository.ModerationRepository {
+	return &moderationRepository{db: db}
+}
+
+func (r *moderationRepository) GetByID(ctx context.Context, id string) (*domain.ModerationItem, error) {
+	var item domain.ModerationItem
+
+	if err := r.db.WithContext(ctx).Where("public_id = ?", strings.ToLower(id)).Take(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get moderation item: %w", err)
+	}
+
+	return &item, nil
+}
+
+func (r *moderationRepository) List(ctx context.Context, status string, limit, offset int) ([]*domain.ModerationItem, int64, error) {
+	var items []*domain.ModerationItem
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.ModerationItem{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count moderation items: %w", err)
+	}
+
+	if err := query.Order("id ASC").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list moderation items: %w", err)
+	}
+
+	return items, total, nil
+}
+
+func (r *moderationRepository) GetPending(ctx context.Context, kind string, targetID uint) (*domain.ModerationItem, error) {
+	var item domain.ModerationItem
+
+	err := r.db.WithContext(ctx).
+		Where("kind = ? AND target_id = ? AND status = ?", kind, targetID, domain.ModerationPending).
+		Take(&item).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get pending moderation item: %w", err)
+	}
+
+	return &item, nil
+}
+
+func (r *moderationRepository) Hold(ctx context.Context, item *domain.ModerationItem) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return holdItem(tx, item)
+	})
+}
+
+// holdItem queues item inside the transaction tx, or refreshes the
+// pending item of the same content
+func holdItem(tx *gorm.DB, item *domain.ModerationItem) error {
+	var pending domain.ModerationItem
+	err := tx.Where("kind = ? AND target_id = ? AND status = ?", item.Kind, item.TargetID, domain.ModerationPending).
+		Take(&pending).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		item.Status = domain.ModerationPending
+		if err := tx.Create(item).Error; err != nil {
+			return fmt.Errorf("failed to queue moderation item: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find moderation item: %w", err)
+	}
+
+	// The content keeps the status it was held from the first time
+	pending.Excerpt = item.Excerpt
+	pending.Reasons = item.Reasons
+	if err := tx.Model(&pending).Select("Excerpt", "Reasons", "UpdatedAt").Updates(&pending).Error; err != nil {
+		return fmt.Errorf("failed to update moderation item: %w", err)
+	}
+	*item = pending
+
+	return nil
+}
+
+func (r *moderationRepository) Resolve(ctx context.Context, item *domain.ModerationItem) error {
+	res := r.db.WithContext(ctx).Model(item).
+		Where("status = ?", domain.ModerationPending).
+		Select("Status", "ModeratedBy", "ModeratedAt", "UpdatedAt").
+		Updates(item)
+	if res.Error != nil {
+		return fmt.Errorf("failed to resolve moderation item: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return repository.ErrConflict
+	}
+	return nil
+}
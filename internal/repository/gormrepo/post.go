@@ -5,12 +5,22 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/yakuter/ugin/internal/domain"
 	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/pkg/uid"
 	"gorm.io/gorm"
 )
 
+// batchSize is the number of posts inserted per statement by ApplyBatch
+const batchSize = 100
+
+// popularity is the SQL expression posts are sorted by for
+// repository.SortPopularity: their number of reactions and bookmarks
+const popularity = "((SELECT COUNT(*) FROM reactions WHERE reactions.post_id = posts.id) + " +
+	"(SELECT COUNT(*) FROM bookmarks WHERE bookmarks.post_id = posts.id))"
+
 type postRepository struct {
 	db *gorm.DB
 }
@@ -23,9 +33,14 @@ func NewPostRepository(db *gorm.DB) repository.PostRepository {
 func (r *postRepository) GetByID(ctx context.Context, id string) (*domain.Post, error) {
 	var post domain.Post
 
-	err := r.db.WithContext(ctx).
-		Preload("Tags").
-		First(&post, id).Error
+	query := r.db.WithContext(ctx).Preload("Tags")
+	if uid.IsValid(id) {
+		query = query.Where("public_id = ?", strings.ToLower(id))
+	} else {
+		query = query.Where("slug = ?", id)
+	}
+
+	err := query.First(&post).Error
 
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -34,9 +49,112 @@ func (r *postRepository) GetByID(ctx context.Context, id string) (*domain.Post,
 		return nil, fmt.Errorf("failed to get post: %w", err)
 	}
 
+	if err := loadDetails(r.db.WithContext(ctx), &post); err != nil {
+		return nil, err
+	}
+
+	if err := loadSeries(r.db.WithContext(ctx), &post); err != nil {
+		return nil, err
+	}
+
 	return &post, nil
 }
 
+func (r *postRepository) GetByPreviousSlug(ctx context.Context, slug string) (*domain.Post, error) {
+	var previous domain.PostSlug
+
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&previous).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get previous slug: %w", err)
+	}
+
+	var post domain.Post
+
+	err = r.db.WithContext(ctx).Preload("Tags").First(&post, previous.PostID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	if err := loadDetails(r.db.WithContext(ctx), &post); err != nil {
+		return nil, err
+	}
+
+	return &post, nil
+}
+
+func (r *postRepository) SlugTaken(ctx context.Context, slug string, postID uint) (bool, error) {
+	var count int64
+
+	if err := r.db.WithContext(ctx).Unscoped().Model(&domain.Post{}).
+		Where("slug = ? AND id <> ?", slug, postID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check slug: %w", err)
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	if err := r.db.WithContext(ctx).Model(&domain.PostSlug{}).
+		Where("slug = ? AND post_id <> ?", slug, postID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check previous slugs: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *postRepository) ListWithoutSlug(ctx context.Context, limit int) ([]*domain.Post, error) {
+	var posts []*domain.Post
+
+	if err := r.db.WithContext(ctx).Unscoped().
+		Where("slug IS NULL OR slug = ''").
+		Order("id").
+		Limit(limit).
+		Find(&posts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list posts without slug: %w", err)
+	}
+
+	return posts, nil
+}
+
+func (r *postRepository) SetSlug(ctx context.Context, id uint, slug string) error {
+	if err := r.db.WithContext(ctx).Unscoped().Model(&domain.Post{}).
+		Where("id = ?", id).
+		UpdateColumn("slug", slug).Error; err != nil {
+		return fmt.Errorf("failed to set slug: %w", err)
+	}
+	return nil
+}
+
+func (r *postRepository) ListUnrendered(ctx context.Context, limit int) ([]*domain.Post, error) {
+	var posts []*domain.Post
+
+	if err := r.db.WithContext(ctx).Unscoped().
+		Where("description_html IS NULL").
+		Order("id").
+		Limit(limit).
+		Find(&posts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list unrendered posts: %w", err)
+	}
+
+	return posts, nil
+}
+
+func (r *postRepository) SetDescriptionHTML(ctx context.Context, id uint, html string) error {
+	if err := r.db.WithContext(ctx).Unscoped().Model(&domain.Post{}).
+		Where("id = ?", id).
+		UpdateColumn("description_html", html).Error; err != nil {
+		return fmt.Errorf("failed to set description html: %w", err)
+	}
+	return nil
+}
+
 func (r *postRepository) List(ctx context.Context, filter repository.ListFilter) ([]*domain.Post, *repository.ListResult, error) {
 	var posts []*domain.Post
 	result := &repository.ListResult{}
@@ -49,13 +167,49 @@ func (r *postRepository) List(ctx context.Context, filter repository.ListFilter)
 		query = query.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ?", searchTerm, searchTerm)
 	}
 
+	// Apply category filter, which takes in the category's whole subtree
+	if filter.Category != "" {
+		category, err := getCategory(r.db.WithContext(ctx), filter.Category)
+		if err != nil {
+			return nil, nil, err
+		}
+		subtree := r.db.Model(&domain.Category{}).Select("id").Where("path LIKE ?", category.Path+"%")
+		query = query.Where("category_id IN (?)", subtree)
+	}
+
+	// Apply tag filter
+	if filter.Tag != "" {
+		tagged := r.db.Model(&domain.Tag{}).Select("post_id").Where("LOWER(name) = ?", strings.ToLower(filter.Tag))
+		query = query.Where("id IN (?)", tagged)
+	}
+
+	// Apply date filter
+	if filter.Year > 0 {
+		year, month := dateParts(r.db, "created_at")
+		query = query.Where(year+" = ?", filter.Year)
+		if filter.Month > 0 {
+			query = query.Where(month+" = ?", filter.Month)
+		}
+	}
+
+	// Apply status and contributor filters, which also limit the total
+	total := r.db.WithContext(ctx).Model(&domain.Post{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+		total = total.Where("status = ?", filter.Status)
+	}
+	if filter.Contributor != "" {
+		query = query.Where(contributorScope(r.db, filter))
+		total = total.Where(contributorScope(r.db, filter))
+	}
+
 	// Get filtered count
 	if err := query.Count(&result.Filtered).Error; err != nil {
 		return nil, nil, fmt.Errorf("failed to count filtered posts: %w", err)
 	}
 
-	// Get total count (without filters)
-	if err := r.db.WithContext(ctx).Model(&domain.Post{}).Count(&result.Total).Error; err != nil {
+	// Get total count (without filters other than status)
+	if err := total.Count(&result.Total).Error; err != nil {
 		return nil, nil, fmt.Errorf("failed to count total posts: %w", err)
 	}
 
@@ -70,6 +224,9 @@ func (r *postRepository) List(ctx context.Context, filter repository.ListFilter)
 		if sortField == "id" || sortField == "name" || sortField == "created_at" || sortField == "updated_at" {
 			query = query.Order(fmt.Sprintf("%s %s", sortField, order))
 		}
+		if sortField == repository.SortPopularity {
+			query = query.Order(fmt.Sprintf("%s %s, id %s", popularity, order, order))
+		}
 	}
 
 	// Apply pagination
@@ -86,9 +243,162 @@ func (r *postRepository) List(ctx context.Context, filter repository.ListFilter)
 		return nil, nil, fmt.Errorf("failed to list posts: %w", err)
 	}
 
+	if err := loadDetails(r.db.WithContext(ctx), posts...); err != nil {
+		return nil, nil, err
+	}
+
 	return posts, result, nil
 }
 
+func (r *postRepository) Archive(ctx context.Context, status string) ([]*domain.ArchiveMonth, error) {
+	year, month := dateParts(r.db, "created_at")
+
+	query := r.db.WithContext(ctx).Model(&domain.Post{}).
+		Select(fmt.Sprintf("%s AS year, %s AS month, COUNT(*) AS count", year, month))
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var months []*domain.ArchiveMonth
+	if err := query.Group("year, month").Order("year DESC, month DESC").Scan(&months).Error; err != nil {
+		return nil, fmt.Errorf("failed to count posts per month: %w", err)
+	}
+
+	return months, nil
+}
+
+// contributedTo selects the IDs of the posts the user with email has one
+// of roles on, or any role when roles is empty
+func contributedTo(db *gorm.DB, email string, roles []string) *gorm.DB {
+	query := db.Model(&domain.PostContributor{}).
+		Select("post_contributors.post_id").
+		Joins("JOIN users ON users.id = post_contributors.user_id").
+		Where("LOWER(users.email) = ?", strings.ToLower(email))
+	if len(roles) > 0 {
+		query = query.Where("post_contributors.role IN ?", roles)
+	}
+	return query
+}
+
+// contributorScope matches the posts the Contributor of filter has a role
+// on, and published posts as well when IncludePublished is set
+func contributorScope(db *gorm.DB, filter repository.ListFilter) *gorm.DB {
+	scope := db.Where("id IN (?)", contributedTo(db, filter.Contributor, filter.Roles))
+	if filter.IncludePublished {
+		scope = scope.Or("status = ?", domain.PostPublished)
+	}
+	return scope
+}
+
+// dateParts returns SQL expressions for the year and month of a timestamp
+// column, which every supported driver spells differently. SQLite and
+// PostgreSQL give them in UTC; MySQL DATETIME columns carry no time zone,
+// so they follow the loc the connection stores times in.
+func dateParts(db *gorm.DB, column string) (year, month string) {
+	switch db.Dialector.Name() {
+	case "mysql":
+		return "YEAR(" + column + ")", "MONTH(" + column + ")"
+	case "postgres":
+		utc := column + " AT TIME ZONE 'UTC'"
+		return "CAST(EXTRACT(YEAR FROM " + utc + ") AS INTEGER)", "CAST(EXTRACT(MONTH FROM " + utc + ") AS INTEGER)"
+	default:
+		return "CAST(strftime('%Y', " + column + ") AS INTEGER)", "CAST(strftime('%m', " + column + ") AS INTEGER)"
+	}
+}
+
+func (r *postRepository) Stream(ctx context.Context, filter repository.ListFilter, fn func(*domain.Post) error) error {
+	db := r.db.WithContext(ctx)
+
+	// Categories are few, so their public IDs are looked up in memory
+	var categories []*domain.Category
+	if err := db.Select("id", "public_id").Find(&categories).Error; err != nil {
+		return fmt.Errorf("failed to query categories: %w", err)
+	}
+	categoryIDs := make(map[uint]string, len(categories))
+	for _, category := range categories {
+		categoryIDs[category.ID] = category.PublicID
+	}
+
+	posts := db.Model(&domain.Post{})
+	if filter.Status != "" {
+		posts = posts.Where("status = ?", filter.Status)
+	}
+	if filter.Contributor != "" {
+		posts = posts.Where(contributorScope(r.db, filter))
+	}
+	postRows, err := posts.Order("id").Rows()
+	if err != nil {
+		return fmt.Errorf("failed to query posts: %w", err)
+	}
+	defer postRows.Close()
+
+	// Tags are read through a second cursor in post order and merged in,
+	// so neither side is ever held in memory as a whole
+	tagRows, err := db.Model(&domain.Tag{}).Order("post_id, id").Rows()
+	if err != nil {
+		return fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer tagRows.Close()
+
+	var tag *domain.Tag
+	nextTag := func() error {
+		tag = nil
+		if !tagRows.Next() {
+			return tagRows.Err()
+		}
+		tag = &domain.Tag{}
+		return db.ScanRows(tagRows, tag)
+	}
+	if err := nextTag(); err != nil {
+		return fmt.Errorf("failed to read tag: %w", err)
+	}
+
+	for postRows.Next() {
+		var post domain.Post
+		if err := db.ScanRows(postRows, &post); err != nil {
+			return fmt.Errorf("failed to read post: %w", err)
+		}
+		if post.CategoryID != nil {
+			post.CategoryPublicID = categoryIDs[*post.CategoryID]
+		}
+
+		for tag != nil && tag.PostID <= post.ID {
+			if tag.PostID == post.ID {
+				post.Tags = append(post.Tags, *tag)
+			}
+			if err := nextTag(); err != nil {
+				return fmt.Errorf("failed to read tag: %w", err)
+			}
+		}
+
+		if err := fn(&post); err != nil {
+			return err
+		}
+	}
+
+	if err := postRows.Err(); err != nil {
+		return fmt.Errorf("failed to read posts: %w", err)
+	}
+	return nil
+}
+
+func (r *postRepository) ListChangedSince(ctx context.Context, since time.Time, afterID uint, limit int) ([]*domain.Post, error) {
+	var posts []*domain.Post
+
+	// Soft deletes set only deleted_at, so both timestamps are checked
+	if err := r.db.WithContext(ctx).Unscoped().
+		Select("id", "public_id", "slug", "status", "updated_at", "deleted_at").
+		Where("id > ? AND (updated_at >= ? OR deleted_at >= ?)", afterID, since, since).
+		Order("id").
+		Limit(limit).
+		Preload("Tags").
+		Find(&posts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list changed posts: %w", err)
+	}
+
+	return posts, nil
+}
+
 func (r *postRepository) Create(ctx context.Context, post *domain.Post) error {
 	if err := r.db.WithContext(ctx).Create(post).Error; err != nil {
 		return fmt.Errorf("failed to create post: %w", err)
@@ -97,26 +407,558 @@ func (r *postRepository) Create(ctx context.Context, post *domain.Post) error {
 }
 
 func (r *postRepository) Update(ctx context.Context, post *domain.Post) error {
-	if err := r.db.WithContext(ctx).Save(post).Error; err != nil {
-		return fmt.Errorf("failed to update post: %w", err)
+	current := post.Version
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return r.update(tx, post)
+	})
+	if err != nil {
+		post.Version = current
+	}
+
+	return err
+}
+
+// update saves post and replaces its tags inside the transaction tx
+func (r *postRepository) update(tx *gorm.DB, post *domain.Post) error {
+	current := post.Version
+
+	if err := r.keepPreviousSlug(tx, post); err != nil {
+		return err
+	}
+
+	// Only update the row if nobody else has saved it since it was read
+	post.Version = current + 1
+	res := tx.Model(post).
+		Where("version = ?", current).
+		Select("*").
+		Omit("ID", "CreatedAt", "DeletedAt", "Tags").
+		Updates(post)
+	if res.Error != nil {
+		return fmt.Errorf("failed to update post: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return repository.ErrConflict
 	}
+
+	// Replace the post's tags with the given set. Replaced tags are
+	// removed for good so restoring the post from trash ignores them.
+	if err := tx.Unscoped().Where("post_id = ?", post.ID).Delete(&domain.Tag{}).Error; err != nil {
+		return fmt.Errorf("failed to delete tags: %w", err)
+	}
+
+	if len(post.Tags) == 0 {
+		return nil
+	}
+
+	for i := range post.Tags {
+		post.Tags[i].ID = 0
+		post.Tags[i].PublicID = ""
+		post.Tags[i].PostID = post.ID
+	}
+
+	if err := tx.Create(&post.Tags).Error; err != nil {
+		return fmt.Errorf("failed to create tags: %w", err)
+	}
+
 	return nil
 }
 
-func (r *postRepository) Delete(ctx context.Context, id string) error {
+func (r *postRepository) Delete(ctx context.Context, id uint, version uint) error {
 	// Start a transaction to delete post and its tags
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Soft delete tags associated with the post
-		if err := tx.Where("post_id = ?", id).Delete(&domain.Tag{}).Error; err != nil {
-			return fmt.Errorf("failed to delete tags: %w", err)
+		return r.delete(tx, id, version)
+	})
+}
+
+// delete soft-deletes the post and its tags inside the transaction tx
+func (r *postRepository) delete(tx *gorm.DB, id uint, version uint) error {
+	// Soft delete the post
+	query := tx.Where("id = ?", id)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	res := query.Delete(&domain.Post{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete post: %w", res.Error)
+	}
+	if version != 0 && res.RowsAffected == 0 {
+		return repository.ErrConflict
+	}
+
+	// Soft delete tags associated with the post
+	if err := tx.Where("post_id = ?", id).Delete(&domain.Tag{}).Error; err != nil {
+		return fmt.Errorf("failed to delete tags: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postRepository) ApplyBatch(ctx context.Context, batch *repository.PostBatch) error {
+	versions := make([]uint, len(batch.Update))
+	for i, post := range batch.Update {
+		versions[i] = post.Version
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(batch.Create) > 0 {
+			if err := tx.CreateInBatches(batch.Create, batchSize).Error; err != nil {
+				return &repository.BatchError{Err: fmt.Errorf("failed to create posts: %w", err)}
+			}
 		}
 
-		// Soft delete the post
-		if err := tx.Delete(&domain.Post{}, id).Error; err != nil {
-			return fmt.Errorf("failed to delete post: %w", err)
+		for _, post := range batch.Update {
+			if err := r.update(tx, post); err != nil {
+				return &repository.BatchError{Post: post, Err: err}
+			}
+		}
+
+		for _, post := range batch.Delete {
+			if err := r.delete(tx, post.ID, post.Version); err != nil {
+				return &repository.BatchError{Post: post, Err: err}
+			}
+		}
+
+		for _, posts := range [][]*domain.Post{batch.Create, batch.Update} {
+			for _, post := range posts {
+				if err := writeRecords(tx, post, batch.Records[post]); err != nil {
+					return &repository.BatchError{Post: post, Err: err}
+				}
+			}
 		}
 
 		return nil
 	})
+	if err != nil {
+		// Nothing was stored, so hand back the posts as they were given
+		for _, post := range batch.Create {
+			post.ID = 0
+			post.PublicID = ""
+			for i := range post.Tags {
+				post.Tags[i].ID = 0
+				post.Tags[i].PublicID = ""
+			}
+		}
+		for i, post := range batch.Update {
+			post.Version = versions[i]
+		}
+	}
+
+	return err
 }
 
+// writeRecords stores the records written along with post inside the
+// transaction tx, once the post has its ID
+func writeRecords(tx *gorm.DB, post *domain.Post, records *repository.PostRecords) error {
+	if records == nil {
+		return nil
+	}
+
+	if records.Baseline != nil {
+		var count int64
+		if err := tx.Model(&domain.PostRevision{}).Where("post_id = ?", post.ID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to count revisions: %w", err)
+		}
+		if count == 0 {
+			records.Baseline.PostID = post.ID
+			if err := createRevision(tx, records.Baseline); err != nil {
+				return err
+			}
+		}
+	}
+
+	if records.Revision != nil {
+		records.Revision.PostID = post.ID
+		if err := createRevision(tx, records.Revision); err != nil {
+			return err
+		}
+	}
+
+	if records.Owner != "" {
+		owner := &domain.PostContributor{PostID: post.ID, Role: domain.RoleOwner}
+		if err := addContributor(tx, owner, records.Owner); err != nil {
+			return err
+		}
+	}
+
+	if records.Hold != nil {
+		records.Hold.TargetID = post.ID
+		records.Hold.TargetPublicID = post.PublicID
+		if err := holdItem(tx, records.Hold); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *postRepository) ListDeleted(ctx context.Context, filter repository.ListFilter) ([]*domain.Post, *repository.ListResult, error) {
+	var posts []*domain.Post
+	result := &repository.ListResult{}
+
+	query := r.db.WithContext(ctx).Unscoped().Model(&domain.Post{}).Where("deleted_at IS NOT NULL")
+
+	// The contributor filter also limits the total
+	total := r.db.WithContext(ctx).Unscoped().Model(&domain.Post{}).Where("deleted_at IS NOT NULL")
+	if filter.Contributor != "" {
+		query = query.Where(contributorScope(r.db, filter))
+		total = total.Where(contributorScope(r.db, filter))
+	}
+
+	if err := total.Count(&result.Total).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to count deleted posts: %w", err)
+	}
+
+	// Apply search filter
+	if filter.Search != "" {
+		searchTerm := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ?", searchTerm, searchTerm)
+	}
+
+	if err := query.Count(&result.Filtered).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to count filtered deleted posts: %w", err)
+	}
+
+	// Most recently deleted first
+	query = query.Order("deleted_at DESC")
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	// Tags are soft-deleted together with their post
+	if err := query.Preload("Tags", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Find(&posts).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to list deleted posts: %w", err)
+	}
+
+	return posts, result, nil
+}
+
+func (r *postRepository) GetDeleted(ctx context.Context, id string) (*domain.Post, error) {
+	var post domain.Post
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("public_id = ? AND deleted_at IS NOT NULL", strings.ToLower(id)).
+		First(&post).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get deleted post: %w", err)
+	}
+
+	return &post, nil
+}
+
+func (r *postRepository) Restore(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var post domain.Post
+		err := tx.Unscoped().
+			Where("public_id = ? AND deleted_at IS NOT NULL", strings.ToLower(id)).
+			First(&post).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return repository.ErrNotFound
+			}
+			return fmt.Errorf("failed to get deleted post: %w", err)
+		}
+
+		if err := tx.Unscoped().Model(&post).Update("deleted_at", nil).Error; err != nil {
+			return fmt.Errorf("failed to restore post: %w", err)
+		}
+
+		if err := tx.Unscoped().Model(&domain.Tag{}).
+			Where("post_id = ? AND deleted_at IS NOT NULL", post.ID).
+			Update("deleted_at", nil).Error; err != nil {
+			return fmt.Errorf("failed to restore tags: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *postRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Unscoped().Model(&domain.Post{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("failed to find expired posts: %w", err)
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Unscoped().Where("post_id IN ?", ids).Delete(&domain.Tag{}).Error; err != nil {
+			return fmt.Errorf("failed to purge tags: %w", err)
+		}
+
+		if err := tx.Where("post_id IN ?", ids).Delete(&domain.PostRevision{}).Error; err != nil {
+			return fmt.Errorf("failed to purge revisions: %w", err)
+		}
+
+		if err := tx.Where("post_id IN ?", ids).Delete(&domain.PostSlug{}).Error; err != nil {
+			return fmt.Errorf("failed to purge previous slugs: %w", err)
+		}
+
+		comments := tx.Unscoped().Model(&domain.Comment{}).Select("id").Where("post_id IN ?", ids)
+		if err := tx.Where("kind = ? AND target_id IN (?)", domain.ModerationComment, comments).Delete(&domain.ModerationItem{}).Error; err != nil {
+			return fmt.Errorf("failed to purge comment moderation items: %w", err)
+		}
+
+		if err := tx.Unscoped().Where("post_id IN ?", ids).Delete(&domain.Comment{}).Error; err != nil {
+			return fmt.Errorf("failed to purge comments: %w", err)
+		}
+
+		if err := tx.Where("post_id IN ?", ids).Delete(&domain.PostContributor{}).Error; err != nil {
+			return fmt.Errorf("failed to purge contributors: %w", err)
+		}
+
+		if err := tx.Where("post_id IN ?", ids).Delete(&domain.PostReview{}).Error; err != nil {
+			return fmt.Errorf("failed to purge reviews: %w", err)
+		}
+
+		if err := tx.Where("kind = ? AND target_id IN ?", domain.ModerationPost, ids).Delete(&domain.ModerationItem{}).Error; err != nil {
+			return fmt.Errorf("failed to purge moderation items: %w", err)
+		}
+
+		if err := tx.Where("post_id IN ?", ids).Delete(&domain.SeriesPost{}).Error; err != nil {
+			return fmt.Errorf("failed to purge series memberships: %w", err)
+		}
+
+		if err := tx.Where("post_id IN ?", ids).Delete(&domain.PostTranslation{}).Error; err != nil {
+			return fmt.Errorf("failed to purge translations: %w", err)
+		}
+
+		if err := tx.Where("post_id IN ?", ids).Delete(&domain.Reaction{}).Error; err != nil {
+			return fmt.Errorf("failed to purge reactions: %w", err)
+		}
+
+		if err := tx.Where("post_id IN ?", ids).Delete(&domain.Bookmark{}).Error; err != nil {
+			return fmt.Errorf("failed to purge bookmarks: %w", err)
+		}
+
+		if err := tx.Where("post_id IN ?", ids).Delete(&domain.PostDailyViews{}).Error; err != nil {
+			return fmt.Errorf("failed to purge views: %w", err)
+		}
+
+		res := tx.Unscoped().Where("id IN ?", ids).Delete(&domain.Post{})
+		if res.Error != nil {
+			return fmt.Errorf("failed to purge posts: %w", res.Error)
+		}
+		purged = res.RowsAffected
+
+		return nil
+	})
+
+	return purged, err
+}
+
+// loadDetails fills in the fields of posts that live in other tables
+func loadDetails(db *gorm.DB, posts ...*domain.Post) error {
+	if err := loadCommentCounts(db, posts...); err != nil {
+		return err
+	}
+	if err := loadEngagementCounts(db, posts...); err != nil {
+		return err
+	}
+	if err := loadCategories(db, posts...); err != nil {
+		return err
+	}
+	return loadCovers(db, posts...)
+}
+
+// loadCategories sets the primary category of each post that has one
+func loadCategories(db *gorm.DB, posts ...*domain.Post) error {
+	var ids []uint
+	for _, post := range posts {
+		if post.CategoryID != nil {
+			ids = append(ids, *post.CategoryID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var categories []*domain.Category
+	if err := withParent(db).Where("categories.id IN ?", ids).Find(&categories).Error; err != nil {
+		return fmt.Errorf("failed to load categories: %w", err)
+	}
+
+	byID := make(map[uint]*domain.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+	for _, post := range posts {
+		if post.CategoryID == nil {
+			continue
+		}
+		if category := byID[*post.CategoryID]; category != nil {
+			post.Category = category
+			post.CategoryPublicID = category.PublicID
+		}
+	}
+
+	return nil
+}
+
+// loadCovers sets the cover image of each post that has one
+func loadCovers(db *gorm.DB, posts ...*domain.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	var covers []*domain.Attachment
+	if err := db.Where("post_id IN ? AND kind = ?", ids, domain.AttachmentCover).
+		Order("id ASC").
+		Find(&covers).Error; err != nil {
+		return fmt.Errorf("failed to load covers: %w", err)
+	}
+
+	byPost := make(map[uint]*domain.Attachment, len(covers))
+	for _, cover := range covers {
+		byPost[cover.PostID] = cover
+	}
+
+	latest := make([]*domain.Attachment, 0, len(byPost))
+	for _, post := range posts {
+		post.Cover = byPost[post.ID]
+		if post.Cover != nil {
+			latest = append(latest, post.Cover)
+		}
+	}
+
+	return loadDerivatives(db, latest...)
+}
+
+// loadCommentCounts sets the number of approved comments on each post
+func loadCommentCounts(db *gorm.DB, posts ...*domain.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	var counts []struct {
+		PostID uint
+		Count  int64
+	}
+	if err := db.Model(&domain.Comment{}).
+		Select("post_id, COUNT(*) AS count").
+		Where("post_id IN ? AND status = ?", ids, domain.CommentApproved).
+		Group("post_id").
+		Scan(&counts).Error; err != nil {
+		return fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	byPost := make(map[uint]int64, len(counts))
+	for _, c := range counts {
+		byPost[c.PostID] = c.Count
+	}
+	for _, post := range posts {
+		post.CommentCount = byPost[post.ID]
+	}
+
+	return nil
+}
+
+// loadEngagementCounts sets the reaction counts by emoji and the number of
+// bookmarks of each post
+func loadEngagementCounts(db *gorm.DB, posts ...*domain.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	var reactions []struct {
+		PostID uint
+		Emoji  string
+		Count  int64
+	}
+	if err := db.Model(&domain.Reaction{}).
+		Select("post_id, emoji, COUNT(*) AS count").
+		Where("post_id IN ?", ids).
+		Group("post_id, emoji").
+		Scan(&reactions).Error; err != nil {
+		return fmt.Errorf("failed to count reactions: %w", err)
+	}
+
+	var bookmarks []struct {
+		PostID uint
+		Count  int64
+	}
+	if err := db.Model(&domain.Bookmark{}).
+		Select("post_id, COUNT(*) AS count").
+		Where("post_id IN ?", ids).
+		Group("post_id").
+		Scan(&bookmarks).Error; err != nil {
+		return fmt.Errorf("failed to count bookmarks: %w", err)
+	}
+
+	byPost := make(map[uint]*domain.Post, len(posts))
+	for _, post := range posts {
+		post.Reactions = nil
+		post.BookmarkCount = 0
+		byPost[post.ID] = post
+	}
+	for _, r := range reactions {
+		post := byPost[r.PostID]
+		if post.Reactions == nil {
+			post.Reactions = make(map[string]int64)
+		}
+		post.Reactions[r.Emoji] = r.Count
+	}
+	for _, b := range bookmarks {
+		byPost[b.PostID].BookmarkCount = b.Count
+	}
+
+	return nil
+}
+
+// keepPreviousSlug records the stored slug of post in the slug history
+// when post is about to be saved under a different one
+func (r *postRepository) keepPreviousSlug(tx *gorm.DB, post *domain.Post) error {
+	var previous string
+	if err := tx.Model(&domain.Post{}).
+		Where("id = ?", post.ID).
+		Select("COALESCE(slug, '')").
+		Scan(&previous).Error; err != nil {
+		return fmt.Errorf("failed to get current slug: %w", err)
+	}
+
+	if previous == "" || previous == post.Slug {
+		return nil
+	}
+
+	// A post going back to one of its old slugs takes it out of the history
+	if err := tx.Where("post_id = ? AND slug = ?", post.ID, post.Slug).Delete(&domain.PostSlug{}).Error; err != nil {
+		return fmt.Errorf("failed to delete previous slug: %w", err)
+	}
+
+	if err := tx.Create(&domain.PostSlug{PostID: post.ID, Slug: previous}).Error; err != nil {
+		return fmt.Errorf("failed to record previous slug: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package gormrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"gorm.io/gorm"
+)
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository creates a new post review repository
+func NewReviewRepository(db *gorm.DB) repository.ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+func (r *reviewRepository) List(ctx context.Context, postID uint) ([]*domain.PostReview, error) {
+	var reviews []*domain.PostReview
+
+	if err := r.db.WithContext(ctx).
+		Where("post_id = ?", postID).
+		Order("id ASC").
+		Find(&reviews).Error; err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	return reviews, nil
+}
+
+func (r *reviewRepository) Add(ctx context.Context, review *domain.PostReview) error {
+	if err := r.db.WithContext(ctx).Create(review).Error; err != nil {
+		return fmt.Errorf("failed to add review: %w", err)
+	}
+	return nil
+}
+
+func (r *reviewRepository) Transition(ctx context.Context, post *domain.Post, review *domain.PostReview) error {
+	now := time.Now()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Only move the post if nobody else has saved it since it was read
+		res := tx.Model(&domain.Post{}).
+			Where("id = ? AND version = ?", post.ID, post.Version).
+			Updates(map[string]interface{}{
+				"status":     post.Status,
+				"reviewer":   post.Reviewer,
+				"version":    post.Version + 1,
+				"updated_at": now,
+			})
+		if res.Error != nil {
+			return fmt.Errorf("failed to update post status: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return repository.ErrConflict
+		}
+
+		review.PostID = post.ID
+		if err := tx.Create(review).Error; err != nil {
+			return fmt.Errorf("failed to add review: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	post.Version++
+	post.UpdatedAt = now
+	return nil
+}
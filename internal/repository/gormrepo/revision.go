@@ -0,0 +1,91 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"gorm.io/gorm"
+)
+
+type postRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewPostRevisionRepository creates a new post revision repository
+func NewPostRevisionRepository(db *gorm.DB) repository.PostRevisionRepository {
+	return &postRevisionRepository{db: db}
+}
+
+func (r *postRevisionRepository) Create(ctx context.Context, rev *domain.PostRevision) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createRevision(tx, rev)
+	})
+}
+
+// createRevision stores rev as the next revision of its post inside the
+// transaction tx
+func createRevision(tx *gorm.DB, rev *domain.PostRevision) error {
+	// Revision numbers are sequential per post
+	var latest int
+	if err := tx.Model(&domain.PostRevision{}).
+		Where("post_id = ?", rev.PostID).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&latest).Error; err != nil {
+		return fmt.Errorf("failed to get latest revision: %w", err)
+	}
+
+	rev.ID = 0
+	rev.Revision = latest + 1
+
+	if err := tx.Create(rev).Error; err != nil {
+		return fmt.Errorf("failed to create revision: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postRevisionRepository) Get(ctx context.Context, postID uint, revision int) (*domain.PostRevision, error) {
+	var rev domain.PostRevision
+
+	err := r.db.WithContext(ctx).
+		Where("post_id = ? AND revision = ?", postID, revision).
+		First(&rev).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	return &rev, nil
+}
+
+func (r *postRevisionRepository) ListByPostID(ctx context.Context, postID uint) ([]*domain.PostRevision, error) {
+	var revs []*domain.PostRevision
+
+	if err := r.db.WithContext(ctx).
+		Where("post_id = ?", postID).
+		Order("revision DESC").
+		Find(&revs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	return revs, nil
+}
+
+func (r *postRevisionRepository) CountByPostID(ctx context.Context, postID uint) (int64, error) {
+	var count int64
+
+	if err := r.db.WithContext(ctx).
+		Model(&domain.PostRevision{}).
+		Where("post_id = ?", postID).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count revisions: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,272 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/pkg/uid"
+	"gorm.io/gorm"
+)
+
+type seriesRepository struct {
+	db *gorm.DB
+}
+
+// NewSeriesRepository creates a new series repository
+func NewSeriesRepository(db *gorm.DB) repository.SeriesRepository {
+	return &seriesRepository{db: db}
+}
+
+func (r *seriesRepository) GetByID(ctx context.Context, id string) (*domain.Series, error) {
+	if !uid.IsValid(id) {
+		return nil, repository.ErrNotFound
+	}
+
+	var series domain.Series
+	if err := r.db.WithContext(ctx).Where("public_id = ?", strings.ToLower(id)).Take(&series).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get series: %w", err)
+	}
+
+	return &series, nil
+}
+
+func (r *seriesRepository) List(ctx context.Context) ([]*domain.Series, error) {
+	var series []*domain.Series
+
+	if err := r.db.WithContext(ctx).Order("name ASC, id ASC").Find(&series).Error; err != nil {
+		return nil, fmt.Errorf("failed to list series: %w", err)
+	}
+
+	return series, nil
+}
+
+func (r *seriesRepository) Create(ctx context.Context, series *domain.Series) error {
+	if err := r.db.WithContext(ctx).Create(series).Error; err != nil {
+		return fmt.Errorf("failed to create series: %w", err)
+	}
+	return nil
+}
+
+func (r *seriesRepository) Update(ctx context.Context, series *domain.Series) error {
+	err := r.db.WithContext(ctx).Model(series).
+		Select("Name", "Description", "UpdatedAt").
+		Updates(series).Error
+	if err != nil {
+		return fmt.Errorf("failed to update series: %w", err)
+	}
+	return nil
+}
+
+func (r *seriesRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", id).Delete(&domain.SeriesPost{}).Error; err != nil {
+			return fmt.Errorf("failed to delete series posts: %w", err)
+		}
+
+		res := tx.Delete(&domain.Series{}, id)
+		if res.Error != nil {
+			return fmt.Errorf("failed to delete series: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return repository.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+func (r *seriesRepository) Entries(ctx context.Context, seriesID uint) ([]domain.SeriesEntry, error) {
+	return seriesEntries(r.db.WithContext(ctx), seriesID)
+}
+
+func (r *seriesRepository) AddPost(ctx context.Context, seriesID, postID uint, position int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.SeriesPost{}).Where("post_id = ?", postID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check series membership: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: post already belongs to a series", repository.ErrAlreadyExists)
+		}
+
+		var members []*domain.SeriesPost
+		if err := tx.Where("series_id = ?", seriesID).Order("position ASC, id ASC").Find(&members).Error; err != nil {
+			return fmt.Errorf("failed to load series posts: %w", err)
+		}
+
+		// Positions count the entries, which leave out trashed and
+		// unpublished posts
+		var visible []*domain.SeriesPost
+		if err := visibleMembers(tx, seriesID).Select("series_posts.*").Find(&visible).Error; err != nil {
+			return fmt.Errorf("failed to load series posts: %w", err)
+		}
+
+		member := &domain.SeriesPost{SeriesID: seriesID, PostID: postID}
+		if position <= 0 || position > len(visible) {
+			member.Position = 1
+			if len(members) > 0 {
+				member.Position = members[len(members)-1].Position + 1
+			}
+		} else {
+			// Make room by moving the post at the position and those after it along
+			member.Position = visible[position-1].Position
+			if err := tx.Model(&domain.SeriesPost{}).
+				Where("series_id = ? AND position >= ?", seriesID, member.Position).
+				UpdateColumn("position", gorm.Expr("position + 1")).Error; err != nil {
+				return fmt.Errorf("failed to shift series posts: %w", err)
+			}
+		}
+
+		if err := tx.Create(member).Error; err != nil {
+			return fmt.Errorf("failed to add post to series: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *seriesRepository) RemovePost(ctx context.Context, seriesID, postID uint) error {
+	res := r.db.WithContext(ctx).
+		Where("series_id = ? AND post_id = ?", seriesID, postID).
+		Delete(&domain.SeriesPost{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to remove post from series: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *seriesRepository) Reorder(ctx context.Context, seriesID uint, postIDs []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		type member struct {
+			ID        uint
+			PublicID  string
+			Status    string
+			DeletedAt *time.Time
+		}
+
+		var members []member
+		if err := tx.Table("series_posts").
+			Select("series_posts.id, posts.public_id, posts.status, posts.deleted_at").
+			Joins("JOIN posts ON posts.id = series_posts.post_id").
+			Where("series_posts.series_id = ?", seriesID).
+			Order("series_posts.position ASC, series_posts.id ASC").
+			Scan(&members).Error; err != nil {
+			return fmt.Errorf("failed to load series posts: %w", err)
+		}
+
+		// Posts left out of the entries keep their order after them
+		byPublicID := make(map[string]uint, len(members))
+		var hidden []uint
+		for _, m := range members {
+			if m.DeletedAt != nil || m.Status != domain.PostPublished {
+				hidden = append(hidden, m.ID)
+			} else {
+				byPublicID[m.PublicID] = m.ID
+			}
+		}
+
+		if len(postIDs) != len(byPublicID) {
+			return fmt.Errorf("%w: the new order must list each post of the series once", repository.ErrInvalidInput)
+		}
+		order := make([]uint, 0, len(members))
+		seen := make(map[string]bool, len(postIDs))
+		for _, postID := range postIDs {
+			id, ok := byPublicID[postID]
+			if !ok || seen[postID] {
+				return fmt.Errorf("%w: the new order must list each post of the series once", repository.ErrInvalidInput)
+			}
+			seen[postID] = true
+			order = append(order, id)
+		}
+		order = append(order, hidden...)
+
+		for i, id := range order {
+			if err := tx.Model(&domain.SeriesPost{}).Where("id = ?", id).
+				UpdateColumn("position", i+1).Error; err != nil {
+				return fmt.Errorf("failed to reorder series posts: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// visibleMembers selects the memberships of the series whose posts are
+// published and not in the trash, in reading order
+func visibleMembers(db *gorm.DB, seriesID uint) *gorm.DB {
+	return db.Model(&domain.SeriesPost{}).
+		Joins("JOIN posts ON posts.id = series_posts.post_id AND posts.deleted_at IS NULL AND posts.status = ?", domain.PostPublished).
+		Where("series_posts.series_id = ?", seriesID).
+		Order("series_posts.position ASC, series_posts.id ASC")
+}
+
+// seriesEntries returns the published posts of the series not in the
+// trash, numbered in reading order
+func seriesEntries(db *gorm.DB, seriesID uint) ([]domain.SeriesEntry, error) {
+	entries := []domain.SeriesEntry{}
+	if err := visibleMembers(db, seriesID).
+		Select("posts.public_id AS post_id, posts.name, posts.slug").
+		Scan(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to load series entries: %w", err)
+	}
+
+	for i := range entries {
+		entries[i].Position = i + 1
+	}
+	return entries, nil
+}
+
+// loadSeries sets the place of the post within its series, if it has one
+func loadSeries(db *gorm.DB, post *domain.Post) error {
+	var member domain.SeriesPost
+	if err := db.Where("post_id = ?", post.ID).Take(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to load series membership: %w", err)
+	}
+
+	var series domain.Series
+	if err := db.Take(&series, member.SeriesID).Error; err != nil {
+		return fmt.Errorf("failed to load series: %w", err)
+	}
+
+	entries, err := seriesEntries(db, series.ID)
+	if err != nil {
+		return err
+	}
+
+	for i := range entries {
+		if entries[i].PostID != post.PublicID {
+			continue
+		}
+
+		nav := &domain.SeriesNavigation{
+			ID:       series.PublicID,
+			Name:     series.Name,
+			Position: entries[i].Position,
+			Total:    len(entries),
+		}
+		if i > 0 {
+			nav.Prev = &entries[i-1]
+		}
+		if i < len(entries)-1 {
+			nav.Next = &entries[i+1]
+		}
+		post.Series = nav
+		break
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"gorm.io/gorm"
+)
+
+type postTranslationRepository struct {
+	db *gorm.DB
+}
+
+// NewPostTranslationRepository creates a new post translation repository
+func NewPostTranslationRepository(db *gorm.DB) repository.PostTranslationRepository {
+	return &postTranslationRepository{db: db}
+}
+
+func (r *postTranslationRepository) Get(ctx context.Context, postID uint, locale string) (*domain.PostTranslation, error) {
+	var translation domain.PostTranslation
+
+	err := r.db.WithContext(ctx).Where("post_id = ? AND locale = ?", postID, locale).Take(&translation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get translation: %w", err)
+	}
+
+	return &translation, nil
+}
+
+func (r *postTranslationRepository) ListByPostID(ctx context.Context, postID uint) ([]*domain.PostTranslation, error) {
+	var translations []*domain.PostTranslation
+
+	if err := r.db.WithContext(ctx).
+		Where("post_id = ?", postID).
+		Order("locale ASC").
+		Find(&translations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list translations: %w", err)
+	}
+
+	return translations, nil
+}
+
+func (r *postTranslationRepository) ListForPosts(ctx context.Context, postIDs []uint, locales []string) ([]*domain.PostTranslation, error) {
+	var translations []*domain.PostTranslation
+	if len(postIDs) == 0 || len(locales) == 0 {
+		return translations, nil
+	}
+
+	if err := r.db.WithContext(ctx).
+		Where("post_id IN ? AND locale IN ?", postIDs, locales).
+		Find(&translations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list translations: %w", err)
+	}
+
+	return translations, nil
+}
+
+func (r *postTranslationRepository) Create(ctx context.Context, translation *domain.PostTranslation) error {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.PostTranslation{}).
+		Where("post_id = ? AND locale = ?", translation.PostID, translation.Locale).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check translation existence: %w", err)
+	}
+
+	if count > 0 {
+		return repository.ErrAlreadyExists
+	}
+
+	translation.Version = 1
+	if err := r.db.WithContext(ctx).Create(translation).Error; err != nil {
+		return fmt.Errorf("failed to create translation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postTranslationRepository) Update(ctx context.Context, translation *domain.PostTranslation) error {
+	current := translation.Version
+
+	// Only update the row if nobody else has saved it since it was read
+	translation.Version = current + 1
+	res := r.db.WithContext(ctx).Model(translation).
+		Where("version = ?", current).
+		Select("Name", "Description", "Format", "DescriptionHTML", "UpdatedAt", "Version").
+		Updates(translation)
+	if res.Error != nil {
+		translation.Version = current
+		return fmt.Errorf("failed to update translation: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		translation.Version = current
+		return repository.ErrConflict
+	}
+
+	return nil
+}
+
+func (r *postTranslationRepository) Delete(ctx context.Context, postID uint, locale string) error {
+	res := r.db.WithContext(ctx).
+		Where("post_id = ? AND locale = ?", postID, locale).
+		Delete(&domain.PostTranslation{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete translation: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
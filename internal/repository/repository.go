@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/yakuter/ugin/internal/domain"
 )
@@ -12,11 +13,69 @@ var (
 	ErrNotFound      = errors.New("record not found")
 	ErrAlreadyExists = errors.New("record already exists")
 	ErrInvalidInput  = errors.New("invalid input")
+	ErrConflict      = errors.New("record was modified concurrently")
 )
 
+// BatchError reports the post whose write made a batch fail. Post is nil
+// when the failing write cannot be attributed to a single post.
+type BatchError struct {
+	Post *domain.Post
+	Err  error
+}
+
+func (e *BatchError) Error() string { return e.Err.Error() }
+
+func (e *BatchError) Unwrap() error { return e.Err }
+
+// PostBatch groups post writes that are applied together
+type PostBatch struct {
+	// Create holds new posts, inserted in chunks
+	Create []*domain.Post
+	// Update holds posts whose Version is the stored version they replace
+	Update []*domain.Post
+	// Delete holds posts to remove by ID; a non-zero Version must match
+	Delete []*domain.Post
+	// Records holds what is written along with created and updated posts
+	Records map[*domain.Post]*PostRecords
+}
+
+// PostRecords are written together with a post, in the transaction of its batch
+type PostRecords struct {
+	// Baseline is stored first when the post has no revisions yet, so
+	// changes to posts saved before revisions were kept can be undone
+	Baseline *domain.PostRevision
+	// Revision is stored as the next revision of the post
+	Revision *domain.PostRevision
+	// Owner is the email of the user added as the post's owner, if any
+	Owner string
+	// Hold queues the post for a moderator, if it was flagged
+	Hold *domain.ModerationItem
+}
+
+// SortPopularity sorts posts by their number of reactions and bookmarks
+const SortPopularity = "popularity"
+
 // ListFilter contains common filtering options
 type ListFilter struct {
 	Search string
+	// Category limits the list to posts in the category with this public ID
+	// or slug and its descendants
+	Category string
+	// Tag limits the list to posts with a tag of this name, ignoring case
+	Tag string
+	// Status limits the list to posts in this review status; empty lists
+	// posts in every status
+	Status string
+	// Contributor limits the list to posts the user with this email has a
+	// role on, one of Roles when they are given, and to published posts
+	// as well when IncludePublished is set
+	Contributor      string
+	Roles            []string
+	IncludePublished bool
+	// Year and Month limit the list to posts created in that year, or that
+	// month of it when Month is set, in UTC
+	Year   int
+	Month  int
 	Limit  int
 	Offset int
 	Sort   string
@@ -31,11 +90,222 @@ type ListResult struct {
 
 // PostRepository defines the interface for post data access
 type PostRepository interface {
+	// GetByID returns the post with the given public ID or current slug
 	GetByID(ctx context.Context, id string) (*domain.Post, error)
+	// GetByPreviousSlug returns the post that used slug before it was renamed
+	GetByPreviousSlug(ctx context.Context, slug string) (*domain.Post, error)
+	// SlugTaken reports whether a post other than postID uses or used slug, including deleted posts
+	SlugTaken(ctx context.Context, slug string, postID uint) (bool, error)
+	// ListWithoutSlug returns up to limit posts, including deleted ones, that have no slug yet
+	ListWithoutSlug(ctx context.Context, limit int) ([]*domain.Post, error)
+	SetSlug(ctx context.Context, id uint, slug string) error
+	// ListUnrendered returns up to limit posts, including deleted ones, whose
+	// description was saved before rendered HTML was cached
+	ListUnrendered(ctx context.Context, limit int) ([]*domain.Post, error)
+	SetDescriptionHTML(ctx context.Context, id uint, html string) error
 	List(ctx context.Context, filter ListFilter) ([]*domain.Post, *ListResult, error)
+	// Archive counts the posts in status, or in every status when empty,
+	// per month they were created in, newest month first
+	Archive(ctx context.Context, status string) ([]*domain.ArchiveMonth, error)
+	// Stream calls fn with every post matching the Status and Contributor
+	// of filter, and its tags, in ID order, reading rows through a database
+	// cursor. It stops at the first error fn returns.
+	Stream(ctx context.Context, filter ListFilter, fn func(*domain.Post) error) error
+	// ListChangedSince returns up to limit posts with an ID above afterID,
+	// in ID order, that were saved or deleted at or after since. Deleted
+	// posts are included without their tags.
+	ListChangedSince(ctx context.Context, since time.Time, afterID uint, limit int) ([]*domain.Post, error)
 	Create(ctx context.Context, post *domain.Post) error
+	// Update saves post only if its stored version still equals post.Version
+	// and increments the version, returning ErrConflict otherwise
 	Update(ctx context.Context, post *domain.Post) error
-	Delete(ctx context.Context, id string) error
+	// Delete removes the post; a non-zero version must match the stored one
+	Delete(ctx context.Context, id uint, version uint) error
+	// ApplyBatch writes the whole batch in one transaction. Any failure rolls
+	// back every write and is returned as a *BatchError.
+	ApplyBatch(ctx context.Context, batch *PostBatch) error
+	// ListDeleted returns the posts in the trash matching the Search and
+	// Contributor of filter, most recently deleted first
+	ListDeleted(ctx context.Context, filter ListFilter) ([]*domain.Post, *ListResult, error)
+	// GetDeleted returns the deleted post with the given public ID
+	GetDeleted(ctx context.Context, id string) (*domain.Post, error)
+	// Restore brings back the deleted post with the given public ID
+	Restore(ctx context.Context, id string) error
+	// Purge permanently removes posts that were soft-deleted before the given time
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+// PostRevisionRepository defines the interface for post revision data access
+type PostRevisionRepository interface {
+	// Create stores rev as the next revision of its post and sets rev.Revision
+	Create(ctx context.Context, rev *domain.PostRevision) error
+	Get(ctx context.Context, postID uint, revision int) (*domain.PostRevision, error)
+	ListByPostID(ctx context.Context, postID uint) ([]*domain.PostRevision, error)
+	CountByPostID(ctx context.Context, postID uint) (int64, error)
+}
+
+// PostTranslationRepository defines the interface for post translation data access
+type PostTranslationRepository interface {
+	// Get returns the post's translation to locale
+	Get(ctx context.Context, postID uint, locale string) (*domain.PostTranslation, error)
+	// ListByPostID returns the post's translations ordered by locale
+	ListByPostID(ctx context.Context, postID uint) ([]*domain.PostTranslation, error)
+	// ListForPosts returns the translations of the posts to any of locales
+	ListForPosts(ctx context.Context, postIDs []uint, locales []string) ([]*domain.PostTranslation, error)
+	// Create stores a new translation, returning ErrAlreadyExists when the
+	// post already has one to its locale
+	Create(ctx context.Context, translation *domain.PostTranslation) error
+	// Update saves the translation only if its stored version still equals
+	// translation.Version and increments the version, returning ErrConflict otherwise
+	Update(ctx context.Context, translation *domain.PostTranslation) error
+	Delete(ctx context.Context, postID uint, locale string) error
+}
+
+// CommentRepository defines the interface for comment data access
+type CommentRepository interface {
+	// GetByID returns the comment with the given public ID
+	GetByID(ctx context.Context, id string) (*domain.Comment, error)
+	// ListByPostID returns the post's comments in the given states, oldest
+	// first; without states it returns all of them
+	ListByPostID(ctx context.Context, postID uint, statuses ...string) ([]*domain.Comment, error)
+	Create(ctx context.Context, comment *domain.Comment) error
+	Update(ctx context.Context, comment *domain.Comment) error
+	// Delete removes the comment together with all replies below it and
+	// returns how many comments were removed
+	Delete(ctx context.Context, id uint) (int64, error)
+}
+
+// AttachmentRepository defines the interface for attachment data access
+type AttachmentRepository interface {
+	// GetByID returns the attachment with the given public ID and its derivatives
+	GetByID(ctx context.Context, id string) (*domain.Attachment, error)
+	// ListByPostID returns the post's attachments of kind with their
+	// derivatives, oldest first; an empty kind returns all
+	ListByPostID(ctx context.Context, postID uint, kind string) ([]*domain.Attachment, error)
+	Create(ctx context.Context, attachment *domain.Attachment) error
+	// Delete removes the attachment and its derivatives
+	Delete(ctx context.Context, id uint) error
+	// ListOrphaned returns up to limit attachments whose post was purged, with their derivatives
+	ListOrphaned(ctx context.Context, limit int) ([]*domain.Attachment, error)
+}
+
+// EngagementRepository defines the interface for reaction and bookmark data access
+type EngagementRepository interface {
+	// SetReaction stores the reaction, replacing the emoji of any earlier
+	// reaction of its user to the post, and reports whether it is new
+	SetReaction(ctx context.Context, reaction *domain.Reaction) (bool, error)
+	DeleteReaction(ctx context.Context, userID, postID uint) error
+	// AddBookmark stores the bookmark, returning ErrAlreadyExists when the
+	// user already bookmarked the post
+	AddBookmark(ctx context.Context, bookmark *domain.Bookmark) error
+	DeleteBookmark(ctx context.Context, userID, postID uint) error
+	// ListBookmarked returns the posts the user bookmarked that are
+	// published or that the user contributes to, most recently bookmarked
+	// first, along with how many there are
+	ListBookmarked(ctx context.Context, userID uint, limit, offset int) ([]*domain.Post, int64, error)
+}
+
+// AnalyticsRepository defines the interface for post view data access
+type AnalyticsRepository interface {
+	// AddViews adds the views to the daily counts of their posts
+	AddViews(ctx context.Context, views []*domain.PostDailyViews) error
+	// TotalViews returns every recorded view of the post
+	TotalViews(ctx context.Context, postID uint) (int64, error)
+	// DailyViews returns the post's daily counts of the days from from up
+	// to but excluding to, oldest first; days without views are left out
+	DailyViews(ctx context.Context, postID uint, from, to time.Time) ([]*domain.PostDailyViews, error)
+	// TopPosts returns up to limit posts not in the trash, most viewed since
+	// the day from first
+	TopPosts(ctx context.Context, from time.Time, limit int) ([]*domain.PostViewCount, error)
+}
+
+// ContributorRepository defines the interface for post contributor data access
+type ContributorRepository interface {
+	// List returns the contributors of the post in the order they were added
+	List(ctx context.Context, postID uint) ([]*domain.PostContributor, error)
+	// Add gives the user with the given email a role on the post, returning
+	// ErrNotFound when there is no such user and ErrAlreadyExists when the
+	// user already contributes to the post
+	Add(ctx context.Context, contributor *domain.PostContributor, email string) error
+	// Remove takes the user with the given public ID off the post. Removing
+	// the last owner returns ErrConflict.
+	Remove(ctx context.Context, postID uint, userID string) error
+}
+
+// ReviewRepository defines the interface for post review data access
+type ReviewRepository interface {
+	// List returns the review history of the post, oldest first
+	List(ctx context.Context, postID uint) ([]*domain.PostReview, error)
+	// Add records a review step that leaves the post as it is
+	Add(ctx context.Context, review *domain.PostReview) error
+	// Transition saves the post's status and reviewer together with the
+	// review step that changed them, bumping the post's version. The stored
+	// version must still be post.Version, otherwise ErrConflict is returned.
+	Transition(ctx context.Context, post *domain.Post, review *domain.PostReview) error
+}
+
+// ModerationRepository defines the interface for moderation queue data access
+type ModerationRepository interface {
+	// GetByID returns the moderation item with the given public ID
+	GetByID(ctx context.Context, id string) (*domain.ModerationItem, error)
+	// List returns items in status, oldest first, along with how many
+	// there are in that status
+	List(ctx context.Context, status string, limit, offset int) ([]*domain.ModerationItem, int64, error)
+	// GetPending returns the pending item of the content of kind with the
+	// given ID, or ErrNotFound when it does not wait for a moderator
+	GetPending(ctx context.Context, kind string, targetID uint) (*domain.ModerationItem, error)
+	// Hold queues item as pending. When its content already waits for a
+	// moderator, that item takes the new excerpt and reasons instead.
+	Hold(ctx context.Context, item *domain.ModerationItem) error
+	// Resolve saves the decision on a pending item, returning ErrConflict
+	// when the item was decided meanwhile
+	Resolve(ctx context.Context, item *domain.ModerationItem) error
+}
+
+// SeriesRepository defines the interface for series data access
+type SeriesRepository interface {
+	// GetByID returns the series with the given public ID
+	GetByID(ctx context.Context, id string) (*domain.Series, error)
+	// List returns every series ordered by name
+	List(ctx context.Context) ([]*domain.Series, error)
+	Create(ctx context.Context, series *domain.Series) error
+	Update(ctx context.Context, series *domain.Series) error
+	// Delete removes the series, leaving its posts in no series
+	Delete(ctx context.Context, id uint) error
+	// Entries returns the published posts of the series not in the trash
+	// in reading order
+	Entries(ctx context.Context, seriesID uint) ([]domain.SeriesEntry, error)
+	// AddPost inserts the post at the 1-based position among the entries of
+	// the series, or appends it when position is zero or past the end. A
+	// post already in a series returns ErrAlreadyExists.
+	AddPost(ctx context.Context, seriesID, postID uint, position int) error
+	// RemovePost takes the post out of the series, returning ErrNotFound
+	// when it is not in it
+	RemovePost(ctx context.Context, seriesID, postID uint) error
+	// Reorder puts the entries of the series in the order of the given post
+	// public IDs, which must list each of them once; trashed and unpublished
+	// posts of the series follow them. Any other list returns ErrInvalidInput.
+	Reorder(ctx context.Context, seriesID uint, postIDs []string) error
+}
+
+// CategoryRepository defines the interface for category data access
+type CategoryRepository interface {
+	// GetByID returns the category with the given public ID or slug
+	GetByID(ctx context.Context, id string) (*domain.Category, error)
+	// List returns every category ordered by depth and name
+	List(ctx context.Context) ([]*domain.Category, error)
+	// SlugTaken reports whether a category other than categoryID uses slug
+	SlugTaken(ctx context.Context, slug string, categoryID uint) (bool, error)
+	// Create stores the category below its ParentID and sets its path and depth
+	Create(ctx context.Context, category *domain.Category) error
+	Update(ctx context.Context, category *domain.Category) error
+	// Move makes parent, or nothing for a root, the parent of the category
+	// and moves its whole subtree along. Moving a category below itself
+	// returns ErrInvalidInput.
+	Move(ctx context.Context, id uint, parent *domain.Category) error
+	// Delete removes a category without subcategories or posts, returning
+	// ErrConflict otherwise. Trashed posts in it become uncategorized.
+	Delete(ctx context.Context, id uint) error
 }
 
 // UserRepository defines the interface for user data access
@@ -46,4 +316,3 @@ type UserRepository interface {
 	Update(ctx context.Context, user *domain.User) error
 	Delete(ctx context.Context, id uint) error
 }
-
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+)
+
+const (
+	// maxRangeDays is the longest range statistics are given for
+	maxRangeDays = 366
+	// flushTimeout bounds the final flush when recording stops
+	flushTimeout = 5 * time.Second
+)
+
+// AnalyticsConfig holds view recording configuration
+type AnalyticsConfig struct {
+	// DedupeWindow is how long repeated views of a post by one visitor
+	// count as a single view
+	DedupeWindow time.Duration
+	// FlushInterval is how often buffered views are written
+	FlushInterval time.Duration
+	// BatchSize is the number of distinct post days buffered before they
+	// are written ahead of the interval
+	BatchSize int
+	// BufferSize is the number of views queued for the recorder; views
+	// arriving while the queue is full are dropped
+	BufferSize int
+}
+
+// view is a view waiting to be counted
+type view struct {
+	postID  uint
+	visitor uint64
+	at      time.Time
+}
+
+type visit struct {
+	postID  uint
+	visitor uint64
+}
+
+type postDay struct {
+	postID uint
+	day    time.Time
+}
+
+type analyticsService struct {
+	views        repository.AnalyticsRepository
+	posts        repository.PostRepository
+	contributors repository.ContributorRepository
+	cfg          AnalyticsConfig
+	logger       Logger
+	queue        chan view
+	dropped      atomic.Int64
+}
+
+// NewAnalyticsService creates a new view analytics service. Views are only
+// counted while Run is running.
+func NewAnalyticsService(views repository.AnalyticsRepository, posts repository.PostRepository, contributors repository.ContributorRepository, cfg *AnalyticsConfig, logger Logger) AnalyticsService {
+	s := &analyticsService{
+		views:        views,
+		posts:        posts,
+		contributors: contributors,
+		cfg:          *cfg,
+		logger:       logger,
+	}
+	if s.cfg.FlushInterval <= 0 {
+		s.cfg.FlushInterval = 10 * time.Second
+	}
+	if s.cfg.BatchSize <= 0 {
+		s.cfg.BatchSize = 500
+	}
+	if s.cfg.BufferSize <= 0 {
+		s.cfg.BufferSize = 10000
+	}
+	s.queue = make(chan view, s.cfg.BufferSize)
+	return s
+}
+
+func (s *analyticsService) RecordView(postID uint, visitor string) {
+	h := fnv.New64a()
+	h.Write([]byte(visitor))
+
+	select {
+	case s.queue <- view{postID: postID, visitor: h.Sum64(), at: time.Now()}:
+	default:
+		// Reads must never wait for the recorder
+		s.dropped.Add(1)
+	}
+}
+
+func (s *analyticsService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	pending := make(map[postDay]int64)
+	seen := make(map[visit]time.Time)
+	// After a failed write, full batches wait for the next tick instead of
+	// retrying with every view
+	failed := false
+
+	count := func(v view) {
+		// A visitor's views count once per window, starting with the first
+		key := visit{postID: v.postID, visitor: v.visitor}
+		if first, ok := seen[key]; ok && v.at.Sub(first) < s.cfg.DedupeWindow {
+			return
+		}
+		seen[key] = v.at
+		pending[postDay{postID: v.postID, day: startOfDay(v.at)}]++
+	}
+
+	for {
+		select {
+		case v := <-s.queue:
+			count(v)
+			if len(pending) >= s.cfg.BatchSize && !failed {
+				failed = !s.flush(ctx, pending)
+			}
+
+		case now := <-ticker.C:
+			failed = !s.flush(ctx, pending)
+			for key, first := range seen {
+				if now.Sub(first) >= s.cfg.DedupeWindow {
+					delete(seen, key)
+				}
+			}
+
+		case <-ctx.Done():
+			// Count what is still queued and write it with a fresh context
+			for len(s.queue) > 0 {
+				count(<-s.queue)
+			}
+			flushCtx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+			s.flush(flushCtx, pending)
+			cancel()
+			return
+		}
+	}
+}
+
+// flush writes the pending views, keeping them for the next flush if the
+// write fails, and reports whether it succeeded
+func (s *analyticsService) flush(ctx context.Context, pending map[postDay]int64) bool {
+	if dropped := s.dropped.Swap(0); dropped > 0 {
+		s.logger.Warn("view queue full, views dropped", "dropped", dropped)
+	}
+	if len(pending) == 0 {
+		return true
+	}
+
+	views := make([]*domain.PostDailyViews, 0, len(pending))
+	for key, n := range pending {
+		views = append(views, &domain.PostDailyViews{PostID: key.postID, Day: key.day, Views: n})
+	}
+
+	if err := s.views.AddViews(ctx, views); err != nil {
+		s.logger.Error("failed to record views", "post_days", len(views), "error", err)
+		return false
+	}
+
+	for key := range pending {
+		delete(pending, key)
+	}
+	s.logger.Debug("views recorded", "post_days", len(views))
+	return true
+}
+
+func (s *analyticsService) Stats(ctx context.Context, postID string, rng string) (*domain.PostStats, error) {
+	days, err := parseRange(rng, 30)
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.views.TotalViews(ctx, post.ID)
+	if err != nil {
+		s.logger.Error("failed to get total views", "post_id", postID, "error", err)
+		return nil, fmt.Errorf("get total views: %w", err)
+	}
+
+	to := startOfDay(time.Now()).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -days)
+	rows, err := s.views.DailyViews(ctx, post.ID, from, to)
+	if err != nil {
+		s.logger.Error("failed to get daily views", "post_id", postID, "error", err)
+		return nil, fmt.Errorf("get daily views: %w", err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Day.UTC().Format(time.DateOnly)] = row.Views
+	}
+
+	// Days without views are filled in so the series has no gaps
+	stats := &domain.PostStats{PostID: post.PublicID, TotalViews: total, Daily: make([]domain.DailyViews, 0, days)}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		stats.Daily = append(stats.Daily, domain.DailyViews{Date: date, Views: counts[date]})
+		stats.Views += counts[date]
+	}
+
+	return stats, nil
+}
+
+func (s *analyticsService) TopPosts(ctx context.Context, rng string, limit int) ([]*domain.PostViewCount, error) {
+	days, err := parseRange(rng, 7)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100 // Max limit
+	}
+
+	from := startOfDay(time.Now()).AddDate(0, 0, 1-days)
+	top, err := s.views.TopPosts(ctx, from, limit)
+	if err != nil {
+		s.logger.Error("failed to list top posts", "range", rng, "error", err)
+		return nil, fmt.Errorf("list top posts: %w", err)
+	}
+
+	return top, nil
+}
+
+func (s *analyticsService) getPost(ctx context.Context, id string) (*domain.Post, error) {
+	if id == "" {
+		return nil, repository.ErrInvalidInput
+	}
+
+	post, err := s.posts.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get post", "id", id, "error", err)
+		return nil, fmt.Errorf("get post: %w", err)
+	}
+
+	if err := readable(ctx, s.contributors, s.logger, post); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// parseRange returns the number of days of a range such as "7d", which
+// must include today, or def for an empty range
+func parseRange(rng string, def int) (int, error) {
+	if rng == "" {
+		return def, nil
+	}
+
+	days, err := strconv.Atoi(strings.TrimSuffix(rng, "d"))
+	if err != nil || !strings.HasSuffix(rng, "d") || days < 1 || days > maxRangeDays {
+		return 0, fmt.Errorf("%w: range must be a number of days from 1d to %dd", repository.ErrInvalidInput, maxRangeDays)
+	}
+	return days, nil
+}
+
+// startOfDay returns the start of the UTC day t falls on
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
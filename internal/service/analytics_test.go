@@ -0,0 +1,191 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+// mockAnalyticsRepository records the views written to it and fails the
+// first failures writes
+type mockAnalyticsRepository struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	views    map[uint]int64
+}
+
+func (m *mockAnalyticsRepository) AddViews(ctx context.Context, views []*domain.PostDailyViews) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls++
+	if m.calls <= m.failures {
+		return errors.New("database unavailable")
+	}
+	if m.views == nil {
+		m.views = make(map[uint]int64)
+	}
+	for _, v := range views {
+		m.views[v.PostID] += v.Views
+	}
+	return nil
+}
+
+func (m *mockAnalyticsRepository) TotalViews(ctx context.Context, postID uint) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockAnalyticsRepository) DailyViews(ctx context.Context, postID uint, from, to time.Time) ([]*domain.PostDailyViews, error) {
+	return nil, nil
+}
+
+func (m *mockAnalyticsRepository) TopPosts(ctx context.Context, from time.Time, limit int) ([]*domain.PostViewCount, error) {
+	return nil, nil
+}
+
+func (m *mockAnalyticsRepository) snapshot() (int, map[uint]int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	views := make(map[uint]int64, len(m.views))
+	for id, n := range m.views {
+		views[id] = n
+	}
+	return m.calls, views
+}
+
+func TestAnalyticsService_Run(t *testing.T) {
+	type pageView struct {
+		postID  uint
+		visitor string
+		// pause is waited before the view
+		pause time.Duration
+	}
+
+	tests := []struct {
+		name     string
+		cfg      service.AnalyticsConfig
+		failures int
+		// stopFirst queues the views before the recorder starts and stops
+		// it right away
+		stopFirst bool
+		views     []pageView
+		// wantRunning is the number of writes made before the recorder stops
+		wantRunning int
+		wantCalls   int
+		wantViews   map[uint]int64
+	}{
+		{
+			name:      "buffers until stopped",
+			cfg:       service.AnalyticsConfig{BatchSize: 100},
+			views:     []pageView{{postID: 1, visitor: "a"}, {postID: 2, visitor: "b"}, {postID: 1, visitor: "c"}},
+			wantCalls: 1,
+			wantViews: map[uint]int64{1: 2, 2: 1},
+		},
+		{
+			name:      "drains the queue on shutdown",
+			cfg:       service.AnalyticsConfig{BatchSize: 100},
+			stopFirst: true,
+			views:     []pageView{{postID: 1, visitor: "a"}, {postID: 1, visitor: "b"}, {postID: 3, visitor: "a"}},
+			wantCalls: 1,
+			wantViews: map[uint]int64{1: 2, 3: 1},
+		},
+		{
+			name:      "dedupes a visitor within the window",
+			cfg:       service.AnalyticsConfig{BatchSize: 100, DedupeWindow: time.Hour},
+			views:     []pageView{{postID: 1, visitor: "a"}, {postID: 1, visitor: "a"}, {postID: 1, visitor: "b"}, {postID: 2, visitor: "a"}},
+			wantCalls: 1,
+			wantViews: map[uint]int64{1: 2, 2: 1},
+		},
+		{
+			name:      "counts a visitor again after the window",
+			cfg:       service.AnalyticsConfig{BatchSize: 100, DedupeWindow: 30 * time.Millisecond},
+			views:     []pageView{{postID: 1, visitor: "a"}, {postID: 1, visitor: "a"}, {postID: 1, visitor: "a", pause: 60 * time.Millisecond}},
+			wantCalls: 1,
+			wantViews: map[uint]int64{1: 2},
+		},
+		{
+			name:        "flushes a full batch",
+			cfg:         service.AnalyticsConfig{BatchSize: 2},
+			views:       []pageView{{postID: 1, visitor: "a"}, {postID: 2, visitor: "a"}},
+			wantRunning: 1,
+			wantCalls:   1,
+			wantViews:   map[uint]int64{1: 1, 2: 1},
+		},
+		{
+			name:        "keeps views after a failed flush and backs off",
+			cfg:         service.AnalyticsConfig{BatchSize: 1},
+			failures:    1,
+			views:       []pageView{{postID: 1, visitor: "a"}, {postID: 2, visitor: "a"}, {postID: 3, visitor: "a"}},
+			wantRunning: 1,
+			wantCalls:   2,
+			wantViews:   map[uint]int64{1: 1, 2: 1, 3: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Ticks never come during the test, so only batches and
+			// shutdown write views
+			cfg := tt.cfg
+			cfg.FlushInterval = time.Hour
+			repo := &mockAnalyticsRepository{failures: tt.failures}
+			svc := service.NewAnalyticsService(repo, &mockPostRepository{}, &mockContributorRepository{}, &cfg, &mockLogger{})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			run := func() {
+				go func() {
+					svc.Run(ctx)
+					close(done)
+				}()
+			}
+
+			if tt.stopFirst {
+				for _, v := range tt.views {
+					svc.RecordView(v.postID, v.visitor)
+				}
+				cancel()
+				run()
+			} else {
+				run()
+				for _, v := range tt.views {
+					time.Sleep(v.pause)
+					svc.RecordView(v.postID, v.visitor)
+				}
+
+				// Give the recorder time to take the views off the queue
+				time.Sleep(50 * time.Millisecond)
+				if calls, _ := repo.snapshot(); calls != tt.wantRunning {
+					t.Errorf("expected %d writes while running, got %d", tt.wantRunning, calls)
+				}
+				cancel()
+			}
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("recorder did not stop")
+			}
+
+			calls, views := repo.snapshot()
+			if calls != tt.wantCalls {
+				t.Errorf("expected %d writes, got %d", tt.wantCalls, calls)
+			}
+			if len(views) != len(tt.wantViews) {
+				t.Errorf("expected views %v, got %v", tt.wantViews, views)
+			}
+			for id, want := range tt.wantViews {
+				if views[id] != want {
+					t.Errorf("expected %d views of post %d, got %d", want, id, views[id])
+				}
+			}
+		})
+	}
+}
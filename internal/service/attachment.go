@@ -0,0 +1,516 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/pkg/blob"
+	"github.com/yakuter/ugin/pkg/imaging"
+	"github.com/yakuter/ugin/pkg/signedurl"
+	"github.com/yakuter/ugin/pkg/uid"
+)
+
+var (
+	// ErrTooLarge is returned for uploads above the configured size limit
+	ErrTooLarge = errors.New("file is too large")
+	// ErrUnsupportedType is returned for uploads whose content type is not allowed
+	ErrUnsupportedType = errors.New("file type is not allowed")
+)
+
+// sniffLength is the number of leading bytes inspected to detect the content type
+const sniffLength = 512
+
+// orphanBatchSize is the number of orphaned attachments removed per query
+const orphanBatchSize = 100
+
+// coverTypes are the content types accepted for cover images
+var coverTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// AttachmentConfig holds attachment configuration
+type AttachmentConfig struct {
+	// MaxSize is the largest accepted upload in bytes
+	MaxSize int64
+	// AllowedTypes lists the sniffed media types accepted for attachments
+	AllowedTypes []string
+	// URLTTL is how long signed download URLs stay valid
+	URLTTL time.Duration
+	// SigningKey signs download URLs
+	SigningKey []byte
+	// DownloadPath is the route serving attachments, with ":id" standing for the attachment ID
+	DownloadPath string
+	// Derivatives configures the resized copies made of cover images
+	Derivatives DerivativeConfig
+}
+
+// DerivativeConfig configures the resized copies made of cover images
+type DerivativeConfig struct {
+	// Widths are the widths in pixels to resize to; covers are never enlarged
+	Widths []int
+	// Formats are the image formats each width is written in: jpeg, png
+	// and webp, which is lossless
+	Formats []string
+	// Quality is the JPEG quality from 1 to 100
+	Quality int
+}
+
+type attachmentService struct {
+	repo         repository.AttachmentRepository
+	posts        repository.PostRepository
+	contributors repository.ContributorRepository
+	store        BlobStore
+	signer       *signedurl.Signer
+	maxSize      int64
+	allowedTypes map[string]bool
+	urlTTL       time.Duration
+	downloadPath string
+	derivatives  DerivativeConfig
+	logger       Logger
+}
+
+// NewAttachmentService creates a new attachment service. Attachments are
+// uploaded and deleted by the owners and editors of their post.
+func NewAttachmentService(repo repository.AttachmentRepository, posts repository.PostRepository, contributors repository.ContributorRepository, store BlobStore, cfg *AttachmentConfig, logger Logger) AttachmentService {
+	allowed := make(map[string]bool, len(cfg.AllowedTypes))
+	for _, t := range cfg.AllowedTypes {
+		allowed[strings.ToLower(t)] = true
+	}
+
+	// Formats that cannot be encoded are skipped
+	derivatives := cfg.Derivatives
+	derivatives.Formats = nil
+	for _, format := range cfg.Derivatives.Formats {
+		format = strings.ToLower(format)
+		if format == "jpg" {
+			format = imaging.JPEG
+		}
+		if !imaging.Supported(format) {
+			logger.Warn("cover derivative format not supported, skipping", "format", format)
+			continue
+		}
+		derivatives.Formats = append(derivatives.Formats, format)
+	}
+	if derivatives.Quality <= 0 || derivatives.Quality > 100 {
+		derivatives.Quality = jpeg.DefaultQuality
+	}
+
+	return &attachmentService{
+		repo:         repo,
+		posts:        posts,
+		contributors: contributors,
+		store:        store,
+		signer:       signedurl.New(cfg.SigningKey),
+		maxSize:      cfg.MaxSize,
+		allowedTypes: allowed,
+		urlTTL:       cfg.URLTTL,
+		downloadPath: cfg.DownloadPath,
+		derivatives:  derivatives,
+		logger:       logger,
+	}
+}
+
+func (s *attachmentService) Upload(ctx context.Context, postID, kind, filename string, r io.Reader) (*domain.Attachment, error) {
+	if kind != domain.AttachmentFile && kind != domain.AttachmentCover {
+		return nil, fmt.Errorf("%w: unknown attachment kind %q", repository.ErrInvalidInput, kind)
+	}
+
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, post.ID); err != nil {
+		return nil, err
+	}
+
+	// Spool the upload to disk so its size is known before it is stored
+	// and nothing is kept when it turns out to be too large
+	tmp, err := os.CreateTemp("", "ugin-upload-*")
+	if err != nil {
+		s.logger.Error("failed to create upload file", "error", err)
+		return nil, fmt.Errorf("upload attachment: %w", err)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read upload: %w", repository.ErrInvalidInput, err)
+	}
+	if size > s.maxSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrTooLarge, s.maxSize)
+	}
+	if size == 0 {
+		return nil, fmt.Errorf("%w: file is empty", repository.ErrInvalidInput)
+	}
+
+	// The stored type is what the content looks like, not what the client claims
+	head := make([]byte, sniffLength)
+	n, err := tmp.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("upload attachment: %w", err)
+	}
+	contentType := http.DetectContentType(head[:n])
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	if kind == domain.AttachmentCover && !coverTypes[mediaType] {
+		return nil, fmt.Errorf("%w: cover must be a JPEG, PNG, GIF or WebP image, got %s", ErrUnsupportedType, mediaType)
+	}
+	if kind == domain.AttachmentFile && !s.allowedTypes[mediaType] {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, mediaType)
+	}
+
+	publicID, err := uid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("upload attachment: %w", err)
+	}
+
+	attachment := &domain.Attachment{
+		PublicID:    publicID,
+		PostID:      post.ID,
+		Kind:        kind,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        size,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		StorageKey:  "posts/" + post.PublicID + "/" + publicID,
+	}
+
+	var img image.Image
+	if kind == domain.AttachmentCover {
+		img = s.decodeCover(attachment, tmp)
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("upload attachment: %w", err)
+	}
+	if err := s.store.Put(ctx, attachment.StorageKey, tmp, size, contentType); err != nil {
+		s.logger.Error("failed to store attachment", "post_id", postID, "error", err)
+		return nil, fmt.Errorf("upload attachment: %w", err)
+	}
+
+	if err := s.repo.Create(ctx, attachment); err != nil {
+		s.logger.Error("failed to create attachment", "post_id", postID, "error", err)
+		s.deleteBlob(ctx, attachment)
+		return nil, fmt.Errorf("upload attachment: %w", err)
+	}
+
+	if kind == domain.AttachmentCover {
+		if img != nil {
+			s.createDerivatives(ctx, attachment, img)
+		}
+		if err := s.replaceCovers(ctx, post.ID, attachment.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logger.Info("attachment uploaded", "id", attachment.PublicID, "post_id", postID, "kind", kind, "size", size)
+	s.Sign(attachment)
+	return attachment, nil
+}
+
+func (s *attachmentService) List(ctx context.Context, postID string) ([]*domain.Attachment, error) {
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.repo.ListByPostID(ctx, post.ID, "")
+	if err != nil {
+		s.logger.Error("failed to list attachments", "post_id", postID, "error", err)
+		return nil, fmt.Errorf("list attachments: %w", err)
+	}
+
+	s.Sign(attachments...)
+	return attachments, nil
+}
+
+func (s *attachmentService) Delete(ctx context.Context, id string) error {
+	attachment, err := s.getAttachment(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorize(ctx, attachment.PostID); err != nil {
+		return err
+	}
+
+	if err := s.remove(ctx, attachment); err != nil {
+		return err
+	}
+
+	s.logger.Info("attachment deleted", "id", id)
+	return nil
+}
+
+func (s *attachmentService) Open(ctx context.Context, id, expires, signature string) (*domain.Attachment, io.ReadCloser, error) {
+	if err := s.signer.Verify(s.path(id), expires, signature, time.Now()); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrForbidden, err)
+	}
+
+	attachment, err := s.getAttachment(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.store.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			s.logger.Warn("attachment content missing", "id", id, "key", attachment.StorageKey)
+			return nil, nil, repository.ErrNotFound
+		}
+		s.logger.Error("failed to open attachment", "id", id, "error", err)
+		return nil, nil, fmt.Errorf("open attachment: %w", err)
+	}
+
+	return attachment, content, nil
+}
+
+func (s *attachmentService) Sign(attachments ...*domain.Attachment) {
+	expiresAt := time.Now().Add(s.urlTTL).Truncate(time.Second)
+	for _, attachment := range attachments {
+		if attachment == nil {
+			continue
+		}
+		attachment.URL = s.signer.Sign(s.path(attachment.PublicID), expiresAt)
+		attachment.URLExpiresAt = &expiresAt
+		s.Sign(attachment.Derivatives...)
+	}
+}
+
+func (s *attachmentService) DeleteOrphaned(ctx context.Context) (int, error) {
+	deleted := 0
+
+	for {
+		attachments, err := s.repo.ListOrphaned(ctx, orphanBatchSize)
+		if err != nil {
+			s.logger.Error("failed to list orphaned attachments", "error", err)
+			return deleted, fmt.Errorf("delete orphaned attachments: %w", err)
+		}
+		if len(attachments) == 0 {
+			break
+		}
+
+		for _, attachment := range attachments {
+			if err := s.remove(ctx, attachment); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+	}
+
+	if deleted > 0 {
+		s.logger.Info("deleted orphaned attachments", "count", deleted)
+	}
+	return deleted, nil
+}
+
+// replaceCovers removes every cover of the post except keep
+func (s *attachmentService) replaceCovers(ctx context.Context, postID uint, keep uint) error {
+	covers, err := s.repo.ListByPostID(ctx, postID, domain.AttachmentCover)
+	if err != nil {
+		s.logger.Error("failed to list covers", "post_id", postID, "error", err)
+		return fmt.Errorf("replace cover: %w", err)
+	}
+
+	for _, cover := range covers {
+		if cover.ID == keep {
+			continue
+		}
+		if err := s.remove(ctx, cover); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remove deletes the attachment's record and then its content. Content
+// that cannot be deleted is only logged; the record is what makes it reachable.
+func (s *attachmentService) remove(ctx context.Context, attachment *domain.Attachment) error {
+	if err := s.repo.Delete(ctx, attachment.ID); err != nil {
+		s.logger.Error("failed to delete attachment", "id", attachment.PublicID, "error", err)
+		return fmt.Errorf("delete attachment: %w", err)
+	}
+
+	s.deleteBlob(ctx, attachment)
+	for _, derivative := range attachment.Derivatives {
+		s.deleteBlob(ctx, derivative)
+	}
+	return nil
+}
+
+// decodeCover reads the cover image from src, upright, and records its
+// size. It returns nil when no derivatives are configured or the image
+// cannot be decoded, in which case the cover is kept without them.
+func (s *attachmentService) decodeCover(cover *domain.Attachment, src io.ReadSeeker) image.Image {
+	if len(s.derivatives.Widths) == 0 || len(s.derivatives.Formats) == 0 {
+		return nil
+	}
+
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		s.logger.Error("failed to read cover", "error", err)
+		return nil
+	}
+	img, err := imaging.Decode(src)
+	if err != nil {
+		s.logger.Warn("cannot decode cover, skipping derivatives", "content_type", cover.ContentType, "error", err)
+		return nil
+	}
+
+	cover.Width = img.Bounds().Dx()
+	cover.Height = img.Bounds().Dy()
+	return img
+}
+
+// createDerivatives stores resized copies of the cover image img, without
+// metadata. Failures are only logged, since the cover itself is usable.
+func (s *attachmentService) createDerivatives(ctx context.Context, cover *domain.Attachment, img image.Image) {
+	name := strings.TrimSuffix(cover.Filename, path.Ext(cover.Filename))
+
+	for _, width := range s.derivatives.Widths {
+		if width <= 0 || width >= cover.Width {
+			continue
+		}
+		resized := imaging.Resize(img, width)
+
+		for _, format := range s.derivatives.Formats {
+			var buf bytes.Buffer
+			if err := imaging.Encode(&buf, resized, format, s.derivatives.Quality); err != nil {
+				s.logger.Error("failed to encode cover derivative", "id", cover.PublicID, "width", width, "format", format, "error", err)
+				continue
+			}
+
+			suffix := "-" + strconv.Itoa(width) + "w" + imaging.Extension(format)
+			sum := sha256.Sum256(buf.Bytes())
+			derivative := &domain.Attachment{
+				PostID:      cover.PostID,
+				ParentID:    &cover.ID,
+				Kind:        domain.AttachmentDerivative,
+				Filename:    name + suffix,
+				ContentType: imaging.ContentType(format),
+				Size:        int64(buf.Len()),
+				SHA256:      hex.EncodeToString(sum[:]),
+				StorageKey:  cover.StorageKey + suffix,
+				Width:       resized.Bounds().Dx(),
+				Height:      resized.Bounds().Dy(),
+			}
+
+			if err := s.store.Put(ctx, derivative.StorageKey, &buf, derivative.Size, derivative.ContentType); err != nil {
+				s.logger.Error("failed to store cover derivative", "key", derivative.StorageKey, "error", err)
+				continue
+			}
+			if err := s.repo.Create(ctx, derivative); err != nil {
+				s.logger.Error("failed to create cover derivative", "id", cover.PublicID, "error", err)
+				s.deleteBlob(ctx, derivative)
+				continue
+			}
+			cover.Derivatives = append(cover.Derivatives, derivative)
+		}
+	}
+}
+
+func (s *attachmentService) deleteBlob(ctx context.Context, attachment *domain.Attachment) {
+	if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+		s.logger.Warn("failed to delete attachment content", "key", attachment.StorageKey, "error", err)
+	}
+}
+
+func (s *attachmentService) getPost(ctx context.Context, id string) (*domain.Post, error) {
+	if id == "" {
+		return nil, repository.ErrInvalidInput
+	}
+
+	post, err := s.posts.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get post", "id", id, "error", err)
+		return nil, fmt.Errorf("get post: %w", err)
+	}
+
+	if err := readable(ctx, s.contributors, s.logger, post); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// authorize checks that the actor owns or edits the post with the given
+// internal ID
+func (s *attachmentService) authorize(ctx context.Context, postID uint) error {
+	contributors, err := s.contributors.List(ctx, postID)
+	if err != nil {
+		s.logger.Error("failed to list contributors", "error", err)
+		return fmt.Errorf("list contributors: %w", err)
+	}
+
+	if !hasRole(ctx, contributors, domain.RoleOwner, domain.RoleEditor) {
+		s.logger.Info("attachment change forbidden", "actor", ActorFromContext(ctx))
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s *attachmentService) getAttachment(ctx context.Context, id string) (*domain.Attachment, error) {
+	if id == "" {
+		return nil, repository.ErrInvalidInput
+	}
+
+	attachment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get attachment", "id", id, "error", err)
+		return nil, fmt.Errorf("get attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// path returns the download route of the attachment with the given public ID
+func (s *attachmentService) path(id string) string {
+	return strings.Replace(s.downloadPath, ":id", id, 1)
+}
+
+// cleanFilename keeps the base name of an uploaded file without control characters
+func cleanFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	if len(name) > 255 {
+		name = strings.ToValidUTF8(name[len(name)-255:], "")
+	}
+	return name
+}
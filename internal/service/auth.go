@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -22,6 +23,7 @@ type authService struct {
 	jwtSecret             string
 	accessTokenDuration   time.Duration
 	refreshTokenDuration  time.Duration
+	admins                map[string]bool
 	logger                Logger
 }
 
@@ -30,15 +32,23 @@ type AuthConfig struct {
 	JWTSecret            string
 	AccessTokenDuration  time.Duration
 	RefreshTokenDuration time.Duration
+	// Admins lists the emails of users who may read and change any post
+	Admins []string
 }
 
 // NewAuthService creates a new authentication service
 func NewAuthService(userRepo repository.UserRepository, cfg *AuthConfig, logger Logger) AuthService {
+	admins := make(map[string]bool, len(cfg.Admins))
+	for _, email := range cfg.Admins {
+		admins[strings.ToLower(strings.TrimSpace(email))] = true
+	}
+
 	return &authService{
 		userRepo:             userRepo,
 		jwtSecret:            cfg.JWTSecret,
 		accessTokenDuration:  cfg.AccessTokenDuration,
 		refreshTokenDuration: cfg.RefreshTokenDuration,
+		admins:               admins,
 		logger:               logger,
 	}
 }
@@ -67,7 +77,7 @@ func (s *authService) SignIn(ctx context.Context, creds *domain.Credentials) (*d
 	}
 
 	// Generate tokens
-	tokenDetails, err := s.createTokens(user.Email, user.ID)
+	tokenDetails, err := s.createTokens(user.Email, user.PublicID)
 	if err != nil {
 		s.logger.Error("failed to create tokens", "email", creds.Email, "error", err)
 		return nil, fmt.Errorf("create tokens: %w", err)
@@ -124,13 +134,20 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*d
 		return nil, ErrInvalidToken
 	}
 
-	userID, ok := claims["user_id"].(float64)
-	if !ok {
-		return nil, ErrInvalidToken
+	// Tokens issued before users had public IDs carry no user_uuid, so
+	// the user is looked up to issue new ones
+	userUUID, _ := claims["user_uuid"].(string)
+	if userUUID == "" {
+		user, err := s.userRepo.GetByEmail(ctx, email)
+		if err != nil {
+			s.logger.Info("user of refresh token not found", "email", email, "error", err)
+			return nil, ErrInvalidToken
+		}
+		userUUID = user.PublicID
 	}
 
 	// Create new tokens
-	tokenDetails, err := s.createTokens(email, uint(userID))
+	tokenDetails, err := s.createTokens(email, userUUID)
 	if err != nil {
 		s.logger.Error("failed to refresh tokens", "email", email, "error", err)
 		return nil, fmt.Errorf("refresh token: %w", err)
@@ -163,10 +180,13 @@ func (s *authService) ValidateToken(ctx context.Context, tokenString string) (*d
 		Email:    email,
 		UserUUID: userUUID,
 		UUID:     uuid,
+		// Admins are looked up on every request, so removing one from the
+		// configuration takes effect without waiting for tokens to expire
+		Admin: s.admins[strings.ToLower(email)],
 	}, nil
 }
 
-func (s *authService) createTokens(email string, userID uint) (*domain.TokenDetails, error) {
+func (s *authService) createTokens(email, userUUID string) (*domain.TokenDetails, error) {
 	td := &domain.TokenDetails{}
 
 	now := time.Now()
@@ -175,10 +195,10 @@ func (s *authService) createTokens(email string, userID uint) (*domain.TokenDeta
 
 	// Create access token
 	atClaims := jwt.MapClaims{
-		"email":   email,
-		"user_id": userID,
-		"exp":     td.ATExpiresAt.Unix(),
-		"iat":     now.Unix(),
+		"email":     email,
+		"user_uuid": userUUID,
+		"exp":       td.ATExpiresAt.Unix(),
+		"iat":       now.Unix(),
 	}
 
 	at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
@@ -190,10 +210,10 @@ func (s *authService) createTokens(email string, userID uint) (*domain.TokenDeta
 
 	// Create refresh token
 	rtClaims := jwt.MapClaims{
-		"email":   email,
-		"user_id": userID,
-		"exp":     td.RTExpiresAt.Unix(),
-		"iat":     now.Unix(),
+		"email":     email,
+		"user_uuid": userUUID,
+		"exp":       td.RTExpiresAt.Unix(),
+		"iat":       now.Unix(),
 	}
 
 	rt := jwt.NewWithClaims(jwt.SigningMethodHS256, rtClaims)
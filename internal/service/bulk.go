@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+)
+
+// MaxBulkOperations is the largest number of operations accepted in one bulk request
+const MaxBulkOperations = 1000
+
+// ErrBulkAborted marks operations of an all-or-nothing bulk request that
+// were rolled back because another operation failed
+var ErrBulkAborted = errors.New("not applied: another operation failed")
+
+func (s *postService) Bulk(ctx context.Context, ops []domain.BulkOperation, atomic bool) ([]domain.BulkResult, error) {
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("%w: no operations given", repository.ErrInvalidInput)
+	}
+	if len(ops) > MaxBulkOperations {
+		return nil, fmt.Errorf("%w: at most %d operations allowed", repository.ErrInvalidInput, MaxBulkOperations)
+	}
+
+	if atomic {
+		return s.bulkAtomic(ctx, ops)
+	}
+
+	// Each operation stands on its own, so failures are only reported
+	results := make([]domain.BulkResult, len(ops))
+	failed := 0
+	for i, op := range ops {
+		results[i] = s.bulkApply(ctx, i, op)
+		if results[i].Err != nil {
+			failed++
+		}
+	}
+
+	s.logger.Info("bulk operations applied", "count", len(ops), "failed", failed)
+	return results, nil
+}
+
+// bulkApply runs a single operation through the regular create, update
+// and delete paths
+func (s *postService) bulkApply(ctx context.Context, index int, op domain.BulkOperation) domain.BulkResult {
+	result := domain.BulkResult{Index: index, Op: op.Op, ID: op.ID}
+
+	switch op.Op {
+	case domain.BulkCreate, domain.BulkUpdate:
+		if op.Post == nil {
+			result.Err = fmt.Errorf("%w: post is required", repository.ErrInvalidInput)
+			return result
+		}
+
+		post := *op.Post
+		if op.Op == domain.BulkCreate {
+			result.Err = s.Create(ctx, &post)
+		} else {
+			post.Version = op.Version
+			result.Err = s.Update(ctx, op.ID, &post)
+		}
+		if result.Err == nil {
+			result.ID = post.PublicID
+			result.Version = post.Version
+		}
+	case domain.BulkDelete:
+		result.Err = s.Delete(ctx, op.ID, op.Version)
+	default:
+		result.Err = fmt.Errorf("%w: unknown operation %q", repository.ErrInvalidInput, op.Op)
+	}
+
+	return result
+}
+
+// bulkAtomic validates every operation up front and writes them in a
+// single transaction, so either all of them are applied or none is
+func (s *postService) bulkAtomic(ctx context.Context, ops []domain.BulkOperation) ([]domain.BulkResult, error) {
+	results := make([]domain.BulkResult, len(ops))
+	for i, op := range ops {
+		results[i] = domain.BulkResult{Index: i, Op: op.Op, ID: op.ID}
+	}
+
+	fail := func(index int, err error) ([]domain.BulkResult, error) {
+		for i := range results {
+			results[i].Err = ErrBulkAborted
+		}
+		results[index].Err = err
+		s.logger.Info("bulk operations rolled back", "count", len(ops), "index", index, "error", err)
+		return results, err
+	}
+
+	batch := &repository.PostBatch{Records: make(map[*domain.Post]*repository.PostRecords, len(ops))}
+	indexes := make(map[*domain.Post]int, len(ops))
+	reserved := make(map[string]bool)
+	touched := make(map[uint]bool)
+
+	for i, op := range ops {
+		post, records, err := s.prepareBulk(ctx, op, reserved, touched)
+		if err != nil {
+			return fail(i, err)
+		}
+		indexes[post] = i
+
+		switch op.Op {
+		case domain.BulkCreate:
+			batch.Create = append(batch.Create, post)
+			batch.Records[post] = records
+		case domain.BulkUpdate:
+			batch.Update = append(batch.Update, post)
+			batch.Records[post] = records
+		case domain.BulkDelete:
+			batch.Delete = append(batch.Delete, post)
+		}
+	}
+
+	if err := s.repo.ApplyBatch(ctx, batch); err != nil {
+		index := 0
+		var batchErr *repository.BatchError
+		if errors.As(err, &batchErr) && batchErr.Post != nil {
+			index = indexes[batchErr.Post]
+		} else if len(batch.Create) > 0 {
+			index = indexes[batch.Create[0]]
+		}
+
+		if errors.Is(err, repository.ErrConflict) {
+			return fail(index, repository.ErrConflict)
+		}
+		s.logger.Error("failed to apply bulk operations", "error", err)
+		return fail(index, fmt.Errorf("bulk posts: %w", err))
+	}
+
+	for post, i := range indexes {
+		results[i].ID = post.PublicID
+		switch ops[i].Op {
+		case domain.BulkCreate:
+			results[i].Version = post.Version
+			s.logHeld(post, batch.Records[post])
+			s.publish(ctx, PostCreated, post)
+		case domain.BulkUpdate:
+			results[i].Version = post.Version
+			s.logHeld(post, batch.Records[post])
+			s.publish(ctx, PostUpdated, post)
+		case domain.BulkDelete:
+			s.publish(ctx, PostDeleted, post)
+		}
+	}
+
+	s.logger.Info("bulk operations applied", "count", len(ops), "atomic", true)
+	return results, nil
+}
+
+// prepareBulk checks one operation of an atomic bulk request the same way
+// as Create, Update and Delete, and returns the post to write along with
+// its records. Slugs handed out and posts already touched by earlier
+// operations are tracked in reserved and touched.
+func (s *postService) prepareBulk(ctx context.Context, op domain.BulkOperation, reserved map[string]bool, touched map[uint]bool) (*domain.Post, *repository.PostRecords, error) {
+	switch op.Op {
+	case domain.BulkCreate, domain.BulkUpdate:
+		if op.Post == nil {
+			return nil, nil, fmt.Errorf("%w: post is required", repository.ErrInvalidInput)
+		}
+	case domain.BulkDelete:
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown operation %q", repository.ErrInvalidInput, op.Op)
+	}
+
+	if op.Op == domain.BulkCreate {
+		post := *op.Post
+		post.Tags = append([]domain.Tag(nil), op.Post.Tags...)
+		records, err := s.prepareCreate(ctx, &post, reserved)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &post, records, nil
+	}
+
+	var (
+		existing *domain.Post
+		records  *repository.PostRecords
+		err      error
+	)
+	if op.Op == domain.BulkUpdate {
+		post := *op.Post
+		post.Version = op.Version
+		existing, records, err = s.prepareUpdate(ctx, op.ID, &post, reserved)
+	} else {
+		existing, err = s.prepareDelete(ctx, op.ID, op.Version)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A second write to the same post would always see a stale version
+	if touched[existing.ID] {
+		return nil, nil, fmt.Errorf("%w: post %s appears more than once", repository.ErrInvalidInput, op.ID)
+	}
+	touched[existing.ID] = true
+
+	if op.Op == domain.BulkDelete {
+		existing.Version = op.Version
+	}
+	return existing, records, nil
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/pkg/slug"
+	"github.com/yakuter/ugin/pkg/uid"
+)
+
+// maxCategoryDepth is the number of levels allowed below a root category
+const maxCategoryDepth = 8
+
+type categoryService struct {
+	categories repository.CategoryRepository
+	logger     Logger
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(categories repository.CategoryRepository, logger Logger) CategoryService {
+	return &categoryService{
+		categories: categories,
+		logger:     logger,
+	}
+}
+
+func (s *categoryService) Tree(ctx context.Context) ([]*domain.Category, int, error) {
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		s.logger.Error("failed to list categories", "error", err)
+		return nil, 0, fmt.Errorf("list categories: %w", err)
+	}
+
+	return buildTree(categories), len(categories), nil
+}
+
+func (s *categoryService) GetByID(ctx context.Context, id string) (*domain.Category, error) {
+	category, err := s.getCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The tree is small enough to read whole; the category's subtree hangs below it
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		s.logger.Error("failed to list categories", "error", err)
+		return nil, fmt.Errorf("get category: %w", err)
+	}
+	buildTree(categories)
+
+	for _, c := range categories {
+		if c.ID == category.ID {
+			return c, nil
+		}
+	}
+	return category, nil
+}
+
+func (s *categoryService) Create(ctx context.Context, req *domain.CreateCategoryRequest) (*domain.Category, error) {
+	if req == nil {
+		return nil, repository.ErrInvalidInput
+	}
+	if ActorFromContext(ctx) == "" {
+		return nil, ErrForbidden
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", repository.ErrInvalidInput)
+	}
+
+	category := &domain.Category{
+		Name:        name,
+		Description: req.Description,
+	}
+
+	if req.ParentID != "" {
+		parent, err := s.getParent(ctx, req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.Depth >= maxCategoryDepth {
+			return nil, fmt.Errorf("%w: categories may be nested at most %d levels deep", repository.ErrInvalidInput, maxCategoryDepth)
+		}
+		category.ParentID = &parent.ID
+		category.ParentPublicID = parent.PublicID
+	}
+
+	categorySlug, err := s.uniqueSlug(ctx, name, 0)
+	if err != nil {
+		return nil, err
+	}
+	category.Slug = categorySlug
+
+	if err := s.categories.Create(ctx, category); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: parent category not found", repository.ErrInvalidInput)
+		}
+		s.logger.Error("failed to create category", "error", err)
+		return nil, fmt.Errorf("create category: %w", err)
+	}
+
+	s.logger.Info("category created", "id", category.PublicID, "name", category.Name)
+	return category, nil
+}
+
+func (s *categoryService) Update(ctx context.Context, id string, req *domain.UpdateCategoryRequest) (*domain.Category, error) {
+	if req == nil {
+		return nil, repository.ErrInvalidInput
+	}
+	if ActorFromContext(ctx) == "" {
+		return nil, ErrForbidden
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", repository.ErrInvalidInput)
+	}
+
+	category, err := s.getCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != category.Name {
+		categorySlug, err := s.uniqueSlug(ctx, name, category.ID)
+		if err != nil {
+			return nil, err
+		}
+		category.Slug = categorySlug
+	}
+	category.Name = name
+	category.Description = req.Description
+
+	if err := s.categories.Update(ctx, category); err != nil {
+		s.logger.Error("failed to update category", "id", id, "error", err)
+		return nil, fmt.Errorf("update category: %w", err)
+	}
+
+	s.logger.Info("category updated", "id", id)
+	return category, nil
+}
+
+func (s *categoryService) Move(ctx context.Context, id string, req *domain.MoveCategoryRequest) (*domain.Category, error) {
+	if req == nil {
+		return nil, repository.ErrInvalidInput
+	}
+	if ActorFromContext(ctx) == "" {
+		return nil, ErrForbidden
+	}
+
+	category, err := s.getCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent *domain.Category
+	depth := 0
+	if req.ParentID != "" {
+		if parent, err = s.getParent(ctx, req.ParentID); err != nil {
+			return nil, err
+		}
+		if category.Contains(parent) {
+			return nil, fmt.Errorf("%w: a category cannot be moved below itself", repository.ErrInvalidInput)
+		}
+		depth = parent.Depth + 1
+	}
+
+	// The deepest category of the subtree must stay within the depth limit
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		s.logger.Error("failed to list categories", "error", err)
+		return nil, fmt.Errorf("move category: %w", err)
+	}
+	height := 0
+	for _, c := range categories {
+		if category.Contains(c) && c.Depth-category.Depth > height {
+			height = c.Depth - category.Depth
+		}
+	}
+	if depth+height > maxCategoryDepth {
+		return nil, fmt.Errorf("%w: categories may be nested at most %d levels deep", repository.ErrInvalidInput, maxCategoryDepth)
+	}
+
+	if err := s.categories.Move(ctx, category.ID, parent); err != nil {
+		// The category or its new parent may have been deleted meanwhile
+		if errors.Is(err, repository.ErrInvalidInput) || errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to move category", "id", id, "error", err)
+		return nil, fmt.Errorf("move category: %w", err)
+	}
+
+	s.logger.Info("category moved", "id", id, "parent", req.ParentID)
+	return s.GetByID(ctx, category.PublicID)
+}
+
+func (s *categoryService) Delete(ctx context.Context, id string) error {
+	if ActorFromContext(ctx) == "" {
+		return ErrForbidden
+	}
+
+	category, err := s.getCategory(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.categories.Delete(ctx, category.ID); err != nil {
+		if errors.Is(err, repository.ErrConflict) || errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		s.logger.Error("failed to delete category", "id", id, "error", err)
+		return fmt.Errorf("delete category: %w", err)
+	}
+
+	s.logger.Info("category deleted", "id", id)
+	return nil
+}
+
+func (s *categoryService) getCategory(ctx context.Context, id string) (*domain.Category, error) {
+	if id == "" {
+		return nil, repository.ErrInvalidInput
+	}
+
+	category, err := s.categories.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get category", "id", id, "error", err)
+		return nil, fmt.Errorf("get category: %w", err)
+	}
+
+	return category, nil
+}
+
+// getParent returns the category named as a parent, reporting a missing
+// one as invalid input rather than as the target not being found
+func (s *categoryService) getParent(ctx context.Context, id string) (*domain.Category, error) {
+	parent, err := s.getCategory(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: parent category not found", repository.ErrInvalidInput)
+	}
+	return parent, err
+}
+
+// uniqueSlug returns a slug for name that no other category uses,
+// appending a numeric suffix on collisions
+func (s *categoryService) uniqueSlug(ctx context.Context, name string, categoryID uint) (string, error) {
+	base := slug.Make(name)
+	if base == "" {
+		base = "category"
+	}
+
+	// Slugs shaped like public IDs would be resolved as IDs
+	if uid.IsValid(base) {
+		base = "category-" + base
+	}
+
+	candidate := base
+	for i := 2; i <= maxSlugAttempts; i++ {
+		taken, err := s.categories.SlugTaken(ctx, candidate, categoryID)
+		if err != nil {
+			s.logger.Error("failed to check category slug", "slug", candidate, "error", err)
+			return "", fmt.Errorf("check category slug: %w", err)
+		}
+		if !taken {
+			return candidate, nil
+		}
+
+		suffix := "-" + strconv.Itoa(i)
+		if len(base)+len(suffix) > slug.MaxLength {
+			candidate = strings.TrimRight(base[:slug.MaxLength-len(suffix)], "-") + suffix
+		} else {
+			candidate = base + suffix
+		}
+	}
+
+	return "", fmt.Errorf("%w: no free slug for %q", repository.ErrAlreadyExists, name)
+}
+
+// buildTree nests categories, ordered by depth, under their parents and
+// returns the root ones
+func buildTree(categories []*domain.Category) []*domain.Category {
+	byID := make(map[uint]*domain.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	roots := make([]*domain.Category, 0, len(categories))
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+			continue
+		}
+		if parent, ok := byID[*category.ParentID]; ok {
+			parent.Children = append(parent.Children, category)
+		}
+	}
+
+	return roots
+}
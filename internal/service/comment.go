@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+)
+
+// maxCommentLength bounds the body of a comment, in characters
+const maxCommentLength = 10000
+
+// CommentStatusAll lists comments in every moderation state
+const CommentStatusAll = "all"
+
+// CommentConfig holds comment configuration
+type CommentConfig struct {
+	// MaxDepth is the number of reply levels allowed below a top-level comment
+	MaxDepth int
+	// RequireApproval holds new comments as pending until a moderator approves them
+	RequireApproval bool
+}
+
+type commentService struct {
+	comments        repository.CommentRepository
+	posts           repository.PostRepository
+	contributors    repository.ContributorRepository
+	moderation      repository.ModerationRepository
+	moderator       ContentModerator
+	maxDepth        int
+	requireApproval bool
+	logger          Logger
+}
+
+// NewCommentService creates a new comment service
+func NewCommentService(comments repository.CommentRepository, posts repository.PostRepository, contributors repository.ContributorRepository, moderation repository.ModerationRepository, moderator ContentModerator, cfg *CommentConfig, logger Logger) CommentService {
+	return &commentService{
+		comments:        comments,
+		posts:           posts,
+		contributors:    contributors,
+		moderation:      moderation,
+		moderator:       moderator,
+		maxDepth:        cfg.MaxDepth,
+		requireApproval: cfg.RequireApproval,
+		logger:          logger,
+	}
+}
+
+func (s *commentService) List(ctx context.Context, postID string, status string) ([]*domain.Comment, int, error) {
+	var statuses []string
+	switch status {
+	case "", domain.CommentApproved:
+		statuses = []string{domain.CommentApproved}
+	case domain.CommentPending, domain.CommentRejected, CommentStatusAll:
+		// Comments awaiting or failing moderation are only shown to signed-in users
+		if ActorFromContext(ctx) == "" {
+			return nil, 0, ErrForbidden
+		}
+		if status != CommentStatusAll {
+			statuses = []string{status}
+		}
+	default:
+		return nil, 0, fmt.Errorf("%w: unknown comment status %q", repository.ErrInvalidInput, status)
+	}
+
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	comments, err := s.comments.ListByPostID(ctx, post.ID, statuses...)
+	if err != nil {
+		s.logger.Error("failed to list comments", "post_id", postID, "error", err)
+		return nil, 0, fmt.Errorf("list comments: %w", err)
+	}
+
+	threads := buildThreads(comments)
+	return threads, countThreads(threads), nil
+}
+
+func (s *commentService) Create(ctx context.Context, postID string, req *domain.CreateCommentRequest) (*domain.Comment, error) {
+	if req == nil {
+		return nil, repository.ErrInvalidInput
+	}
+
+	body, err := validateCommentBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Signed-in users always comment under their own identity
+	author := ActorFromContext(ctx)
+	if author == "" {
+		author = strings.TrimSpace(req.Author)
+	}
+	if author == "" {
+		return nil, fmt.Errorf("%w: author is required", repository.ErrInvalidInput)
+	}
+
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &domain.Comment{
+		PostID:       post.ID,
+		PostPublicID: post.PublicID,
+		Author:       author,
+		Body:         body,
+		Status:       domain.CommentApproved,
+	}
+	if s.requireApproval {
+		comment.Status = domain.CommentPending
+	}
+
+	held, err := screen(ctx, s.moderator, domain.ModerationComment, body)
+	if err != nil {
+		s.logger.Error("failed to moderate comment", "post_id", postID, "error", err)
+		return nil, err
+	}
+	if held != nil {
+		comment.Status = domain.CommentPending
+	}
+
+	if req.ParentID != "" {
+		parent, err := s.comments.GetByID(ctx, req.ParentID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, fmt.Errorf("%w: parent comment not found", repository.ErrInvalidInput)
+			}
+			s.logger.Error("failed to get parent comment", "id", req.ParentID, "error", err)
+			return nil, fmt.Errorf("create comment: %w", err)
+		}
+
+		if parent.PostID != post.ID || parent.Status != domain.CommentApproved {
+			return nil, fmt.Errorf("%w: parent comment not found", repository.ErrInvalidInput)
+		}
+		if parent.Depth >= s.maxDepth {
+			return nil, fmt.Errorf("%w: replies may be nested at most %d levels deep", repository.ErrInvalidInput, s.maxDepth)
+		}
+
+		comment.ParentID = &parent.ID
+		comment.ParentPublicID = parent.PublicID
+		comment.Depth = parent.Depth + 1
+	}
+
+	if err := s.comments.Create(ctx, comment); err != nil {
+		s.logger.Error("failed to create comment", "post_id", postID, "error", err)
+		return nil, fmt.Errorf("create comment: %w", err)
+	}
+
+	if held != nil {
+		// Approving the item approves the comment
+		held.TargetID = comment.ID
+		held.TargetPublicID = comment.PublicID
+		held.ReleaseStatus = domain.CommentApproved
+		if err := s.moderation.Hold(ctx, held); err != nil {
+			s.logger.Error("failed to hold comment", "id", comment.PublicID, "error", err)
+			return nil, fmt.Errorf("hold comment: %w", err)
+		}
+		s.logger.Info("comment held for moderation", "id", comment.PublicID, "reasons", held.Reasons)
+	}
+
+	s.logger.Info("comment created", "id", comment.PublicID, "post_id", postID, "status", comment.Status)
+	return comment, nil
+}
+
+func (s *commentService) Update(ctx context.Context, id string, req *domain.UpdateCommentRequest) (*domain.Comment, error) {
+	if id == "" || req == nil {
+		return nil, repository.ErrInvalidInput
+	}
+	if req.Body == "" && req.Status == "" {
+		return nil, fmt.Errorf("%w: nothing to update", repository.ErrInvalidInput)
+	}
+
+	actor := ActorFromContext(ctx)
+	if actor == "" {
+		return nil, ErrForbidden
+	}
+
+	comment, err := s.getComment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Status != "" {
+		switch req.Status {
+		case domain.CommentPending, domain.CommentApproved, domain.CommentRejected:
+		default:
+			return nil, fmt.Errorf("%w: unknown comment status %q", repository.ErrInvalidInput, req.Status)
+		}
+		if err := s.authorize(ctx, comment); err != nil {
+			return nil, err
+		}
+
+		// Comments the moderator flagged are decided in the moderation queue
+		if _, err := s.moderation.GetPending(ctx, domain.ModerationComment, comment.ID); err == nil {
+			return nil, fmt.Errorf("%w: comment is held for moderation", repository.ErrConflict)
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			s.logger.Error("failed to get moderation item", "id", id, "error", err)
+			return nil, fmt.Errorf("update comment: %w", err)
+		}
+		comment.Status = req.Status
+	}
+
+	var held *domain.ModerationItem
+	if req.Body != "" {
+		if !strings.EqualFold(comment.Author, actor) {
+			return nil, ErrForbidden
+		}
+		body, err := validateCommentBody(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		comment.Body = body
+
+		// Edits are moderated like new comments, and rejected ones stay rejected
+		held, err = screen(ctx, s.moderator, domain.ModerationComment, body)
+		if err != nil {
+			s.logger.Error("failed to moderate comment", "id", id, "error", err)
+			return nil, err
+		}
+		if comment.Status == domain.CommentRejected {
+			held = nil
+		} else if held != nil || s.requireApproval {
+			comment.Status = domain.CommentPending
+		}
+	}
+
+	if err := s.comments.Update(ctx, comment); err != nil {
+		s.logger.Error("failed to update comment", "id", id, "error", err)
+		return nil, fmt.Errorf("update comment: %w", err)
+	}
+
+	if held != nil {
+		held.TargetID = comment.ID
+		held.TargetPublicID = comment.PublicID
+		held.ReleaseStatus = domain.CommentApproved
+		if err := s.moderation.Hold(ctx, held); err != nil {
+			s.logger.Error("failed to hold comment", "id", id, "error", err)
+			return nil, fmt.Errorf("hold comment: %w", err)
+		}
+		s.logger.Info("comment held for moderation", "id", id, "reasons", held.Reasons)
+	}
+
+	s.logger.Info("comment updated", "id", id, "status", comment.Status, "by", actor)
+	return comment, nil
+}
+
+func (s *commentService) Delete(ctx context.Context, id string) (int64, error) {
+	if id == "" {
+		return 0, repository.ErrInvalidInput
+	}
+
+	actor := ActorFromContext(ctx)
+	if actor == "" {
+		return 0, ErrForbidden
+	}
+
+	comment, err := s.getComment(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.authorize(ctx, comment); err != nil {
+		return 0, err
+	}
+
+	deleted, err := s.comments.Delete(ctx, comment.ID)
+	if err != nil {
+		s.logger.Error("failed to delete comment", "id", id, "error", err)
+		return 0, fmt.Errorf("delete comment: %w", err)
+	}
+
+	s.logger.Info("comment deleted", "id", id, "count", deleted, "by", actor)
+	return deleted, nil
+}
+
+// authorize checks that the actor may moderate the comments of the post
+// the comment is on, which owners and editors of the post and admins do
+func (s *commentService) authorize(ctx context.Context, comment *domain.Comment) error {
+	post, err := s.getPost(ctx, comment.PostPublicID)
+	if err != nil {
+		return err
+	}
+
+	contributors, err := s.contributors.List(ctx, post.ID)
+	if err != nil {
+		s.logger.Error("failed to list contributors", "id", post.PublicID, "error", err)
+		return fmt.Errorf("list contributors: %w", err)
+	}
+
+	if !hasRole(ctx, contributors, domain.RoleOwner, domain.RoleEditor) {
+		s.logger.Info("comment change forbidden", "id", comment.PublicID, "actor", ActorFromContext(ctx), "role", actorRole(ctx, contributors))
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s *commentService) getPost(ctx context.Context, id string) (*domain.Post, error) {
+	if id == "" {
+		return nil, repository.ErrInvalidInput
+	}
+
+	post, err := s.posts.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get post", "id", id, "error", err)
+		return nil, fmt.Errorf("get post: %w", err)
+	}
+
+	if err := readable(ctx, s.contributors, s.logger, post); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+func (s *commentService) getComment(ctx context.Context, id string) (*domain.Comment, error) {
+	comment, err := s.comments.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get comment", "id", id, "error", err)
+		return nil, fmt.Errorf("get comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// validateCommentBody trims body and checks its length
+func validateCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: body is required", repository.ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", fmt.Errorf("%w: body is longer than %d characters", repository.ErrInvalidInput, maxCommentLength)
+	}
+	return body, nil
+}
+
+// buildThreads nests comments under their parents and returns the top-level
+// ones. Replies whose parent is not among comments are left out with it.
+func buildThreads(comments []*domain.Comment) []*domain.Comment {
+	byID := make(map[uint]*domain.Comment, len(comments))
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+	}
+
+	roots := make([]*domain.Comment, 0, len(comments))
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+			continue
+		}
+		if parent, ok := byID[*comment.ParentID]; ok {
+			parent.Replies = append(parent.Replies, comment)
+		}
+	}
+
+	return roots
+}
+
+// countThreads returns the number of comments in threads, replies included
+func countThreads(threads []*domain.Comment) int {
+	count := len(threads)
+	for _, comment := range threads {
+		count += countThreads(comment.Replies)
+	}
+	return count
+}
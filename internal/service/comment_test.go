@@ -0,0 +1,308 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/service"
+)
+
+type mockCommentRepository struct {
+	comments []*domain.Comment
+}
+
+func (m *mockCommentRepository) GetByID(ctx context.Context, id string) (*domain.Comment, error) {
+	for _, comment := range m.comments {
+		if comment.PublicID == id {
+			return comment, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockCommentRepository) ListByPostID(ctx context.Context, postID uint, statuses ...string) ([]*domain.Comment, error) {
+	var comments []*domain.Comment
+	for _, comment := range m.comments {
+		if comment.PostID != postID {
+			continue
+		}
+		if len(statuses) == 0 {
+			comments = append(comments, comment)
+			continue
+		}
+		for _, status := range statuses {
+			if comment.Status == status {
+				comments = append(comments, comment)
+				break
+			}
+		}
+	}
+	return comments, nil
+}
+
+func (m *mockCommentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	comment.ID = uint(len(m.comments) + 1)
+	comment.PublicID = fmt.Sprintf("comment-%d", comment.ID)
+	m.comments = append(m.comments, comment)
+	return nil
+}
+
+func (m *mockCommentRepository) Update(ctx context.Context, comment *domain.Comment) error {
+	return nil
+}
+
+func (m *mockCommentRepository) Delete(ctx context.Context, id uint) (int64, error) {
+	return 0, nil
+}
+
+// commentPosts returns a post repository holding the posts "first" and "second"
+func commentPosts() *mockPostRepository {
+	return &mockPostRepository{
+		getByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			switch id {
+			case "first":
+				return &domain.Post{ID: 1, PublicID: "first", Status: domain.PostPublished}, nil
+			case "second":
+				return &domain.Post{ID: 2, PublicID: "second", Status: domain.PostPublished}, nil
+			}
+			return nil, repository.ErrNotFound
+		},
+	}
+}
+
+func TestCommentService_CreateDepth(t *testing.T) {
+	parent := func(id uint, postID uint, depth int, status string) *domain.Comment {
+		return &domain.Comment{ID: id, PublicID: fmt.Sprintf("comment-%d", id), PostID: postID, Depth: depth, Status: status}
+	}
+
+	tests := []struct {
+		name      string
+		parentID  string
+		wantDepth int
+		wantErr   error
+	}{
+		{name: "top level", wantDepth: 0},
+		{name: "reply", parentID: "comment-1", wantDepth: 1},
+		{name: "reply at max depth", parentID: "comment-2", wantDepth: 2},
+		{name: "too deep", parentID: "comment-3", wantErr: repository.ErrInvalidInput},
+		{name: "parent on another post", parentID: "comment-4", wantErr: repository.ErrInvalidInput},
+		{name: "pending parent", parentID: "comment-5", wantErr: repository.ErrInvalidInput},
+		{name: "missing parent", parentID: "comment-9", wantErr: repository.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comments := &mockCommentRepository{comments: []*domain.Comment{
+				parent(1, 1, 0, domain.CommentApproved),
+				parent(2, 1, 1, domain.CommentApproved),
+				parent(3, 1, 2, domain.CommentApproved),
+				parent(4, 2, 0, domain.CommentApproved),
+				parent(5, 1, 0, domain.CommentPending),
+			}}
+			svc := service.NewCommentService(comments, commentPosts(), &mockContributorRepository{}, &mockModerationRepository{}, nil, &service.CommentConfig{MaxDepth: 2}, &mockLogger{})
+
+			comment, err := svc.Create(context.Background(), "first", &domain.CreateCommentRequest{Author: "Ann", Body: "Nice", ParentID: tt.parentID})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if comment.Depth != tt.wantDepth {
+				t.Errorf("expected depth %d, got %d", tt.wantDepth, comment.Depth)
+			}
+			if comment.ParentPublicID != tt.parentID {
+				t.Errorf("expected parent %q, got %q", tt.parentID, comment.ParentPublicID)
+			}
+		})
+	}
+}
+
+func TestCommentService_CreateStatus(t *testing.T) {
+	moderator := service.NewFilterModerator(&service.ModerationConfig{Words: []string{"casino"}})
+
+	tests := []struct {
+		name            string
+		requireApproval bool
+		body            string
+		wantStatus      string
+		wantHeld        bool
+	}{
+		{name: "approved right away", body: "Nice post", wantStatus: domain.CommentApproved},
+		{name: "approval required", requireApproval: true, body: "Nice post", wantStatus: domain.CommentPending},
+		{name: "flagged", body: "Visit my casino", wantStatus: domain.CommentPending, wantHeld: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := &mockModerationRepository{}
+			cfg := &service.CommentConfig{MaxDepth: 5, RequireApproval: tt.requireApproval}
+			svc := service.NewCommentService(&mockCommentRepository{}, commentPosts(), &mockContributorRepository{}, queue, moderator, cfg, &mockLogger{})
+
+			comment, err := svc.Create(context.Background(), "first", &domain.CreateCommentRequest{Author: "Ann", Body: tt.body})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if comment.Status != tt.wantStatus {
+				t.Errorf("expected status %q, got %q", tt.wantStatus, comment.Status)
+			}
+
+			if !tt.wantHeld {
+				if len(queue.items) != 0 {
+					t.Errorf("expected nothing queued, got %d items", len(queue.items))
+				}
+				return
+			}
+			if len(queue.items) != 1 {
+				t.Fatalf("expected one queued item, got %d", len(queue.items))
+			}
+			item := queue.items[0]
+			if item.Kind != domain.ModerationComment || item.TargetPublicID != comment.PublicID || item.ReleaseStatus != domain.CommentApproved {
+				t.Errorf("unexpected queued item: %+v", item)
+			}
+		})
+	}
+}
+
+func TestCommentService_List(t *testing.T) {
+	reply := uint(1)
+	comments := &mockCommentRepository{comments: []*domain.Comment{
+		{ID: 1, PublicID: "comment-1", PostID: 1, Status: domain.CommentApproved},
+		{ID: 2, PublicID: "comment-2", PostID: 1, ParentID: &reply, Depth: 1, Status: domain.CommentApproved},
+		{ID: 3, PublicID: "comment-3", PostID: 1, Status: domain.CommentPending},
+		{ID: 4, PublicID: "comment-4", PostID: 1, Status: domain.CommentRejected},
+		{ID: 5, PublicID: "comment-5", PostID: 2, Status: domain.CommentApproved},
+	}}
+	svc := service.NewCommentService(comments, commentPosts(), &mockContributorRepository{}, &mockModerationRepository{}, nil, &service.CommentConfig{MaxDepth: 5}, &mockLogger{})
+	signedIn := service.WithActor(context.Background(), "moderator@example.com")
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		status    string
+		wantRoots int
+		wantTotal int
+		wantErr   error
+	}{
+		{name: "approved by default", ctx: context.Background(), wantRoots: 1, wantTotal: 2},
+		{name: "pending hidden from anonymous", ctx: context.Background(), status: domain.CommentPending, wantErr: service.ErrForbidden},
+		{name: "pending", ctx: signedIn, status: domain.CommentPending, wantRoots: 1, wantTotal: 1},
+		{name: "rejected", ctx: signedIn, status: domain.CommentRejected, wantRoots: 1, wantTotal: 1},
+		{name: "all", ctx: signedIn, status: service.CommentStatusAll, wantRoots: 3, wantTotal: 4},
+		{name: "unknown status", ctx: signedIn, status: "spam", wantErr: repository.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Threads are rebuilt from the stored comments on every call
+			for _, comment := range comments.comments {
+				comment.Replies = nil
+			}
+
+			threads, total, err := svc.List(tt.ctx, "first", tt.status)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(threads) != tt.wantRoots || total != tt.wantTotal {
+				t.Errorf("expected %d threads with %d comments, got %d with %d", tt.wantRoots, tt.wantTotal, len(threads), total)
+			}
+		})
+	}
+}
+
+func TestCommentService_Update(t *testing.T) {
+	moderator := service.NewFilterModerator(&service.ModerationConfig{Words: []string{"casino"}})
+	author := service.WithActor(context.Background(), "ann@example.com")
+	owner := service.WithActor(context.Background(), "owner@example.com")
+	admin := service.WithAdmin(service.WithActor(context.Background(), "admin@example.com"))
+
+	tests := []struct {
+		name            string
+		ctx             context.Context
+		requireApproval bool
+		held            bool
+		req             domain.UpdateCommentRequest
+		wantStatus      string
+		wantQueued      bool
+		wantErr         error
+	}{
+		{name: "author edits the body", ctx: author, req: domain.UpdateCommentRequest{Body: "Nice post"}, wantStatus: domain.CommentApproved},
+		{name: "edit needs approval again", ctx: author, requireApproval: true, req: domain.UpdateCommentRequest{Body: "Nice post"}, wantStatus: domain.CommentPending},
+		{name: "flagged edit is held", ctx: author, req: domain.UpdateCommentRequest{Body: "Visit my casino"}, wantStatus: domain.CommentPending, wantQueued: true},
+		{name: "others may not edit the body", ctx: owner, req: domain.UpdateCommentRequest{Body: "Nice post"}, wantErr: service.ErrForbidden},
+		{name: "author may not change the status", ctx: author, req: domain.UpdateCommentRequest{Status: domain.CommentRejected}, wantErr: service.ErrForbidden},
+		{name: "owner of the post rejects it", ctx: owner, req: domain.UpdateCommentRequest{Status: domain.CommentRejected}, wantStatus: domain.CommentRejected},
+		{name: "admin rejects it", ctx: admin, req: domain.UpdateCommentRequest{Status: domain.CommentRejected}, wantStatus: domain.CommentRejected},
+		{name: "held comments wait for a moderator", ctx: owner, held: true, req: domain.UpdateCommentRequest{Status: domain.CommentApproved}, wantErr: repository.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment := &domain.Comment{ID: 1, PublicID: "comment-1", PostID: 1, PostPublicID: "first", Author: "ann@example.com", Body: "Hello", Status: domain.CommentApproved}
+			queue := &mockModerationRepository{}
+			if tt.held {
+				comment.Status = domain.CommentPending
+				queue.items = []*domain.ModerationItem{{Kind: domain.ModerationComment, TargetID: 1, Status: domain.ModerationPending}}
+			}
+			cfg := &service.CommentConfig{MaxDepth: 5, RequireApproval: tt.requireApproval}
+			comments := &mockCommentRepository{comments: []*domain.Comment{comment}}
+			svc := service.NewCommentService(comments, commentPosts(), ownedBy("owner@example.com", 1), queue, moderator, cfg, &mockLogger{})
+
+			updated, err := svc.Update(tt.ctx, "comment-1", &tt.req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if updated.Status != tt.wantStatus {
+				t.Errorf("expected status %q, got %q", tt.wantStatus, updated.Status)
+			}
+			if queued := len(queue.items) == 1; queued != tt.wantQueued {
+				t.Errorf("expected queued=%v, got %d items", tt.wantQueued, len(queue.items))
+			}
+		})
+	}
+}
+
+func TestCommentService_Delete(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{name: "owner of the post", ctx: service.WithActor(context.Background(), "owner@example.com")},
+		{name: "admin", ctx: service.WithAdmin(service.WithActor(context.Background(), "admin@example.com"))},
+		{name: "author", ctx: service.WithActor(context.Background(), "ann@example.com"), wantErr: service.ErrForbidden},
+		{name: "anonymous", ctx: context.Background(), wantErr: service.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comments := &mockCommentRepository{comments: []*domain.Comment{
+				{ID: 1, PublicID: "comment-1", PostID: 1, PostPublicID: "first", Author: "ann@example.com", Status: domain.CommentApproved},
+			}}
+			svc := service.NewCommentService(comments, commentPosts(), ownedBy("owner@example.com", 1), &mockModerationRepository{}, nil, &service.CommentConfig{MaxDepth: 5}, &mockLogger{})
+
+			if _, err := svc.Delete(tt.ctx, "comment-1"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
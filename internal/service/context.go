@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrForbidden is returned when the actor of a request may not perform it
+var ErrForbidden = errors.New("forbidden")
+
+type actorKey struct{}
+
+type adminKey struct{}
+
+// WithActor returns a copy of ctx carrying the email of the user performing the request
+func WithActor(ctx context.Context, email string) context.Context {
+	return context.WithValue(ctx, actorKey{}, email)
+}
+
+// WithAdmin returns a copy of ctx marking its actor as an admin, who
+// passes the contributor checks of every post
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+// IsAdmin reports whether the actor of ctx is an admin
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin && ActorFromContext(ctx) != ""
+}
+
+// ActorFromContext returns the email stored by WithActor, or an empty string for anonymous requests
+func ActorFromContext(ctx context.Context) string {
+	email, _ := ctx.Value(actorKey{}).(string)
+	return email
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+)
+
+// ErrContributorNotFound is returned when the user does not contribute to the post
+var ErrContributorNotFound = fmt.Errorf("contributor %w", repository.ErrNotFound)
+
+func (s *postService) ListContributors(ctx context.Context, id string) ([]*domain.PostContributor, error) {
+	if ActorFromContext(ctx) == "" {
+		return nil, ErrForbidden
+	}
+
+	post, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.listContributors(ctx, post)
+}
+
+func (s *postService) InviteContributor(ctx context.Context, id string, req *domain.InviteContributorRequest) (*domain.PostContributor, error) {
+	if req == nil {
+		return nil, repository.ErrInvalidInput
+	}
+	switch req.Role {
+	case domain.RoleOwner, domain.RoleEditor, domain.RoleReviewer:
+	default:
+		return nil, fmt.Errorf("%w: unknown role %q", repository.ErrInvalidInput, req.Role)
+	}
+	email := strings.TrimSpace(req.Email)
+	if email == "" {
+		return nil, fmt.Errorf("%w: email is required", repository.ErrInvalidInput)
+	}
+
+	post, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Posts without an owner only get one from an admin
+	if err := s.authorize(ctx, post, domain.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	actor := ActorFromContext(ctx)
+	contributor := &domain.PostContributor{PostID: post.ID, Role: req.Role, InvitedBy: actor}
+	if err := s.contributors.Add(ctx, contributor, email); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: no user with email %s", repository.ErrInvalidInput, email)
+		}
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, err
+		}
+		s.logger.Error("failed to invite contributor", "id", id, "error", err)
+		return nil, fmt.Errorf("invite contributor: %w", err)
+	}
+
+	s.logger.Info("contributor invited", "id", id, "user", contributor.UserPublicID, "role", contributor.Role, "by", actor)
+	return contributor, nil
+}
+
+func (s *postService) RemoveContributor(ctx context.Context, id, userID string) error {
+	if userID == "" {
+		return repository.ErrInvalidInput
+	}
+
+	post, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	contributors, err := s.listContributors(ctx, post)
+	if err != nil {
+		return err
+	}
+
+	// Owners and admins remove anyone; other contributors may only leave
+	actor := ActorFromContext(ctx)
+	allowed := IsAdmin(ctx)
+	for _, c := range contributors {
+		if actor != "" && strings.EqualFold(c.Email, actor) {
+			allowed = allowed || c.Role == domain.RoleOwner || strings.EqualFold(c.UserPublicID, userID)
+			break
+		}
+	}
+	if !allowed {
+		return ErrForbidden
+	}
+
+	if err := s.contributors.Remove(ctx, post.ID, strings.ToLower(userID)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrContributorNotFound
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			return err
+		}
+		s.logger.Error("failed to remove contributor", "id", id, "user", userID, "error", err)
+		return fmt.Errorf("remove contributor: %w", err)
+	}
+
+	s.logger.Info("contributor removed", "id", id, "user", userID, "by", actor)
+	return nil
+}
+
+// authorize checks that the actor has one of roles on the post. Posts
+// nobody contributes to are closed to everyone but admins.
+func (s *postService) authorize(ctx context.Context, post *domain.Post, roles ...string) error {
+	contributors, err := s.listContributors(ctx, post)
+	if err != nil {
+		return err
+	}
+
+	if !hasRole(ctx, contributors, roles...) {
+		s.logger.Info("post change forbidden", "id", post.PublicID, "actor", ActorFromContext(ctx), "role", actorRole(ctx, contributors))
+		return ErrForbidden
+	}
+	return nil
+}
+
+// roleOf returns the role of the actor on the post, or an empty string
+// when the actor does not contribute to it
+func (s *postService) roleOf(ctx context.Context, post *domain.Post) (string, error) {
+	contributors, err := s.listContributors(ctx, post)
+	if err != nil {
+		return "", err
+	}
+	return actorRole(ctx, contributors), nil
+}
+
+// readable hides posts that are not published, held ones included, from
+// everyone but their contributors and admins, answering as if they did not
+// exist. Every service that looks up a post for its reader goes through it.
+func readable(ctx context.Context, contributors repository.ContributorRepository, logger Logger, post *domain.Post) error {
+	if post.Status == domain.PostPublished || IsAdmin(ctx) {
+		return nil
+	}
+
+	if ActorFromContext(ctx) != "" {
+		list, err := contributors.List(ctx, post.ID)
+		if err != nil {
+			logger.Error("failed to list contributors", "id", post.PublicID, "error", err)
+			return fmt.Errorf("list contributors: %w", err)
+		}
+		if actorRole(ctx, list) != "" {
+			return nil
+		}
+	}
+
+	logger.Info("unpublished post hidden", "id", post.PublicID, "status", post.Status)
+	return repository.ErrNotFound
+}
+
+func (s *postService) listContributors(ctx context.Context, post *domain.Post) ([]*domain.PostContributor, error) {
+	contributors, err := s.contributors.List(ctx, post.ID)
+	if err != nil {
+		s.logger.Error("failed to list contributors", "id", post.PublicID, "error", err)
+		return nil, fmt.Errorf("list contributors: %w", err)
+	}
+	return contributors, nil
+}
+
+// actorRole finds the actor among contributors and returns their role
+func actorRole(ctx context.Context, contributors []*domain.PostContributor) string {
+	actor := ActorFromContext(ctx)
+	if actor == "" {
+		return ""
+	}
+	for _, c := range contributors {
+		if strings.EqualFold(c.Email, actor) {
+			return c.Role
+		}
+	}
+	return ""
+}
+
+// hasRole reports whether the actor has one of roles among contributors.
+// Admins have them all.
+func hasRole(ctx context.Context, contributors []*domain.PostContributor, roles ...string) bool {
+	if IsAdmin(ctx) {
+		return true
+	}
+	role := actorRole(ctx, contributors)
+	if role == "" {
+		return false
+	}
+	for _, allowed := range roles {
+		if role == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+)
+
+var (
+	// ErrReactionNotFound is returned when the actor has not reacted to the post
+	ErrReactionNotFound = fmt.Errorf("reaction %w", repository.ErrNotFound)
+	// ErrBookmarkNotFound is returned when the actor has not bookmarked the post
+	ErrBookmarkNotFound = fmt.Errorf("bookmark %w", repository.ErrNotFound)
+)
+
+// EngagementConfig holds reaction and bookmark configuration
+type EngagementConfig struct {
+	// Reactions is the set of emoji users may react to posts with
+	Reactions []string
+}
+
+type engagementService struct {
+	engagement   repository.EngagementRepository
+	posts        repository.PostRepository
+	contributors repository.ContributorRepository
+	users        repository.UserRepository
+	reactions    map[string]string
+	allowed      []string
+	logger       Logger
+}
+
+// NewEngagementService creates a new reaction and bookmark service
+func NewEngagementService(engagement repository.EngagementRepository, posts repository.PostRepository, contributors repository.ContributorRepository, users repository.UserRepository, cfg *EngagementConfig, logger Logger) EngagementService {
+	s := &engagementService{
+		engagement:   engagement,
+		posts:        posts,
+		contributors: contributors,
+		users:        users,
+		reactions:    make(map[string]string, len(cfg.Reactions)),
+		logger:       logger,
+	}
+	for _, emoji := range cfg.Reactions {
+		if emoji = strings.TrimSpace(emoji); emoji != "" {
+			s.reactions[emojiKey(emoji)] = emoji
+			s.allowed = append(s.allowed, emoji)
+		}
+	}
+	return s
+}
+
+func (s *engagementService) Reactions() []string {
+	return s.allowed
+}
+
+func (s *engagementService) React(ctx context.Context, postID string, req *domain.ReactionRequest) (*domain.Reaction, bool, error) {
+	if req == nil {
+		return nil, false, repository.ErrInvalidInput
+	}
+
+	emoji, ok := s.reactions[emojiKey(strings.TrimSpace(req.Emoji))]
+	if !ok {
+		return nil, false, fmt.Errorf("%w: emoji must be one of %s", repository.ErrInvalidInput, strings.Join(s.allowed, " "))
+	}
+
+	user, err := s.actor(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	reaction := &domain.Reaction{UserID: user.ID, PostID: post.ID, Emoji: emoji, PostPublicID: post.PublicID}
+	created, err := s.engagement.SetReaction(ctx, reaction)
+	if err != nil {
+		s.logger.Error("failed to save reaction", "post_id", postID, "error", err)
+		return nil, false, fmt.Errorf("react to post: %w", err)
+	}
+
+	s.logger.Info("reaction saved", "post_id", postID, "user", user.Email, "emoji", emoji)
+	return reaction, created, nil
+}
+
+func (s *engagementService) Unreact(ctx context.Context, postID string) error {
+	user, err := s.actor(ctx)
+	if err != nil {
+		return err
+	}
+
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.engagement.DeleteReaction(ctx, user.ID, post.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrReactionNotFound
+		}
+		s.logger.Error("failed to delete reaction", "post_id", postID, "error", err)
+		return fmt.Errorf("delete reaction: %w", err)
+	}
+
+	s.logger.Info("reaction deleted", "post_id", postID, "user", user.Email)
+	return nil
+}
+
+func (s *engagementService) Bookmark(ctx context.Context, postID string) (*domain.Bookmark, bool, error) {
+	user, err := s.actor(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Bookmarking a post twice leaves the first bookmark in place
+	bookmark := &domain.Bookmark{UserID: user.ID, PostID: post.ID}
+	err = s.engagement.AddBookmark(ctx, bookmark)
+	created := err == nil
+	if err != nil && !errors.Is(err, repository.ErrAlreadyExists) {
+		s.logger.Error("failed to save bookmark", "post_id", postID, "error", err)
+		return nil, false, fmt.Errorf("bookmark post: %w", err)
+	}
+	bookmark.PostPublicID = post.PublicID
+
+	if created {
+		s.logger.Info("bookmark saved", "post_id", postID, "user", user.Email)
+	}
+	return bookmark, created, nil
+}
+
+func (s *engagementService) Unbookmark(ctx context.Context, postID string) error {
+	user, err := s.actor(ctx)
+	if err != nil {
+		return err
+	}
+
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.engagement.DeleteBookmark(ctx, user.ID, post.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrBookmarkNotFound
+		}
+		s.logger.Error("failed to delete bookmark", "post_id", postID, "error", err)
+		return fmt.Errorf("delete bookmark: %w", err)
+	}
+
+	s.logger.Info("bookmark deleted", "post_id", postID, "user", user.Email)
+	return nil
+}
+
+func (s *engagementService) ListBookmarks(ctx context.Context, limit, offset int) ([]*domain.Post, int64, error) {
+	if limit <= 0 {
+		limit = 25
+	}
+	if limit > 100 {
+		limit = 100 // Max limit
+	}
+
+	user, err := s.actor(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	posts, total, err := s.engagement.ListBookmarked(ctx, user.ID, limit, offset)
+	if err != nil {
+		s.logger.Error("failed to list bookmarks", "user", user.Email, "error", err)
+		return nil, 0, fmt.Errorf("list bookmarks: %w", err)
+	}
+
+	return posts, total, nil
+}
+
+// actor returns the signed-in user performing the request
+func (s *engagementService) actor(ctx context.Context) (*domain.User, error) {
+	email := ActorFromContext(ctx)
+	if email == "" {
+		return nil, ErrForbidden
+	}
+
+	user, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		// The account was removed after the token was issued
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrForbidden
+		}
+		s.logger.Error("failed to get user", "email", email, "error", err)
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (s *engagementService) getPost(ctx context.Context, id string) (*domain.Post, error) {
+	if id == "" {
+		return nil, repository.ErrInvalidInput
+	}
+
+	post, err := s.posts.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get post", "id", id, "error", err)
+		return nil, fmt.Errorf("get post: %w", err)
+	}
+
+	if err := readable(ctx, s.contributors, s.logger, post); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// emojiKey drops the variation selectors some keyboards add to emoji, so
+// "❤" and "❤️" count as the same reaction
+func emojiKey(emoji string) string {
+	return strings.NewReplacer("\uFE0E", "", "\uFE0F", "").Replace(emoji)
+}
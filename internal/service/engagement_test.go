@@ -0,0 +1,233 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/repository/gormrepo"
+	"github.com/yakuter/ugin/internal/service"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// engagementDB opens an in-memory database with posts, users and their
+// reactions and bookmarks, since uniqueness and popularity are kept by
+// the database
+func engagementDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&domain.Post{}, &domain.Tag{}, &domain.User{}, &domain.PostSlug{}, &domain.Comment{},
+		&domain.Attachment{}, &domain.Category{}, &domain.Series{}, &domain.SeriesPost{}, &domain.PostContributor{},
+		&domain.PostTranslation{}, &domain.Reaction{}, &domain.Bookmark{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// seedEngagement stores published posts and users with the given names and emails
+func seedEngagement(t *testing.T, db *gorm.DB, posts []string, users []string) {
+	t.Helper()
+
+	for _, name := range posts {
+		if err := db.Create(&domain.Post{Name: name, Slug: name, Format: domain.FormatPlain, Status: domain.PostPublished, Version: 1}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, email := range users {
+		if err := db.Create(&domain.User{Email: email, MasterPassword: "secret"}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newEngagementService(db *gorm.DB) service.EngagementService {
+	cfg := &service.EngagementConfig{Reactions: []string{"👍", "❤️", "🎉"}}
+	return service.NewEngagementService(gormrepo.NewEngagementRepository(db), gormrepo.NewPostRepository(db), gormrepo.NewContributorRepository(db), gormrepo.NewUserRepository(db), cfg, &mockLogger{})
+}
+
+func TestEngagementService_React(t *testing.T) {
+	db := engagementDB(t)
+	seedEngagement(t, db, []string{"first"}, []string{"ann@example.com"})
+	svc := newEngagementService(db)
+	ctx := service.WithActor(context.Background(), "ann@example.com")
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		emoji       string
+		wantCreated bool
+		wantEmoji   string
+		wantErr     error
+	}{
+		{name: "first reaction", ctx: ctx, emoji: "👍", wantCreated: true, wantEmoji: "👍"},
+		{name: "replaces the emoji", ctx: ctx, emoji: "🎉", wantEmoji: "🎉"},
+		{name: "without variation selector", ctx: ctx, emoji: "❤", wantEmoji: "❤️"},
+		{name: "emoji not allowed", ctx: ctx, emoji: "💩", wantErr: repository.ErrInvalidInput},
+		{name: "anonymous", ctx: context.Background(), emoji: "👍", wantErr: service.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaction, created, err := svc.React(tt.ctx, "first", &domain.ReactionRequest{Emoji: tt.emoji})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if created != tt.wantCreated || reaction.Emoji != tt.wantEmoji {
+				t.Errorf("expected created=%v emoji %q, got created=%v emoji %q", tt.wantCreated, tt.wantEmoji, created, reaction.Emoji)
+			}
+		})
+	}
+
+	// A user keeps a single reaction per post
+	var count int64
+	db.Model(&domain.Reaction{}).Count(&count)
+	if count != 1 {
+		t.Errorf("expected one stored reaction, got %d", count)
+	}
+
+	if err := svc.Unreact(ctx, "first"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.Unreact(ctx, "first"); !errors.Is(err, service.ErrReactionNotFound) {
+		t.Errorf("expected %v, got %v", service.ErrReactionNotFound, err)
+	}
+}
+
+func TestEngagementService_Bookmark(t *testing.T) {
+	db := engagementDB(t)
+	seedEngagement(t, db, []string{"first", "second"}, []string{"ann@example.com"})
+	svc := newEngagementService(db)
+	ctx := service.WithActor(context.Background(), "ann@example.com")
+
+	first, created, err := svc.Bookmark(ctx, "first")
+	if err != nil || !created {
+		t.Fatalf("expected a new bookmark, got created=%v err=%v", created, err)
+	}
+
+	// Bookmarking again keeps the first bookmark
+	again, created, err := svc.Bookmark(ctx, "first")
+	if err != nil || created {
+		t.Fatalf("expected the existing bookmark, got created=%v err=%v", created, err)
+	}
+	if !again.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("expected the bookmark from %v, got %v", first.CreatedAt, again.CreatedAt)
+	}
+
+	if _, _, err := svc.Bookmark(ctx, "second"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	posts, total, err := svc.ListBookmarks(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 2 || len(posts) != 2 || posts[0].Slug != "second" {
+		t.Errorf("expected second then first, got %d of %d", len(posts), total)
+	}
+
+	// Unpublished posts are neither listed nor bookmarked for readers who
+	// do not contribute to them
+	if err := db.Model(&domain.Post{}).Where("slug = ?", "second").Update("status", domain.PostDraft).Error; err != nil {
+		t.Fatal(err)
+	}
+	posts, total, err = svc.ListBookmarks(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 1 || len(posts) != 1 || posts[0].Slug != "first" {
+		t.Errorf("expected only first, got %d of %d", len(posts), total)
+	}
+	if _, _, err := svc.Bookmark(ctx, "second"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected %v for a draft, got %v", repository.ErrNotFound, err)
+	}
+
+	if err := svc.Unbookmark(ctx, "first"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.Unbookmark(ctx, "first"); !errors.Is(err, service.ErrBookmarkNotFound) {
+		t.Errorf("expected %v, got %v", service.ErrBookmarkNotFound, err)
+	}
+	if _, _, err := svc.Bookmark(context.Background(), "first"); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("expected %v for an anonymous bookmark, got %v", service.ErrForbidden, err)
+	}
+}
+
+func TestPostService_ListByPopularity(t *testing.T) {
+	db := engagementDB(t)
+	seedEngagement(t, db, []string{"quiet", "liked", "saved", "popular"}, []string{"ann@example.com", "bob@example.com"})
+	engagement := newEngagementService(db)
+
+	ann := service.WithActor(context.Background(), "ann@example.com")
+	bob := service.WithActor(context.Background(), "bob@example.com")
+	// An empty emoji bookmarks the post
+	steps := []struct {
+		ctx   context.Context
+		post  string
+		emoji string
+	}{
+		{ann, "liked", "👍"},
+		{ann, "saved", ""},
+		{bob, "saved", ""},
+		{ann, "popular", "🎉"},
+		{bob, "popular", "👍"},
+		{bob, "popular", ""},
+	}
+	for _, step := range steps {
+		var err error
+		if step.emoji == "" {
+			_, _, err = engagement.Bookmark(step.ctx, step.post)
+		} else {
+			_, _, err = engagement.React(step.ctx, step.post, &domain.ReactionRequest{Emoji: step.emoji})
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := service.NewPostService(gormrepo.NewPostRepository(db), &mockPostRevisionRepository{}, &mockCategoryRepository{}, &mockContributorRepository{}, &mockReviewRepository{}, nil, nil, &mockLogger{})
+
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{order: "DESC", want: []string{"popular", "saved", "liked", "quiet"}},
+		{order: "ASC", want: []string{"quiet", "liked", "saved", "popular"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			posts, _, err := svc.List(context.Background(), repository.ListFilter{Sort: repository.SortPopularity, Order: tt.order})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, post := range posts {
+				got = append(got, post.Slug)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
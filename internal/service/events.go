@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/yakuter/ugin/internal/domain"
+)
+
+// PostEventType names a change made to a post
+type PostEventType string
+
+// Changes reported to post listeners
+const (
+	PostCreated  PostEventType = "post.created"
+	PostUpdated  PostEventType = "post.updated"
+	PostDeleted  PostEventType = "post.deleted"
+	PostRestored PostEventType = "post.restored"
+
+	// Review steps, reported with the step taken
+	PostSubmitted        PostEventType = "post.submitted"
+	PostReviewerAssigned PostEventType = "post.reviewer_assigned"
+	PostApproved         PostEventType = "post.approved"
+	PostChangesRequested PostEventType = "post.changes_requested"
+	PostPublished        PostEventType = "post.published"
+	PostReviewCommented  PostEventType = "post.review_commented"
+)
+
+// PostEvent reports a post written through PostService
+type PostEvent struct {
+	Type PostEventType
+	// Post is the post as stored after the change, or as it was before
+	// being deleted
+	Post *domain.Post
+	// Review is the review step reported by review events
+	Review *domain.PostReview
+}
+
+// PostListener is called after a post changed. Listeners run on the
+// request that made the change, so they must return quickly.
+type PostListener func(ctx context.Context, event PostEvent)
+
+// postEvents holds the listeners subscribed to post changes
+type postEvents struct {
+	mu        sync.RWMutex
+	listeners []PostListener
+}
+
+func (e *postEvents) Subscribe(listener PostListener) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.listeners = append(e.listeners, listener)
+}
+
+// publish calls every listener with the event
+func (e *postEvents) publish(ctx context.Context, typ PostEventType, post *domain.Post) {
+	e.dispatch(ctx, PostEvent{Type: typ, Post: post})
+}
+
+// publishReview calls every listener with the review step taken on post
+func (e *postEvents) publishReview(ctx context.Context, typ PostEventType, post *domain.Post, review *domain.PostReview) {
+	e.dispatch(ctx, PostEvent{Type: typ, Post: post, Review: review})
+}
+
+func (e *postEvents) dispatch(ctx context.Context, event PostEvent) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, listener := range e.listeners {
+		listener(ctx, event)
+	}
+}
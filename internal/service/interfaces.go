@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/yakuter/ugin/internal/domain"
 	"github.com/yakuter/ugin/internal/repository"
@@ -17,11 +19,259 @@ type Logger interface {
 
 // PostService defines the business logic for posts
 type PostService interface {
+	// GetByID returns the post with the given public ID or slug. Posts
+	// that are not published are only found by their contributors.
 	GetByID(ctx context.Context, id string) (*domain.Post, error)
+	// GetByPreviousSlug returns the post that used slug before it was
+	// renamed, hiding unpublished posts as GetByID does
+	GetByPreviousSlug(ctx context.Context, slug string) (*domain.Post, error)
+	// GenerateMissingSlugs assigns slugs to posts created before slugs existed
+	GenerateMissingSlugs(ctx context.Context) (int, error)
+	// RenderMissingDescriptions caches the rendered HTML of posts saved before it was cached
+	RenderMissingDescriptions(ctx context.Context) (int, error)
+	// List returns published posts unless filter.Status asks for another
+	// review status or "all", which list the posts the actor contributes
+	// to, or every post for admins
 	List(ctx context.Context, filter repository.ListFilter) ([]*domain.Post, *repository.ListResult, error)
+	// Create stores a new post, published unless it is created as a draft
 	Create(ctx context.Context, post *domain.Post) error
+	// Update overwrites the post; a non-zero post.Version must match the
+	// stored version. Posts with contributors may only be updated by their
+	// owners and editors.
 	Update(ctx context.Context, id string, post *domain.Post) error
+	// Delete removes the post; a non-zero version must match the stored
+	// version. Posts with contributors may only be deleted by their owners.
+	Delete(ctx context.Context, id string, version uint) error
+	// Bulk applies ops in order. When atomic is set they are written in one
+	// transaction and any failure rolls back all of them; otherwise each
+	// operation succeeds or fails on its own.
+	Bulk(ctx context.Context, ops []domain.BulkOperation, atomic bool) ([]domain.BulkResult, error)
+	// Export writes the posts in status to w in the given file format.
+	// Statuses are resolved as for List, so other posts than published
+	// ones are only exported to their contributors and to admins.
+	Export(ctx context.Context, w io.Writer, format, status string) error
+	// Import creates a post for every valid record read from r, collecting
+	// per-line errors. With dryRun set the records are only validated.
+	Import(ctx context.Context, r io.Reader, format string, dryRun bool) (*domain.ImportResult, error)
+	// Archive returns the number of published posts per month they were
+	// created in, newest month first
+	Archive(ctx context.Context) ([]*domain.ArchiveMonth, error)
+	// ListRevisions and DiffRevisions show the history of the post to its
+	// contributors and admins
+	ListRevisions(ctx context.Context, id string) ([]*domain.PostRevision, error)
+	DiffRevisions(ctx context.Context, id string, from, to int) (*domain.RevisionDiff, error)
+	RestoreRevision(ctx context.Context, id string, revision int) (*domain.Post, error)
+	// ListTrash returns the deleted posts the actor owns, or every deleted
+	// post for admins
+	ListTrash(ctx context.Context, filter repository.ListFilter) ([]*domain.Post, *repository.ListResult, error)
+	// Restore brings back the deleted post, which only its owners may do
+	Restore(ctx context.Context, id string) (*domain.Post, error)
+	PurgeTrash(ctx context.Context, retention time.Duration) (int64, error)
+	// ListContributors returns the users with a role on the post
+	ListContributors(ctx context.Context, id string) ([]*domain.PostContributor, error)
+	// InviteContributor gives a registered user a role on the post, which
+	// only its owners may do
+	InviteContributor(ctx context.Context, id string, req *domain.InviteContributorRequest) (*domain.PostContributor, error)
+	// RemoveContributor takes a user off the post. Owners may remove anyone
+	// but the last owner; other contributors may only remove themselves.
+	RemoveContributor(ctx context.Context, id, userID string) error
+	// GetReview returns the review status of the post and its history
+	GetReview(ctx context.Context, id string) (*domain.Review, error)
+	// SubmitForReview sends a draft, or a post changes were requested to,
+	// to its reviewer. Only owners and editors may submit.
+	SubmitForReview(ctx context.Context, id string, req *domain.SubmitReviewRequest) (*domain.Post, error)
+	// AssignReviewer hands a post that is not approved yet to another of
+	// its reviewers or owners
+	AssignReviewer(ctx context.Context, id string, req *domain.AssignReviewerRequest) (*domain.Post, error)
+	// Approve accepts a post in review, which only its reviewer may do
+	Approve(ctx context.Context, id string, req *domain.ReviewRequest) (*domain.Post, error)
+	// RequestChanges returns a post in review to its authors with a
+	// comment, which only its reviewer may do
+	RequestChanges(ctx context.Context, id string, req *domain.ReviewRequest) (*domain.Post, error)
+	// Publish publishes an approved post, which only its owners may do
+	Publish(ctx context.Context, id string, req *domain.ReviewRequest) (*domain.Post, error)
+	// CommentOnReview adds a comment to the post's review history
+	CommentOnReview(ctx context.Context, id string, req *domain.ReviewRequest) (*domain.PostReview, error)
+	// ReleaseHeld returns a post held by the content moderator to status,
+	// or publishes it when status is empty
+	ReleaseHeld(ctx context.Context, id, status string) (*domain.Post, error)
+	// DiscardHeld moves a post held by the content moderator to the trash
+	DiscardHeld(ctx context.Context, id string) error
+	// Subscribe registers listener to be called after every post created,
+	// updated, deleted, restored or reviewed
+	Subscribe(listener PostListener)
+}
+
+// CommentService defines the business logic for comments
+type CommentService interface {
+	// List returns the post's comments in status as threads of replies,
+	// along with the number of comments listed. An empty status lists
+	// approved comments; other states and "all" need a signed-in actor.
+	List(ctx context.Context, postID string, status string) ([]*domain.Comment, int, error)
+	Create(ctx context.Context, postID string, req *domain.CreateCommentRequest) (*domain.Comment, error)
+	// Update edits the body, which only its author may do and which is
+	// moderated again, and sets the status, which owners and editors of the
+	// post and admins do. Comments held for a moderator answer ErrConflict.
+	Update(ctx context.Context, id string, req *domain.UpdateCommentRequest) (*domain.Comment, error)
+	// Delete removes the comment and its replies, returning how many were
+	// removed. Only owners and editors of the post and admins may.
+	Delete(ctx context.Context, id string) (int64, error)
+}
+
+// ModerationService defines the business logic for the queue of content
+// the content moderator flagged
+type ModerationService interface {
+	// List returns the queued items in status, pending ones when empty
+	List(ctx context.Context, status string, limit, offset int) ([]*domain.ModerationItem, int64, error)
+	// Approve shows the held content and records moderator's decision
+	Approve(ctx context.Context, id, moderator string) (*domain.ModerationItem, error)
+	// Reject trashes a held post or rejects a held comment
+	Reject(ctx context.Context, id, moderator string) (*domain.ModerationItem, error)
+}
+
+// TranslationService defines the business logic for post translations
+type TranslationService interface {
+	// List returns the post's translations ordered by locale
+	List(ctx context.Context, postID string) ([]*domain.PostTranslation, error)
+	// Put creates or replaces the post's translation to locale without
+	// changing the post itself, reporting whether the translation is new
+	Put(ctx context.Context, postID, locale string, req *domain.PostTranslationRequest) (*domain.PostTranslation, bool, error)
+	Delete(ctx context.Context, postID, locale string) error
+	// Translate gives each post the name and description of its translation
+	// to the first locale of chain it has one for, and sets the locale the
+	// post is shown in. Posts without such a translation stay as written.
+	Translate(ctx context.Context, chain []string, posts ...*domain.Post) error
+}
+
+// EngagementService defines the business logic for reactions and
+// bookmarks, which are always those of the signed-in actor
+type EngagementService interface {
+	// Reactions returns the emoji posts may be reacted to with
+	Reactions() []string
+	// React sets the actor's reaction to the post, replacing an earlier
+	// one, and reports whether the actor had not reacted before
+	React(ctx context.Context, postID string, req *domain.ReactionRequest) (*domain.Reaction, bool, error)
+	Unreact(ctx context.Context, postID string) error
+	// Bookmark saves the post for the actor and reports whether it was not
+	// bookmarked already
+	Bookmark(ctx context.Context, postID string) (*domain.Bookmark, bool, error)
+	Unbookmark(ctx context.Context, postID string) error
+	// ListBookmarks returns the actor's bookmarked posts, most recent first,
+	// along with how many there are
+	ListBookmarks(ctx context.Context, limit, offset int) ([]*domain.Post, int64, error)
+}
+
+// SitemapService defines the business logic for the sitemap of posts and
+// tag pages, kept in memory and refreshed from the posts changed since
+type SitemapService interface {
+	// Sitemap returns the sitemap served at /sitemap.xml, a sitemap index
+	// when the site has more URLs than fit into one sitemap
+	Sitemap(ctx context.Context) ([]byte, error)
+	// Page returns the numbered sitemap, counting from 1, of a site listed
+	// in a sitemap index
+	Page(ctx context.Context, page int) ([]byte, error)
+}
+
+// AnalyticsService defines the business logic for post views, which are
+// buffered in memory and written in batches
+type AnalyticsService interface {
+	// RecordView queues a view of the post by visitor without blocking.
+	// Repeated views by the same visitor within the dedupe window count once.
+	RecordView(postID uint, visitor string)
+	// Run counts and writes queued views until ctx is cancelled, then
+	// writes what is left
+	Run(ctx context.Context)
+	// Stats returns the post's views with a daily series over rng, such as
+	// "30d", ending today
+	Stats(ctx context.Context, postID string, rng string) (*domain.PostStats, error)
+	// TopPosts returns up to limit posts, most viewed over rng first
+	TopPosts(ctx context.Context, rng string, limit int) ([]*domain.PostViewCount, error)
+}
+
+// RelatedService defines the business logic for finding posts related to
+// a post by shared tags and text similarity
+type RelatedService interface {
+	// Related returns up to limit published posts most related to the
+	// published post, best first
+	Related(ctx context.Context, postID string, limit int) ([]*domain.RelatedPost, error)
+	// PostChanged updates the posts related posts are found among; it is
+	// subscribed to PostService
+	PostChanged(ctx context.Context, event PostEvent)
+}
+
+// SuggestService defines the business logic for suggesting posts and tags
+// as names are typed and tags for draft posts, from an index in memory
+type SuggestService interface {
+	// Load indexes every post; it is called once at startup
+	Load(ctx context.Context) error
+	// Suggest returns up to limit posts with a word of their name, and up
+	// to limit tags with their name, starting with q
+	Suggest(ctx context.Context, q string, limit int) (*domain.Suggestions, error)
+	// SuggestTags proposes up to limit existing tags for a draft post: the
+	// tags it mentions and those of the posts most similar to it
+	SuggestTags(ctx context.Context, req *domain.TagSuggestionRequest, limit int) ([]domain.TagSuggestion, error)
+	// PostChanged updates the index; it is subscribed to PostService
+	PostChanged(ctx context.Context, event PostEvent)
+}
+
+// CategoryService defines the business logic for the category tree
+type CategoryService interface {
+	// Tree returns the root categories with their subcategories nested
+	// below them, along with the number of categories
+	Tree(ctx context.Context) ([]*domain.Category, int, error)
+	// GetByID returns the category with the given public ID or slug and its subtree
+	GetByID(ctx context.Context, id string) (*domain.Category, error)
+	Create(ctx context.Context, req *domain.CreateCategoryRequest) (*domain.Category, error)
+	// Update renames the category, moving it to a new slug
+	Update(ctx context.Context, id string, req *domain.UpdateCategoryRequest) (*domain.Category, error)
+	// Move puts the category and its subtree below another category, or at the root
+	Move(ctx context.Context, id string, req *domain.MoveCategoryRequest) (*domain.Category, error)
+	// Delete removes a category that has no subcategories or posts
+	Delete(ctx context.Context, id string) error
+}
+
+// SeriesService defines the interface for series business logic
+type SeriesService interface {
+	// List returns every series ordered by name, without their posts
+	List(ctx context.Context) ([]*domain.Series, error)
+	// GetByID returns the series with its posts in reading order
+	GetByID(ctx context.Context, id string) (*domain.Series, error)
+	// Create makes the actor the owner of the new series. Only the owner
+	// and admins change a series afterwards.
+	Create(ctx context.Context, req *domain.SeriesRequest) (*domain.Series, error)
+	Update(ctx context.Context, id string, req *domain.SeriesRequest) (*domain.Series, error)
+	// Delete removes the series; its posts are kept
 	Delete(ctx context.Context, id string) error
+	// AddPost puts a post that belongs to no series into this one, which
+	// needs the actor to be an owner or editor of the post
+	AddPost(ctx context.Context, id string, req *domain.AddSeriesPostRequest) (*domain.Series, error)
+	// RemovePost takes a post out of the series
+	RemovePost(ctx context.Context, id, postID string) (*domain.Series, error)
+	// Reorder changes the reading order of the posts of the series
+	Reorder(ctx context.Context, id string, req *domain.ReorderSeriesRequest) (*domain.Series, error)
+}
+
+// BlobStore stores the contents of uploaded files under slash-separated keys
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get returns blob.ErrNotFound when nothing is stored under key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// AttachmentService defines the business logic for post attachments
+type AttachmentService interface {
+	// Upload stores the file read from r as an attachment of kind on the
+	// post. A new cover replaces the previous one.
+	Upload(ctx context.Context, postID, kind, filename string, r io.Reader) (*domain.Attachment, error)
+	List(ctx context.Context, postID string) ([]*domain.Attachment, error)
+	Delete(ctx context.Context, id string) error
+	// Open checks a signed download link and returns the attachment with its contents
+	Open(ctx context.Context, id, expires, signature string) (*domain.Attachment, io.ReadCloser, error)
+	// Sign sets a fresh signed download URL on each attachment
+	Sign(attachments ...*domain.Attachment)
+	// DeleteOrphaned removes the files of posts that were purged
+	DeleteOrphaned(ctx context.Context) (int, error)
 }
 
 // AuthService defines the business logic for authentication
@@ -31,4 +281,3 @@ type AuthService interface {
 	RefreshToken(ctx context.Context, refreshToken string) (*domain.TokenDetails, error)
 	ValidateToken(ctx context.Context, token string) (*domain.TokenClaims, error)
 }
-
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/pkg/moderation"
+)
+
+// ErrModerationItemNotFound is returned when the moderation queue has no such item
+var ErrModerationItemNotFound = fmt.Errorf("moderation item %w", repository.ErrNotFound)
+
+// excerptLength bounds the excerpt of flagged content kept in the queue, in characters
+const excerptLength = 280
+
+// ContentModerator screens text users write before it is shown
+type ContentModerator interface {
+	// Moderate returns the reasons text needs a moderator's approval before
+	// it is shown, or none when it may be shown right away
+	Moderate(ctx context.Context, text string) ([]string, error)
+}
+
+// ModerationConfig holds the configuration of the built-in content moderator
+type ModerationConfig struct {
+	// Words lists the words and phrases that hold content for moderation
+	Words []string
+	// MaxLinks is the most links content may have without being held; zero
+	// allows any number
+	MaxLinks int
+}
+
+type filterModerator struct {
+	filter *moderation.Filter
+}
+
+// NewFilterModerator creates the built-in content moderator, which flags
+// text containing listed words or too many links
+func NewFilterModerator(cfg *ModerationConfig) ContentModerator {
+	return &filterModerator{filter: moderation.New(cfg.Words, cfg.MaxLinks)}
+}
+
+func (m *filterModerator) Moderate(ctx context.Context, text string) ([]string, error) {
+	return m.filter.Check(text), nil
+}
+
+type moderationService struct {
+	queue    repository.ModerationRepository
+	posts    PostService
+	comments repository.CommentRepository
+	logger   Logger
+}
+
+// NewModerationService creates a new moderation queue service
+func NewModerationService(queue repository.ModerationRepository, posts PostService, comments repository.CommentRepository, logger Logger) ModerationService {
+	return &moderationService{
+		queue:    queue,
+		posts:    posts,
+		comments: comments,
+		logger:   logger,
+	}
+}
+
+func (s *moderationService) List(ctx context.Context, status string, limit, offset int) ([]*domain.ModerationItem, int64, error) {
+	switch status {
+	case "":
+		status = domain.ModerationPending
+	case domain.ModerationPending, domain.ModerationApproved, domain.ModerationRejected:
+	default:
+		return nil, 0, fmt.Errorf("%w: unknown moderation status %q", repository.ErrInvalidInput, status)
+	}
+	if limit <= 0 {
+		limit = 25
+	}
+	if limit > 100 {
+		limit = 100 // Max limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	items, total, err := s.queue.List(ctx, status, limit, offset)
+	if err != nil {
+		s.logger.Error("failed to list moderation items", "status", status, "error", err)
+		return nil, 0, fmt.Errorf("list moderation items: %w", err)
+	}
+
+	return items, total, nil
+}
+
+func (s *moderationService) Approve(ctx context.Context, id, moderator string) (*domain.ModerationItem, error) {
+	return s.decide(ctx, id, moderator, domain.ModerationApproved)
+}
+
+func (s *moderationService) Reject(ctx context.Context, id, moderator string) (*domain.ModerationItem, error) {
+	return s.decide(ctx, id, moderator, domain.ModerationRejected)
+}
+
+// decide applies the moderator's decision to the held content and records
+// it on the queued item
+func (s *moderationService) decide(ctx context.Context, id, moderator, decision string) (*domain.ModerationItem, error) {
+	if id == "" {
+		return nil, repository.ErrInvalidInput
+	}
+
+	item, err := s.queue.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrModerationItemNotFound
+		}
+		s.logger.Error("failed to get moderation item", "id", id, "error", err)
+		return nil, fmt.Errorf("moderate: %w", err)
+	}
+	if item.Status != domain.ModerationPending {
+		return nil, fmt.Errorf("%w: item was already %s", repository.ErrConflict, item.Status)
+	}
+
+	switch item.Kind {
+	case domain.ModerationPost:
+		err = s.decidePost(ctx, item, decision)
+	case domain.ModerationComment:
+		err = s.decideComment(ctx, item, decision)
+	}
+	// Content deleted while it waited needs no decision
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	item.Status = decision
+	item.ModeratedBy = moderator
+	item.ModeratedAt = &now
+	if err := s.queue.Resolve(ctx, item); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, fmt.Errorf("%w: item was decided meanwhile", repository.ErrConflict)
+		}
+		s.logger.Error("failed to resolve moderation item", "id", id, "error", err)
+		return nil, fmt.Errorf("moderate: %w", err)
+	}
+
+	s.logger.Info("content moderated", "id", id, "kind", item.Kind, "target", item.TargetPublicID, "decision", decision, "by", moderator)
+	return item, nil
+}
+
+func (s *moderationService) decidePost(ctx context.Context, item *domain.ModerationItem, decision string) error {
+	if decision == domain.ModerationApproved {
+		_, err := s.posts.ReleaseHeld(ctx, item.TargetPublicID, item.ReleaseStatus)
+		return err
+	}
+	return s.posts.DiscardHeld(ctx, item.TargetPublicID)
+}
+
+func (s *moderationService) decideComment(ctx context.Context, item *domain.ModerationItem, decision string) error {
+	comment, err := s.comments.GetByID(ctx, item.TargetPublicID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			s.logger.Error("failed to get held comment", "id", item.TargetPublicID, "error", err)
+		}
+		return err
+	}
+
+	comment.Status = domain.CommentRejected
+	if decision == domain.ModerationApproved {
+		comment.Status = domain.CommentApproved
+	}
+	if err := s.comments.Update(ctx, comment); err != nil {
+		s.logger.Error("failed to moderate comment", "id", item.TargetPublicID, "error", err)
+		return fmt.Errorf("moderate comment: %w", err)
+	}
+	return nil
+}
+
+// screen asks moderator about text and returns the item to queue for it,
+// or nil when the text may be shown. Without a moderator nothing is held.
+func screen(ctx context.Context, moderator ContentModerator, kind, text string) (*domain.ModerationItem, error) {
+	if moderator == nil {
+		return nil, nil
+	}
+
+	reasons, err := moderator.Moderate(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("moderate %s: %w", kind, err)
+	}
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+
+	return &domain.ModerationItem{Kind: kind, Excerpt: excerpt(text), Reasons: reasons}, nil
+}
+
+// excerpt returns the start of text, cut at a word boundary
+func excerpt(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= excerptLength {
+		return text
+	}
+
+	runes := []rune(text)[:excerptLength]
+	cut := string(runes)
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
+
+func (s *postService) ReleaseHeld(ctx context.Context, id, status string) (*domain.Post, error) {
+	post, err := s.getHeld(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		status = domain.PostPublished
+	}
+	post.Status = status
+	if err := s.repo.Update(ctx, post); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			s.logger.Info("post modified concurrently", "id", id)
+			return nil, err
+		}
+		s.logger.Error("failed to release held post", "id", id, "error", err)
+		return nil, fmt.Errorf("release post: %w", err)
+	}
+
+	s.logger.Info("held post released", "id", id, "status", status)
+	s.publish(ctx, PostUpdated, post)
+	return post, nil
+}
+
+func (s *postService) DiscardHeld(ctx context.Context, id string) error {
+	post, err := s.getHeld(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, post.ID, 0); err != nil {
+		s.logger.Error("failed to discard held post", "id", id, "error", err)
+		return fmt.Errorf("discard post: %w", err)
+	}
+
+	s.logger.Info("held post discarded", "id", id)
+	s.publish(ctx, PostDeleted, post)
+	return nil
+}
+
+func (s *postService) getHeld(ctx context.Context, id string) (*domain.Post, error) {
+	post, err := s.getPost(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if post.Status != domain.PostHeld {
+		return nil, fmt.Errorf("%w: post is %s", ErrInvalidTransition, post.Status)
+	}
+	return post, nil
+}
+
+// screenPost holds the post when the content moderator flags it. The
+// returned item is queued in the transaction that saves the post.
+func (s *postService) screenPost(ctx context.Context, post *domain.Post) (*domain.ModerationItem, error) {
+	text := post.Name + "\n" + post.Description
+	for _, tag := range post.Tags {
+		text += "\n" + tag.Name + " " + tag.Description
+	}
+
+	item, err := screen(ctx, s.moderator, domain.ModerationPost, text)
+	if err != nil {
+		s.logger.Error("failed to moderate post", "name", post.Name, "error", err)
+		return nil, err
+	}
+	if item == nil {
+		return nil, nil
+	}
+
+	// A post held again keeps the status it was first held from
+	if post.Status != domain.PostHeld {
+		item.ReleaseStatus = post.Status
+		post.Status = domain.PostHeld
+	}
+	return item, nil
+}
+
+// logHeld notes a saved post that its records queued for a moderator
+func (s *postService) logHeld(post *domain.Post, records *repository.PostRecords) {
+	if records != nil && records.Hold != nil {
+		s.logger.Info("post held for moderation", "id", post.PublicID, "reasons", records.Hold.Reasons)
+	}
+}
@@ -4,25 +4,62 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/yakuter/ugin/internal/domain"
 	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/pkg/diff"
+	"github.com/yakuter/ugin/pkg/markup"
+	"github.com/yakuter/ugin/pkg/slug"
+	"github.com/yakuter/ugin/pkg/uid"
 )
 
+// maxSlugAttempts bounds the numeric suffixes tried for a colliding slug
+const maxSlugAttempts = 1000
+
 type postService struct {
-	repo   repository.PostRepository
-	logger Logger
+	repo         repository.PostRepository
+	revisions    repository.PostRevisionRepository
+	categories   repository.CategoryRepository
+	contributors repository.ContributorRepository
+	reviews      repository.ReviewRepository
+	moderator    ContentModerator
+	moderation   repository.ModerationRepository
+	logger       Logger
+	postEvents
 }
 
 // NewPostService creates a new post service
-func NewPostService(repo repository.PostRepository, logger Logger) PostService {
+func NewPostService(repo repository.PostRepository, revisions repository.PostRevisionRepository, categories repository.CategoryRepository, contributors repository.ContributorRepository, reviews repository.ReviewRepository, moderator ContentModerator, moderation repository.ModerationRepository, logger Logger) PostService {
 	return &postService{
-		repo:   repo,
-		logger: logger,
+		repo:         repo,
+		revisions:    revisions,
+		categories:   categories,
+		contributors: contributors,
+		reviews:      reviews,
+		moderator:    moderator,
+		moderation:   moderation,
+		logger:       logger,
 	}
 }
 
 func (s *postService) GetByID(ctx context.Context, id string) (*domain.Post, error) {
+	post, err := s.getPost(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := readable(ctx, s.contributors, s.logger, post); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// getPost returns the post with the given public ID or slug whatever its
+// status, for changes that check the actor's role themselves
+func (s *postService) getPost(ctx context.Context, id string) (*domain.Post, error) {
 	if id == "" {
 		return nil, repository.ErrInvalidInput
 	}
@@ -40,6 +77,93 @@ func (s *postService) GetByID(ctx context.Context, id string) (*domain.Post, err
 	return post, nil
 }
 
+func (s *postService) GetByPreviousSlug(ctx context.Context, slug string) (*domain.Post, error) {
+	if slug == "" {
+		return nil, repository.ErrInvalidInput
+	}
+
+	post, err := s.repo.GetByPreviousSlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get post by previous slug", "slug", slug, "error", err)
+		return nil, fmt.Errorf("get post by previous slug: %w", err)
+	}
+
+	if err := readable(ctx, s.contributors, s.logger, post); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+func (s *postService) GenerateMissingSlugs(ctx context.Context) (int, error) {
+	generated := 0
+
+	for {
+		posts, err := s.repo.ListWithoutSlug(ctx, 100)
+		if err != nil {
+			s.logger.Error("failed to list posts without slug", "error", err)
+			return generated, fmt.Errorf("generate slugs: %w", err)
+		}
+		if len(posts) == 0 {
+			break
+		}
+
+		for _, post := range posts {
+			postSlug, err := s.uniqueSlug(ctx, post.Name, post.ID, nil)
+			if err != nil {
+				return generated, err
+			}
+
+			if err := s.repo.SetSlug(ctx, post.ID, postSlug); err != nil {
+				s.logger.Error("failed to set slug", "id", post.ID, "error", err)
+				return generated, fmt.Errorf("generate slugs: %w", err)
+			}
+			generated++
+		}
+	}
+
+	if generated > 0 {
+		s.logger.Info("generated missing post slugs", "count", generated)
+	}
+	return generated, nil
+}
+
+func (s *postService) RenderMissingDescriptions(ctx context.Context) (int, error) {
+	rendered := 0
+
+	for {
+		posts, err := s.repo.ListUnrendered(ctx, 100)
+		if err != nil {
+			s.logger.Error("failed to list unrendered posts", "error", err)
+			return rendered, fmt.Errorf("render descriptions: %w", err)
+		}
+		if len(posts) == 0 {
+			break
+		}
+
+		for _, post := range posts {
+			// Stored formats were validated on save; anything else is shown as text
+			if err := renderDescription(post); err != nil {
+				post.Format = domain.FormatPlain
+				renderDescription(post)
+			}
+
+			if err := s.repo.SetDescriptionHTML(ctx, post.ID, post.DescriptionHTML); err != nil {
+				s.logger.Error("failed to set description html", "id", post.ID, "error", err)
+				return rendered, fmt.Errorf("render descriptions: %w", err)
+			}
+			rendered++
+		}
+	}
+
+	if rendered > 0 {
+		s.logger.Info("rendered missing post descriptions", "count", rendered)
+	}
+	return rendered, nil
+}
+
 func (s *postService) List(ctx context.Context, filter repository.ListFilter) ([]*domain.Post, *repository.ListResult, error) {
 	// Set default values
 	if filter.Limit <= 0 {
@@ -55,8 +179,22 @@ func (s *postService) List(ctx context.Context, filter repository.ListFilter) ([
 		filter.Order = "DESC"
 	}
 
+	if filter.Year < 0 || filter.Year > 9999 || filter.Month < 0 || filter.Month > 12 {
+		return nil, nil, fmt.Errorf("%w: invalid year or month", repository.ErrInvalidInput)
+	}
+	if filter.Month > 0 && filter.Year == 0 {
+		return nil, nil, fmt.Errorf("%w: month needs a year", repository.ErrInvalidInput)
+	}
+
+	if err := statusFilter(ctx, &filter); err != nil {
+		return nil, nil, err
+	}
+
 	posts, result, err := s.repo.List(ctx, filter)
 	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, fmt.Errorf("%w: category %q not found", repository.ErrNotFound, filter.Category)
+		}
 		s.logger.Error("failed to list posts", "error", err)
 		return nil, nil, fmt.Errorf("list posts: %w", err)
 	}
@@ -65,21 +203,38 @@ func (s *postService) List(ctx context.Context, filter repository.ListFilter) ([
 	return posts, result, nil
 }
 
+func (s *postService) Archive(ctx context.Context) ([]*domain.ArchiveMonth, error) {
+	months, err := s.repo.Archive(ctx, domain.PostPublished)
+	if err != nil {
+		s.logger.Error("failed to get archive", "error", err)
+		return nil, fmt.Errorf("get archive: %w", err)
+	}
+
+	return months, nil
+}
+
 func (s *postService) Create(ctx context.Context, post *domain.Post) error {
 	if post == nil {
 		return repository.ErrInvalidInput
 	}
 
-	if post.Name == "" {
-		return fmt.Errorf("%w: name is required", repository.ErrInvalidInput)
+	records, err := s.prepareCreate(ctx, post, nil)
+	if err != nil {
+		return err
 	}
 
-	if err := s.repo.Create(ctx, post); err != nil {
+	batch := &repository.PostBatch{
+		Create:  []*domain.Post{post},
+		Records: map[*domain.Post]*repository.PostRecords{post: records},
+	}
+	if err := s.repo.ApplyBatch(ctx, batch); err != nil {
 		s.logger.Error("failed to create post", "error", err)
 		return fmt.Errorf("create post: %w", err)
 	}
 
+	s.logHeld(post, records)
 	s.logger.Info("post created", "id", post.ID, "name", post.Name)
+	s.publish(ctx, PostCreated, post)
 	return nil
 }
 
@@ -88,42 +243,523 @@ func (s *postService) Update(ctx context.Context, id string, post *domain.Post)
 		return repository.ErrInvalidInput
 	}
 
-	// Check if post exists
-	existing, err := s.repo.GetByID(ctx, id)
+	existing, records, err := s.prepareUpdate(ctx, id, post, nil)
 	if err != nil {
 		return err
 	}
 
-	// Update fields
-	existing.Name = post.Name
-	existing.Description = post.Description
-	existing.Tags = post.Tags
-
-	if err := s.repo.Update(ctx, existing); err != nil {
+	batch := &repository.PostBatch{
+		Update:  []*domain.Post{existing},
+		Records: map[*domain.Post]*repository.PostRecords{existing: records},
+	}
+	if err := s.repo.ApplyBatch(ctx, batch); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			s.logger.Info("post modified concurrently", "id", id)
+			return repository.ErrConflict
+		}
 		s.logger.Error("failed to update post", "id", id, "error", err)
 		return fmt.Errorf("update post: %w", err)
 	}
 
+	// Reflect the stored state, including the new version, back to the caller
+	*post = *existing
+
+	s.logHeld(existing, records)
 	s.logger.Info("post updated", "id", id)
+	s.publish(ctx, PostUpdated, existing)
 	return nil
 }
 
-func (s *postService) Delete(ctx context.Context, id string) error {
+func (s *postService) Delete(ctx context.Context, id string, version uint) error {
 	if id == "" {
 		return repository.ErrInvalidInput
 	}
 
-	// Check if post exists
-	if _, err := s.repo.GetByID(ctx, id); err != nil {
+	existing, err := s.prepareDelete(ctx, id, version)
+	if err != nil {
 		return err
 	}
 
-	if err := s.repo.Delete(ctx, id); err != nil {
+	if err := s.repo.Delete(ctx, existing.ID, version); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			s.logger.Info("post modified concurrently", "id", id)
+			return err
+		}
 		s.logger.Error("failed to delete post", "id", id, "error", err)
 		return fmt.Errorf("delete post: %w", err)
 	}
 
 	s.logger.Info("post deleted", "id", id)
+	s.publish(ctx, PostDeleted, existing)
+	return nil
+}
+
+// prepareCreate checks a new post and readies it for writing. It returns
+// the records to write in the same transaction. Slugs handed out to other
+// posts of the same batch are in reserved, which may be nil.
+func (s *postService) prepareCreate(ctx context.Context, post *domain.Post, reserved map[string]bool) (*repository.PostRecords, error) {
+	// The author becomes the owner; posts without one could never be
+	// changed again by anyone but an admin
+	if ActorFromContext(ctx) == "" {
+		return nil, ErrForbidden
+	}
+
+	if post.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", repository.ErrInvalidInput)
+	}
+
+	if err := renderDescription(post); err != nil {
+		return nil, err
+	}
+
+	if err := initialStatus(ctx, post); err != nil {
+		return nil, err
+	}
+
+	held, err := s.screenPost(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.resolveCategory(ctx, post); err != nil {
+		return nil, err
+	}
+
+	post.Version = 1
+	post.PublicID = ""
+	for i := range post.Tags {
+		post.Tags[i].PublicID = ""
+	}
+
+	postSlug, err := s.uniqueSlug(ctx, post.Name, 0, reserved)
+	if err != nil {
+		return nil, err
+	}
+	post.Slug = postSlug
+	if reserved != nil {
+		reserved[postSlug] = true
+	}
+
+	actor := ActorFromContext(ctx)
+	return &repository.PostRecords{
+		Revision: newRevision(post, actor),
+		Owner:    actor,
+		Hold:     held,
+	}, nil
+}
+
+// prepareUpdate checks an update of the post with the given ID and
+// returns the stored post with the changes applied, along with the
+// records to write in the same transaction. Slugs handed out to other
+// posts of the same batch are in reserved, which may be nil.
+func (s *postService) prepareUpdate(ctx context.Context, id string, post *domain.Post, reserved map[string]bool) (*domain.Post, *repository.PostRecords, error) {
+	if id == "" {
+		return nil, nil, fmt.Errorf("%w: id is required", repository.ErrInvalidInput)
+	}
+
+	if post.Name == "" {
+		return nil, nil, fmt.Errorf("%w: name is required", repository.ErrInvalidInput)
+	}
+
+	if err := checkFormat(post.Format); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.resolveCategory(ctx, post); err != nil {
+		return nil, nil, err
+	}
+
+	// Check if post exists
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.authorize(ctx, existing, domain.RoleOwner, domain.RoleEditor); err != nil {
+		return nil, nil, err
+	}
+
+	// A non-zero version is the version the caller last saw
+	if post.Version != 0 && post.Version != existing.Version {
+		s.logger.Info("post version mismatch", "id", id, "expected", post.Version, "actual", existing.Version)
+		return nil, nil, repository.ErrConflict
+	}
+
+	// Posts created before revisions were tracked get their current
+	// state recorded first so the update can be diffed and undone
+	baseline := newRevision(existing, "")
+
+	// Renaming a post moves it to a new slug; the old one keeps redirecting
+	if post.Name != existing.Name {
+		postSlug, err := s.uniqueSlug(ctx, post.Name, existing.ID, reserved)
+		if err != nil {
+			return nil, nil, err
+		}
+		existing.Slug = postSlug
+		if reserved != nil {
+			reserved[postSlug] = true
+		}
+	}
+
+	// Update fields; posts keep their format unless a new one is given
+	existing.Name = post.Name
+	existing.Description = post.Description
+	existing.Tags = post.Tags
+	existing.CategoryID = post.CategoryID
+	existing.CategoryPublicID = post.CategoryPublicID
+	existing.Category = post.Category
+	if post.Format != "" {
+		existing.Format = post.Format
+	}
+	if err := renderDescription(existing); err != nil {
+		return nil, nil, err
+	}
+	withdrawApproval(existing)
+
+	held, err := s.screenPost(ctx, existing)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return existing, &repository.PostRecords{
+		Baseline: baseline,
+		Revision: newRevision(existing, ActorFromContext(ctx)),
+		Hold:     held,
+	}, nil
+}
+
+// prepareDelete checks a delete of the post with the given ID and
+// returns the stored post
+func (s *postService) prepareDelete(ctx context.Context, id string, version uint) (*domain.Post, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", repository.ErrInvalidInput)
+	}
+
+	// Check if post exists
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, existing, domain.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	if version != 0 && version != existing.Version {
+		s.logger.Info("post version mismatch", "id", id, "expected", version, "actual", existing.Version)
+		return nil, repository.ErrConflict
+	}
+
+	return existing, nil
+}
+
+func (s *postService) ListTrash(ctx context.Context, filter repository.ListFilter) ([]*domain.Post, *repository.ListResult, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 25
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100 // Max limit
+	}
+
+	// Users see the posts they own in the trash, admins every post
+	actor := ActorFromContext(ctx)
+	if actor == "" {
+		return nil, nil, ErrForbidden
+	}
+	filter.Contributor, filter.Roles, filter.IncludePublished = "", nil, false
+	if !IsAdmin(ctx) {
+		filter.Contributor, filter.Roles = actor, []string{domain.RoleOwner}
+	}
+
+	posts, result, err := s.repo.ListDeleted(ctx, filter)
+	if err != nil {
+		s.logger.Error("failed to list deleted posts", "error", err)
+		return nil, nil, fmt.Errorf("list trash: %w", err)
+	}
+
+	return posts, result, nil
+}
+
+func (s *postService) Restore(ctx context.Context, id string) (*domain.Post, error) {
+	if id == "" {
+		return nil, repository.ErrInvalidInput
+	}
+
+	deleted, err := s.repo.GetDeleted(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			s.logger.Info("deleted post not found", "id", id)
+			return nil, err
+		}
+		s.logger.Error("failed to get deleted post", "id", id, "error", err)
+		return nil, fmt.Errorf("get deleted post: %w", err)
+	}
+
+	if err := s.authorize(ctx, deleted, domain.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Restore(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			s.logger.Info("deleted post not found", "id", id)
+			return nil, err
+		}
+		s.logger.Error("failed to restore post", "id", id, "error", err)
+		return nil, fmt.Errorf("restore post: %w", err)
+	}
+
+	s.logger.Info("post restored", "id", id)
+	post, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, PostRestored, post)
+	return post, nil
+}
+
+func (s *postService) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	if retention < 0 {
+		return 0, repository.ErrInvalidInput
+	}
+
+	purged, err := s.repo.Purge(ctx, time.Now().Add(-retention))
+	if err != nil {
+		s.logger.Error("failed to purge trash", "error", err)
+		return 0, fmt.Errorf("purge trash: %w", err)
+	}
+
+	if purged > 0 {
+		s.logger.Info("purged deleted posts", "count", purged)
+	}
+	return purged, nil
+}
+
+func (s *postService) ListRevisions(ctx context.Context, id string) ([]*domain.PostRevision, error) {
+	post, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Revisions name their authors, so only contributors read them
+	if err := s.authorize(ctx, post, domain.RoleOwner, domain.RoleEditor, domain.RoleReviewer); err != nil {
+		return nil, err
+	}
+
+	revs, err := s.revisions.ListByPostID(ctx, post.ID)
+	if err != nil {
+		s.logger.Error("failed to list revisions", "id", id, "error", err)
+		return nil, fmt.Errorf("list revisions: %w", err)
+	}
+
+	return revs, nil
+}
+
+func (s *postService) DiffRevisions(ctx context.Context, id string, from, to int) (*domain.RevisionDiff, error) {
+	if from < 0 || to <= 0 {
+		return nil, fmt.Errorf("%w: invalid revision number", repository.ErrInvalidInput)
+	}
+
+	post, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, post, domain.RoleOwner, domain.RoleEditor, domain.RoleReviewer); err != nil {
+		return nil, err
+	}
+
+	target, err := s.getRevision(ctx, post.ID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revision 0 stands for the empty post before the first revision
+	base := &domain.PostRevision{PostID: post.ID}
+	if from > 0 {
+		if base, err = s.getRevision(ctx, post.ID, from); err != nil {
+			return nil, err
+		}
+	}
+
+	return &domain.RevisionDiff{
+		PostID:      post.PublicID,
+		From:        from,
+		To:          to,
+		Name:        diff.Lines(base.Name, target.Name),
+		Description: diff.Lines(base.Description, target.Description),
+		Tags:        diff.Slices(revisionTagLines(base.Tags), revisionTagLines(target.Tags)),
+	}, nil
+}
+
+func (s *postService) RestoreRevision(ctx context.Context, id string, revision int) (*domain.Post, error) {
+	if revision <= 0 {
+		return nil, fmt.Errorf("%w: invalid revision number", repository.ErrInvalidInput)
+	}
+
+	post, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := s.getRevision(ctx, post.ID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revisions do not track the category, so the post keeps its current one
+	restored := &domain.Post{
+		Name:             rev.Name,
+		Description:      rev.Description,
+		Format:           rev.Format,
+		CategoryPublicID: post.CategoryPublicID,
+	}
+	for _, tag := range rev.Tags {
+		restored.Tags = append(restored.Tags, domain.Tag{Name: tag.Name, Description: tag.Description})
+	}
+
+	// Restoring goes through Update so it is recorded as a new revision
+	if err := s.Update(ctx, id, restored); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("post revision restored", "id", id, "revision", revision)
+	return s.GetByID(ctx, id)
+}
+
+func (s *postService) getRevision(ctx context.Context, postID uint, revision int) (*domain.PostRevision, error) {
+	rev, err := s.revisions.Get(ctx, postID, revision)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get revision", "post_id", postID, "revision", revision, "error", err)
+		return nil, fmt.Errorf("get revision: %w", err)
+	}
+
+	return rev, nil
+}
+
+// resolveCategory looks up the category post.CategoryPublicID names by
+// public ID or slug and sets it as the post's primary category. Posts
+// naming no category are left uncategorized.
+func (s *postService) resolveCategory(ctx context.Context, post *domain.Post) error {
+	post.CategoryID = nil
+	post.Category = nil
+	if post.CategoryPublicID == "" {
+		return nil
+	}
+
+	category, err := s.categories.GetByID(ctx, post.CategoryPublicID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: category %q not found", repository.ErrInvalidInput, post.CategoryPublicID)
+		}
+		s.logger.Error("failed to get category", "id", post.CategoryPublicID, "error", err)
+		return fmt.Errorf("get category: %w", err)
+	}
+
+	post.CategoryID = &category.ID
+	post.CategoryPublicID = category.PublicID
+	post.Category = category
+	return nil
+}
+
+// uniqueSlug returns a slug for name that no other post uses or used,
+// appending a numeric suffix on collisions. Slugs in reserved count as
+// taken; they belong to posts not stored yet.
+func (s *postService) uniqueSlug(ctx context.Context, name string, postID uint, reserved map[string]bool) (string, error) {
+	base := slug.Make(name)
+	if base == "" {
+		base = "post"
+	}
+
+	// Slugs shaped like public IDs would be resolved as IDs
+	if uid.IsValid(base) {
+		base = "post-" + base
+	}
+
+	candidate := base
+	for i := 2; i <= maxSlugAttempts; i++ {
+		taken, err := s.repo.SlugTaken(ctx, candidate, postID)
+		if err != nil {
+			s.logger.Error("failed to check slug", "slug", candidate, "error", err)
+			return "", fmt.Errorf("check slug: %w", err)
+		}
+		if !taken && !reserved[candidate] {
+			return candidate, nil
+		}
+
+		suffix := "-" + strconv.Itoa(i)
+		if len(base)+len(suffix) > slug.MaxLength {
+			candidate = strings.TrimRight(base[:slug.MaxLength-len(suffix)], "-") + suffix
+		} else {
+			candidate = base + suffix
+		}
+	}
+
+	return "", fmt.Errorf("%w: no free slug for %q", repository.ErrAlreadyExists, name)
+}
+
+// newRevision returns a snapshot of post, saved by author
+func newRevision(post *domain.Post, author string) *domain.PostRevision {
+	rev := &domain.PostRevision{
+		PostID:      post.ID,
+		Author:      author,
+		Name:        post.Name,
+		Description: post.Description,
+		Format:      post.Format,
+		Tags:        make([]domain.RevisionTag, 0, len(post.Tags)),
+	}
+	for _, tag := range post.Tags {
+		rev.Tags = append(rev.Tags, domain.RevisionTag{Name: tag.Name, Description: tag.Description})
+	}
+	return rev
+}
+
+// revisionTagLines renders tags one per line for diffing
+func revisionTagLines(tags []domain.RevisionTag) []string {
+	lines := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag.Description == "" {
+			lines = append(lines, tag.Name)
+			continue
+		}
+		lines = append(lines, tag.Name+": "+tag.Description)
+	}
+	return lines
+}
+
+// checkFormat reports an error for unknown description formats; an empty
+// format stands for the default
+func checkFormat(format string) error {
+	switch format {
+	case "", domain.FormatPlain, domain.FormatMarkdown, domain.FormatHTML:
+		return nil
+	}
+	return fmt.Errorf("%w: format must be plain, markdown or html", repository.ErrInvalidInput)
+}
+
+// renderDescription caches the post's description rendered to sanitized
+// HTML, defaulting to the plain format
+func renderDescription(post *domain.Post) error {
+	if err := checkFormat(post.Format); err != nil {
+		return err
+	}
+
+	if post.Format == "" {
+		post.Format = domain.FormatPlain
+	}
+	post.DescriptionHTML = renderMarkup(post.Format, post.Description)
 	return nil
 }
 
+// renderMarkup renders src written in a checked format to sanitized HTML
+func renderMarkup(format, src string) string {
+	switch format {
+	case domain.FormatMarkdown:
+		return markup.Sanitize(markup.Markdown(src))
+	case domain.FormatHTML:
+		return markup.Sanitize(src)
+	default:
+		return markup.Text(src)
+	}
+}
@@ -1,9 +1,15 @@
 package service_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/yakuter/ugin/internal/domain"
 	"github.com/yakuter/ugin/internal/repository"
@@ -16,7 +22,22 @@ type mockPostRepository struct {
 	listFunc    func(ctx context.Context, filter repository.ListFilter) ([]*domain.Post, *repository.ListResult, error)
 	createFunc  func(ctx context.Context, post *domain.Post) error
 	updateFunc  func(ctx context.Context, post *domain.Post) error
-	deleteFunc  func(ctx context.Context, id string) error
+	deleteFunc  func(ctx context.Context, id uint, version uint) error
+	restoreFunc func(ctx context.Context, id string) error
+	batchFunc   func(ctx context.Context, batch *repository.PostBatch) error
+
+	getDeletedFunc  func(ctx context.Context, id string) (*domain.Post, error)
+	listDeletedFunc func(ctx context.Context, filter repository.ListFilter) ([]*domain.Post, *repository.ListResult, error)
+
+	slugTakenFunc func(ctx context.Context, slug string, postID uint) (bool, error)
+
+	// posts are returned by Stream
+	posts []*domain.Post
+	// revisions, contributors and queue receive the records written
+	// along with batches
+	revisions    *mockPostRevisionRepository
+	contributors *mockContributorRepository
+	queue        *mockModerationRepository
 }
 
 func (m *mockPostRepository) GetByID(ctx context.Context, id string) (*domain.Post, error) {
@@ -26,6 +47,33 @@ func (m *mockPostRepository) GetByID(ctx context.Context, id string) (*domain.Po
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockPostRepository) GetByPreviousSlug(ctx context.Context, slug string) (*domain.Post, error) {
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockPostRepository) SlugTaken(ctx context.Context, slug string, postID uint) (bool, error) {
+	if m.slugTakenFunc != nil {
+		return m.slugTakenFunc(ctx, slug, postID)
+	}
+	return false, nil
+}
+
+func (m *mockPostRepository) ListWithoutSlug(ctx context.Context, limit int) ([]*domain.Post, error) {
+	return nil, nil
+}
+
+func (m *mockPostRepository) ListUnrendered(ctx context.Context, limit int) ([]*domain.Post, error) {
+	return nil, nil
+}
+
+func (m *mockPostRepository) SetDescriptionHTML(ctx context.Context, id uint, html string) error {
+	return nil
+}
+
+func (m *mockPostRepository) SetSlug(ctx context.Context, id uint, slug string) error {
+	return errors.New("not implemented")
+}
+
 func (m *mockPostRepository) List(ctx context.Context, filter repository.ListFilter) ([]*domain.Post, *repository.ListResult, error) {
 	if m.listFunc != nil {
 		return m.listFunc(ctx, filter)
@@ -33,6 +81,43 @@ func (m *mockPostRepository) List(ctx context.Context, filter repository.ListFil
 	return nil, nil, errors.New("not implemented")
 }
 
+func (m *mockPostRepository) Archive(ctx context.Context, status string) ([]*domain.ArchiveMonth, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockPostRepository) Stream(ctx context.Context, filter repository.ListFilter, fn func(*domain.Post) error) error {
+	for _, post := range m.posts {
+		if filter.Status != "" && post.Status != filter.Status {
+			continue
+		}
+		if filter.Contributor != "" && !m.contributes(post.ID, filter.Contributor) &&
+			!(filter.IncludePublished && post.Status == domain.PostPublished) {
+			continue
+		}
+		if err := fn(post); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// contributes reports whether the user with email has a role on the post
+func (m *mockPostRepository) contributes(postID uint, email string) bool {
+	if m.contributors == nil {
+		return false
+	}
+	for _, c := range m.contributors.contributors {
+		if c.PostID == postID && strings.EqualFold(c.Email, email) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mockPostRepository) ListChangedSince(ctx context.Context, since time.Time, afterID uint, limit int) ([]*domain.Post, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (m *mockPostRepository) Create(ctx context.Context, post *domain.Post) error {
 	if m.createFunc != nil {
 		return m.createFunc(ctx, post)
@@ -47,13 +132,244 @@ func (m *mockPostRepository) Update(ctx context.Context, post *domain.Post) erro
 	return errors.New("not implemented")
 }
 
-func (m *mockPostRepository) Delete(ctx context.Context, id string) error {
+func (m *mockPostRepository) Delete(ctx context.Context, id uint, version uint) error {
 	if m.deleteFunc != nil {
-		return m.deleteFunc(ctx, id)
+		return m.deleteFunc(ctx, id, version)
 	}
 	return errors.New("not implemented")
 }
 
+func (m *mockPostRepository) ApplyBatch(ctx context.Context, batch *repository.PostBatch) error {
+	if m.batchFunc != nil {
+		return m.batchFunc(ctx, batch)
+	}
+
+	// Without a batch func each write goes through the single post funcs
+	for _, post := range batch.Create {
+		if err := m.Create(ctx, post); err != nil {
+			return &repository.BatchError{Post: post, Err: err}
+		}
+	}
+	for _, post := range batch.Update {
+		if err := m.Update(ctx, post); err != nil {
+			return &repository.BatchError{Post: post, Err: err}
+		}
+	}
+	for _, post := range batch.Delete {
+		if err := m.Delete(ctx, post.ID, post.Version); err != nil {
+			return &repository.BatchError{Post: post, Err: err}
+		}
+	}
+
+	for post, records := range batch.Records {
+		if m.revisions != nil {
+			if records.Baseline != nil && len(m.revisions.forPost(post.ID)) == 0 {
+				records.Baseline.PostID = post.ID
+				m.revisions.Create(ctx, records.Baseline)
+			}
+			if records.Revision != nil {
+				records.Revision.PostID = post.ID
+				m.revisions.Create(ctx, records.Revision)
+			}
+		}
+		if m.contributors != nil && records.Owner != "" {
+			m.contributors.Add(ctx, &domain.PostContributor{PostID: post.ID, Role: domain.RoleOwner}, records.Owner)
+		}
+		if m.queue != nil && records.Hold != nil {
+			records.Hold.TargetID = post.ID
+			records.Hold.TargetPublicID = post.PublicID
+			m.queue.Hold(ctx, records.Hold)
+		}
+	}
+	return nil
+}
+
+func (m *mockPostRepository) ListDeleted(ctx context.Context, filter repository.ListFilter) ([]*domain.Post, *repository.ListResult, error) {
+	if m.listDeletedFunc != nil {
+		return m.listDeletedFunc(ctx, filter)
+	}
+	return nil, nil, errors.New("not implemented")
+}
+
+func (m *mockPostRepository) GetDeleted(ctx context.Context, id string) (*domain.Post, error) {
+	if m.getDeletedFunc != nil {
+		return m.getDeletedFunc(ctx, id)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockPostRepository) Restore(ctx context.Context, id string) error {
+	if m.restoreFunc != nil {
+		return m.restoreFunc(ctx, id)
+	}
+	return errors.New("not implemented")
+}
+
+func (m *mockPostRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+// Mock revision repository
+type mockPostRevisionRepository struct {
+	revisions []*domain.PostRevision
+}
+
+func (m *mockPostRevisionRepository) Create(ctx context.Context, rev *domain.PostRevision) error {
+	rev.Revision = len(m.revisions) + 1
+	m.revisions = append(m.revisions, rev)
+	return nil
+}
+
+func (m *mockPostRevisionRepository) Get(ctx context.Context, postID uint, revision int) (*domain.PostRevision, error) {
+	for _, rev := range m.revisions {
+		if rev.PostID == postID && rev.Revision == revision {
+			return rev, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockPostRevisionRepository) ListByPostID(ctx context.Context, postID uint) ([]*domain.PostRevision, error) {
+	return m.revisions, nil
+}
+
+func (m *mockPostRevisionRepository) CountByPostID(ctx context.Context, postID uint) (int64, error) {
+	return int64(len(m.forPost(postID))), nil
+}
+
+func (m *mockPostRevisionRepository) forPost(postID uint) []*domain.PostRevision {
+	var revs []*domain.PostRevision
+	for _, rev := range m.revisions {
+		if rev.PostID == postID {
+			revs = append(revs, rev)
+		}
+	}
+	return revs
+}
+
+// Mock category repository
+type mockCategoryRepository struct {
+	categories []*domain.Category
+}
+
+func (m *mockCategoryRepository) GetByID(ctx context.Context, id string) (*domain.Category, error) {
+	for _, category := range m.categories {
+		if category.PublicID == id || category.Slug == id {
+			return category, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockCategoryRepository) List(ctx context.Context) ([]*domain.Category, error) {
+	return m.categories, nil
+}
+
+func (m *mockCategoryRepository) SlugTaken(ctx context.Context, slug string, categoryID uint) (bool, error) {
+	return false, nil
+}
+
+func (m *mockCategoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	return nil
+}
+
+func (m *mockCategoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	return nil
+}
+
+func (m *mockCategoryRepository) Move(ctx context.Context, id uint, parent *domain.Category) error {
+	return nil
+}
+
+func (m *mockCategoryRepository) Delete(ctx context.Context, id uint) error {
+	return nil
+}
+
+// Mock contributor repository
+type mockContributorRepository struct {
+	contributors []*domain.PostContributor
+}
+
+func (m *mockContributorRepository) List(ctx context.Context, postID uint) ([]*domain.PostContributor, error) {
+	var contributors []*domain.PostContributor
+	for _, c := range m.contributors {
+		if c.PostID == postID {
+			contributors = append(contributors, c)
+		}
+	}
+	return contributors, nil
+}
+
+func (m *mockContributorRepository) Add(ctx context.Context, contributor *domain.PostContributor, email string) error {
+	contributor.Email = email
+	m.contributors = append(m.contributors, contributor)
+	return nil
+}
+
+func (m *mockContributorRepository) Remove(ctx context.Context, postID uint, userID string) error {
+	return nil
+}
+
+// ownedBy returns contributors making email the owner of the posts with
+// the given IDs
+func ownedBy(email string, postIDs ...uint) *mockContributorRepository {
+	contributors := &mockContributorRepository{}
+	for _, id := range postIDs {
+		contributors.contributors = append(contributors.contributors, &domain.PostContributor{PostID: id, Email: email, Role: domain.RoleOwner})
+	}
+	return contributors
+}
+
+type mockReviewRepository struct {
+	reviews []*domain.PostReview
+}
+
+func (m *mockReviewRepository) List(ctx context.Context, postID uint) ([]*domain.PostReview, error) {
+	return m.reviews, nil
+}
+
+func (m *mockReviewRepository) Add(ctx context.Context, review *domain.PostReview) error {
+	m.reviews = append(m.reviews, review)
+	return nil
+}
+
+func (m *mockReviewRepository) Transition(ctx context.Context, post *domain.Post, review *domain.PostReview) error {
+	post.Version++
+	m.reviews = append(m.reviews, review)
+	return nil
+}
+
+type mockModerationRepository struct {
+	items []*domain.ModerationItem
+}
+
+func (m *mockModerationRepository) GetByID(ctx context.Context, id string) (*domain.ModerationItem, error) {
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockModerationRepository) List(ctx context.Context, status string, limit, offset int) ([]*domain.ModerationItem, int64, error) {
+	return m.items, int64(len(m.items)), nil
+}
+
+func (m *mockModerationRepository) GetPending(ctx context.Context, kind string, targetID uint) (*domain.ModerationItem, error) {
+	for _, item := range m.items {
+		if item.Kind == kind && item.TargetID == targetID && item.Status == domain.ModerationPending {
+			return item, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockModerationRepository) Hold(ctx context.Context, item *domain.ModerationItem) error {
+	item.Status = domain.ModerationPending
+	m.items = append(m.items, item)
+	return nil
+}
+
+func (m *mockModerationRepository) Resolve(ctx context.Context, item *domain.ModerationItem) error {
+	return nil
+}
+
 // Mock logger
 type mockLogger struct{}
 
@@ -63,9 +379,25 @@ func (m *mockLogger) Warn(msg string, keysAndValues ...interface{})  {}
 func (m *mockLogger) Error(msg string, keysAndValues ...interface{}) {}
 
 func TestPostService_GetByID(t *testing.T) {
+	draft := func() *mockPostRepository {
+		return &mockPostRepository{
+			getByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+				return &domain.Post{ID: 2, Name: "Draft", Status: domain.PostDraft}, nil
+			},
+		}
+	}
+	held := func() *mockPostRepository {
+		return &mockPostRepository{
+			getByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+				return &domain.Post{ID: 3, Name: "Held", Status: domain.PostHeld}, nil
+			},
+		}
+	}
+
 	tests := []struct {
 		name    string
 		id      string
+		actor   string
 		mock    func() *mockPostRepository
 		wantErr bool
 	}{
@@ -79,12 +411,53 @@ func TestPostService_GetByID(t *testing.T) {
 							ID:          1,
 							Name:        "Test Post",
 							Description: "Test Description",
+							Status:      domain.PostPublished,
 						}, nil
 					},
 				}
 			},
 			wantErr: false,
 		},
+		{
+			name:    "draft of a contributor",
+			id:      "2",
+			actor:   "owner@example.com",
+			mock:    draft,
+			wantErr: false,
+		},
+		{
+			name:    "draft of someone else",
+			id:      "2",
+			actor:   "stranger@example.com",
+			mock:    draft,
+			wantErr: true,
+		},
+		{
+			name:    "draft for anonymous readers",
+			id:      "2",
+			mock:    draft,
+			wantErr: true,
+		},
+		{
+			name:    "held post of a contributor",
+			id:      "3",
+			actor:   "owner@example.com",
+			mock:    held,
+			wantErr: false,
+		},
+		{
+			name:    "held post of someone else",
+			id:      "3",
+			actor:   "stranger@example.com",
+			mock:    held,
+			wantErr: true,
+		},
+		{
+			name:    "held post for anonymous readers",
+			id:      "3",
+			mock:    held,
+			wantErr: true,
+		},
 		{
 			name: "not found",
 			id:   "999",
@@ -107,8 +480,8 @@ func TestPostService_GetByID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			svc := service.NewPostService(tt.mock(), &mockLogger{})
-			post, err := svc.GetByID(context.Background(), tt.id)
+			svc := service.NewPostService(tt.mock(), &mockPostRevisionRepository{}, &mockCategoryRepository{}, ownedBy("owner@example.com", 2, 3), &mockReviewRepository{}, nil, nil, &mockLogger{})
+			post, err := svc.GetByID(service.WithActor(context.Background(), tt.actor), tt.id)
 
 			if tt.wantErr {
 				if err == nil {
@@ -131,10 +504,11 @@ func TestPostService_GetByID(t *testing.T) {
 
 func TestPostService_Create(t *testing.T) {
 	tests := []struct {
-		name    string
-		post    *domain.Post
-		mock    func() *mockPostRepository
-		wantErr bool
+		name      string
+		post      *domain.Post
+		anonymous bool
+		mock      func() *mockPostRepository
+		wantErr   bool
 	}{
 		{
 			name: "success",
@@ -166,12 +540,23 @@ func TestPostService_Create(t *testing.T) {
 			mock:    func() *mockPostRepository { return &mockPostRepository{} },
 			wantErr: true,
 		},
+		{
+			name:      "anonymous author",
+			post:      &domain.Post{Name: "New Post"},
+			anonymous: true,
+			mock:      func() *mockPostRepository { return &mockPostRepository{} },
+			wantErr:   true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			svc := service.NewPostService(tt.mock(), &mockLogger{})
-			err := svc.Create(context.Background(), tt.post)
+			svc := service.NewPostService(tt.mock(), &mockPostRevisionRepository{}, &mockCategoryRepository{}, &mockContributorRepository{}, &mockReviewRepository{}, nil, nil, &mockLogger{})
+			ctx := service.WithActor(context.Background(), "author@example.com")
+			if tt.anonymous {
+				ctx = context.Background()
+			}
+			err := svc.Create(ctx, tt.post)
 
 			if tt.wantErr {
 				if err == nil {
@@ -187,3 +572,758 @@ func TestPostService_Create(t *testing.T) {
 	}
 }
 
+func TestPostService_CreateRendersDescription(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "default plain", in: "a <b>", want: "<p>a &lt;b&gt;</p>\n"},
+		{name: "markdown", format: domain.FormatMarkdown, in: "**hi** [x](javascript:alert(1))", want: "<p><strong>hi</strong> x</p>\n"},
+		{name: "html", format: domain.FormatHTML, in: `<p onclick="x()">hi<script>alert(1)</script></p>`, want: "<p>hi</p>"},
+		{name: "unknown format", format: "rtf", in: "hi", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockPostRepository{
+				createFunc: func(ctx context.Context, post *domain.Post) error { return nil },
+			}
+			svc := service.NewPostService(repo, &mockPostRevisionRepository{}, &mockCategoryRepository{}, &mockContributorRepository{}, &mockReviewRepository{}, nil, nil, &mockLogger{})
+
+			post := &domain.Post{Name: "Post", Description: tt.in, Format: tt.format}
+			err := svc.Create(service.WithActor(context.Background(), "author@example.com"), post)
+			if tt.wantErr {
+				if !errors.Is(err, repository.ErrInvalidInput) {
+					t.Fatalf("expected ErrInvalidInput, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if post.DescriptionHTML != tt.want {
+				t.Errorf("description_html = %q, want %q", post.DescriptionHTML, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostService_CreateResolvesCategory(t *testing.T) {
+	categories := &mockCategoryRepository{categories: []*domain.Category{
+		{ID: 7, PublicID: "0190a5f2-7c21-7c3d-8a4b-5c6d7e8f9a0b", Slug: "programming", Path: "/7/"},
+	}}
+	repo := &mockPostRepository{
+		createFunc: func(ctx context.Context, post *domain.Post) error { return nil },
+	}
+	svc := service.NewPostService(repo, &mockPostRevisionRepository{}, categories, &mockContributorRepository{}, &mockReviewRepository{}, nil, nil, &mockLogger{})
+
+	post := &domain.Post{Name: "Post", CategoryPublicID: "programming"}
+	if err := svc.Create(service.WithActor(context.Background(), "author@example.com"), post); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if post.CategoryID == nil || *post.CategoryID != 7 {
+		t.Errorf("category_id = %v, want 7", post.CategoryID)
+	}
+	if post.CategoryPublicID != "0190a5f2-7c21-7c3d-8a4b-5c6d7e8f9a0b" {
+		t.Errorf("category public id = %q, want the category's public ID", post.CategoryPublicID)
+	}
+
+	err := svc.Create(service.WithActor(context.Background(), "author@example.com"), &domain.Post{Name: "Post", CategoryPublicID: "missing"})
+	if !errors.Is(err, repository.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for an unknown category, got %v", err)
+	}
+}
+
+func TestPostService_UpdateRecordsRevisions(t *testing.T) {
+	stored := &domain.Post{ID: 1, Name: "Original", Description: "first line", Status: domain.PostPublished}
+	repo := &mockPostRepository{
+		getByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			post := *stored
+			return &post, nil
+		},
+		updateFunc: func(ctx context.Context, post *domain.Post) error {
+			*stored = *post
+			return nil
+		},
+	}
+	revisions := &mockPostRevisionRepository{}
+	repo.revisions = revisions
+	contributors := &mockContributorRepository{contributors: []*domain.PostContributor{
+		{PostID: 1, Email: "editor@example.com", Role: domain.RoleEditor},
+	}}
+	svc := service.NewPostService(repo, revisions, &mockCategoryRepository{}, contributors, &mockReviewRepository{}, nil, nil, &mockLogger{})
+
+	ctx := service.WithActor(context.Background(), "editor@example.com")
+	if err := svc.Update(ctx, "1", &domain.Post{Name: "Edited", Description: "second line"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The pre-existing state is recorded as a baseline before the update
+	if len(revisions.revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revisions.revisions))
+	}
+	if got := revisions.revisions[0]; got.Name != "Original" || got.Author != "" {
+		t.Errorf("unexpected baseline revision: %+v", got)
+	}
+	if got := revisions.revisions[1]; got.Name != "Edited" || got.Author != "editor@example.com" {
+		t.Errorf("unexpected update revision: %+v", got)
+	}
+
+	d, err := svc.DiffRevisions(ctx, "1", 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(d.Description) != 2 {
+		t.Errorf("expected a delete and an insert, got %v", d.Description)
+	}
+
+	// The history names its authors and is kept from other users
+	stranger := service.WithActor(context.Background(), "bob@example.com")
+	if _, err := svc.ListRevisions(stranger, "1"); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("expected %v listing revisions, got %v", service.ErrForbidden, err)
+	}
+	if _, err := svc.DiffRevisions(stranger, "1", 1, 2); !errors.Is(err, service.ErrForbidden) {
+		t.Errorf("expected %v diffing revisions, got %v", service.ErrForbidden, err)
+	}
+
+	post, err := svc.RestoreRevision(ctx, "1", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if post.Name != "Original" {
+		t.Errorf("expected restored name %q, got %q", "Original", post.Name)
+	}
+	if len(revisions.revisions) != 3 {
+		t.Errorf("expected restore to record a revision, got %d revisions", len(revisions.revisions))
+	}
+}
+
+func TestPostService_UpdateVersionConflict(t *testing.T) {
+	repo := &mockPostRepository{
+		getByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			return &domain.Post{ID: 1, Name: "Current", Version: 3}, nil
+		},
+		updateFunc: func(ctx context.Context, post *domain.Post) error {
+			t.Error("update must not reach the repository on a version mismatch")
+			return nil
+		},
+	}
+	svc := service.NewPostService(repo, &mockPostRevisionRepository{}, &mockCategoryRepository{}, ownedBy("owner@example.com", 1), &mockReviewRepository{}, nil, nil, &mockLogger{})
+
+	ctx := service.WithActor(context.Background(), "owner@example.com")
+	err := svc.Update(ctx, "1", &domain.Post{Name: "Stale", Version: 2})
+	if !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+}
+
+func TestPostService_PublishesEvents(t *testing.T) {
+	stored := &domain.Post{ID: 1, Name: "Original", Version: 1}
+	repo := &mockPostRepository{
+		getByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			post := *stored
+			return &post, nil
+		},
+		createFunc: func(ctx context.Context, post *domain.Post) error {
+			post.ID = 2
+			return nil
+		},
+		updateFunc: func(ctx context.Context, post *domain.Post) error {
+			*stored = *post
+			return nil
+		},
+		deleteFunc: func(ctx context.Context, id uint, version uint) error { return nil },
+	}
+	svc := service.NewPostService(repo, &mockPostRevisionRepository{}, &mockCategoryRepository{}, ownedBy("owner@example.com", 1), &mockReviewRepository{}, nil, nil, &mockLogger{})
+
+	var events []string
+	svc.Subscribe(func(ctx context.Context, event service.PostEvent) {
+		events = append(events, fmt.Sprintf("%s %d %s", event.Type, event.Post.ID, event.Post.Name))
+	})
+
+	ctx := service.WithActor(context.Background(), "owner@example.com")
+	if err := svc.Create(ctx, &domain.Post{Name: "New"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.Update(ctx, "1", &domain.Post{Name: "Edited"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Failed writes are not reported
+	if err := svc.Update(ctx, "1", &domain.Post{Name: "Stale", Version: 5}); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if err := svc.Delete(ctx, "1", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"post.created 2 New", "post.updated 1 Edited", "post.deleted 1 Edited"}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+}
+
+func TestPostService_CreateSlug(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		taken []string
+		want  string
+	}{
+		{
+			name:  "transliterated",
+			title: "Çalışma Notları",
+			want:  "calisma-notlari",
+		},
+		{
+			name:  "collision suffix",
+			title: "Hello World",
+			taken: []string{"hello-world", "hello-world-2"},
+			want:  "hello-world-3",
+		},
+		{
+			name:  "public ID lookalike",
+			title: "0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e",
+			want:  "post-0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e",
+		},
+		{
+			name:  "nothing to transliterate",
+			title: "🚀",
+			want:  "post",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockPostRepository{
+				createFunc: func(ctx context.Context, post *domain.Post) error {
+					post.ID = 1
+					return nil
+				},
+				slugTakenFunc: func(ctx context.Context, slug string, postID uint) (bool, error) {
+					for _, taken := range tt.taken {
+						if slug == taken {
+							return true, nil
+						}
+					}
+					return false, nil
+				},
+			}
+			svc := service.NewPostService(repo, &mockPostRevisionRepository{}, &mockCategoryRepository{}, &mockContributorRepository{}, &mockReviewRepository{}, nil, nil, &mockLogger{})
+
+			post := &domain.Post{Name: tt.title}
+			if err := svc.Create(service.WithActor(context.Background(), "author@example.com"), post); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if post.Slug != tt.want {
+				t.Errorf("expected slug %q, got %q", tt.want, post.Slug)
+			}
+		})
+	}
+}
+
+func TestPostService_BulkAtomic(t *testing.T) {
+	existing := map[string]*domain.Post{
+		"first":  {ID: 1, PublicID: "first", Name: "First", Slug: "first", Version: 2},
+		"second": {ID: 2, PublicID: "second", Name: "Second", Slug: "second", Version: 1},
+	}
+	repo := &mockPostRepository{
+		getByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			if post, ok := existing[id]; ok {
+				copied := *post
+				return &copied, nil
+			}
+			return nil, repository.ErrNotFound
+		},
+	}
+
+	tests := []struct {
+		name      string
+		ops       []domain.BulkOperation
+		wantErr   error
+		wantIndex int
+	}{
+		{
+			name: "all valid",
+			ops: []domain.BulkOperation{
+				{Op: domain.BulkCreate, Post: &domain.Post{Name: "Hello"}},
+				{Op: domain.BulkCreate, Post: &domain.Post{Name: "Hello"}},
+				{Op: domain.BulkUpdate, ID: "first", Version: 2, Post: &domain.Post{Name: "First"}},
+				{Op: domain.BulkDelete, ID: "second"},
+			},
+		},
+		{
+			name: "missing post",
+			ops: []domain.BulkOperation{
+				{Op: domain.BulkCreate, Post: &domain.Post{Name: "Hello"}},
+				{Op: domain.BulkDelete, ID: "missing"},
+			},
+			wantErr:   repository.ErrNotFound,
+			wantIndex: 1,
+		},
+		{
+			name: "stale version",
+			ops: []domain.BulkOperation{
+				{Op: domain.BulkUpdate, ID: "first", Version: 1, Post: &domain.Post{Name: "First"}},
+			},
+			wantErr:   repository.ErrConflict,
+			wantIndex: 0,
+		},
+		{
+			name: "same post twice",
+			ops: []domain.BulkOperation{
+				{Op: domain.BulkUpdate, ID: "first", Post: &domain.Post{Name: "First"}},
+				{Op: domain.BulkDelete, ID: "first"},
+			},
+			wantErr:   repository.ErrInvalidInput,
+			wantIndex: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var applied *repository.PostBatch
+			repo.batchFunc = func(ctx context.Context, batch *repository.PostBatch) error {
+				applied = batch
+				return nil
+			}
+			svc := service.NewPostService(repo, &mockPostRevisionRepository{}, &mockCategoryRepository{}, ownedBy("owner@example.com", 1, 2), &mockReviewRepository{}, nil, nil, &mockLogger{})
+
+			ctx := service.WithActor(context.Background(), "owner@example.com")
+			results, err := svc.Bulk(ctx, tt.ops, true)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(applied.Create) != 2 || len(applied.Update) != 1 || len(applied.Delete) != 1 {
+					t.Fatalf("unexpected batch: %+v", applied)
+				}
+				if applied.Create[0].Slug == applied.Create[1].Slug {
+					t.Errorf("expected distinct slugs, both got %q", applied.Create[0].Slug)
+				}
+				// Revisions are written in the batch's transaction
+				update := applied.Records[applied.Update[0]]
+				if applied.Records[applied.Create[0]].Revision == nil || update.Baseline == nil || update.Revision == nil {
+					t.Errorf("expected revisions with the batch, got %+v", applied.Records)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if applied != nil {
+				t.Error("batch must not reach the repository when validation fails")
+			}
+			for i, result := range results {
+				if i == tt.wantIndex {
+					if !errors.Is(result.Err, tt.wantErr) {
+						t.Errorf("expected %v at index %d, got %v", tt.wantErr, i, result.Err)
+					}
+				} else if !errors.Is(result.Err, service.ErrBulkAborted) {
+					t.Errorf("expected index %d to be aborted, got %v", i, result.Err)
+				}
+			}
+		})
+	}
+}
+
+func TestPostService_Import(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		input     string
+		wantNames []string
+		wantLines []int
+	}{
+		{
+			name:      "ndjson",
+			format:    domain.FileFormatNDJSON,
+			input:     "{\"name\":\"One\",\"tags\":[{\"name\":\"go\"}]}\n\n{\"name\":\"\"}\n{\"name\":\"Two\"}\n",
+			wantNames: []string{"One", "Two"},
+			wantLines: []int{3},
+		},
+		{
+			name:      "csv",
+			format:    domain.FileFormatCSV,
+			input:     "name,tags\nOne,\"go, api\"\nTwo,\"[{\"\"name\"\":\"\"\"\"}]\"\nThree,\n",
+			wantNames: []string{"One", "Three"},
+			wantLines: []int{3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created []string
+			repo := &mockPostRepository{
+				createFunc: func(ctx context.Context, post *domain.Post) error {
+					created = append(created, post.Name)
+					return nil
+				},
+			}
+			svc := service.NewPostService(repo, &mockPostRevisionRepository{}, &mockCategoryRepository{}, &mockContributorRepository{}, &mockReviewRepository{}, nil, nil, &mockLogger{})
+
+			result, err := svc.Import(service.WithActor(context.Background(), "author@example.com"), strings.NewReader(tt.input), tt.format, false)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(created, ",") != strings.Join(tt.wantNames, ",") {
+				t.Errorf("expected %v to be created, got %v", tt.wantNames, created)
+			}
+			if result.Imported != len(tt.wantNames) || result.Failed != len(tt.wantLines) {
+				t.Errorf("unexpected counts: %+v", result)
+			}
+			for i, line := range tt.wantLines {
+				if i >= len(result.Errors) || result.Errors[i].Line != line {
+					t.Errorf("expected an error on line %d, got %+v", line, result.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestPostService_Export(t *testing.T) {
+	contributors := ownedBy("ann@example.com", 2, 3)
+	repo := &mockPostRepository{contributors: contributors, posts: []*domain.Post{
+		{ID: 1, Name: "Published", Status: domain.PostPublished},
+		{ID: 2, Name: "Draft", Status: domain.PostDraft},
+		{ID: 3, Name: "Held", Status: domain.PostHeld},
+	}}
+	svc := service.NewPostService(repo, &mockPostRevisionRepository{}, &mockCategoryRepository{}, contributors, &mockReviewRepository{}, nil, nil, &mockLogger{})
+	signedIn := service.WithActor(context.Background(), "ann@example.com")
+	stranger := service.WithActor(context.Background(), "bob@example.com")
+	admin := service.WithAdmin(stranger)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		status    string
+		wantNames []string
+		wantErr   error
+	}{
+		{name: "published by default", ctx: context.Background(), wantNames: []string{"Published"}},
+		{name: "held posts for anonymous readers", ctx: context.Background(), status: domain.PostHeld, wantErr: service.ErrForbidden},
+		{name: "all posts for anonymous readers", ctx: context.Background(), status: "all", wantErr: service.ErrForbidden},
+		{name: "held posts when signed in", ctx: stranger, status: domain.PostHeld, wantNames: nil},
+		{name: "held posts of their contributors", ctx: signedIn, status: domain.PostHeld, wantNames: []string{"Held"}},
+		{name: "held posts for admins", ctx: admin, status: domain.PostHeld, wantNames: []string{"Held"}},
+		{name: "all posts when signed in", ctx: signedIn, status: "all", wantNames: []string{"Published", "Draft", "Held"}},
+		{name: "drafts of other users", ctx: stranger, status: domain.PostDraft, wantNames: nil},
+		{name: "all posts of other users", ctx: stranger, status: "all", wantNames: []string{"Published"}},
+		{name: "all posts for admins", ctx: admin, status: "all", wantNames: []string{"Published", "Draft", "Held"}},
+		{name: "unknown status", ctx: signedIn, status: "hidden", wantErr: repository.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := svc.Export(tt.ctx, &buf, domain.FileFormatNDJSON, tt.status)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			var names []string
+			dec := json.NewDecoder(&buf)
+			for dec.More() {
+				var post domain.Post
+				if err := dec.Decode(&post); err != nil {
+					t.Fatal(err)
+				}
+				names = append(names, post.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.wantNames, ",") {
+				t.Errorf("expected %v, got %v", tt.wantNames, names)
+			}
+		})
+	}
+}
+
+func TestPostService_ContributorRights(t *testing.T) {
+	// Post 2 has no contributors
+	getPost := func(ctx context.Context, id string) (*domain.Post, error) {
+		postID, _ := strconv.Atoi(id)
+		return &domain.Post{ID: uint(postID), PublicID: id, Name: "Shared", Version: 1}, nil
+	}
+	repo := &mockPostRepository{
+		getByIDFunc:    getPost,
+		getDeletedFunc: getPost,
+		updateFunc:     func(ctx context.Context, post *domain.Post) error { return nil },
+		deleteFunc:     func(ctx context.Context, id uint, version uint) error { return nil },
+		restoreFunc:    func(ctx context.Context, id string) error { return nil },
+	}
+	contributors := &mockContributorRepository{contributors: []*domain.PostContributor{
+		{PostID: 1, Email: "owner@example.com", Role: domain.RoleOwner},
+		{PostID: 1, Email: "editor@example.com", Role: domain.RoleEditor},
+		{PostID: 1, Email: "reviewer@example.com", Role: domain.RoleReviewer},
+	}}
+	svc := service.NewPostService(repo, &mockPostRevisionRepository{}, &mockCategoryRepository{}, contributors, &mockReviewRepository{}, nil, nil, &mockLogger{})
+
+	tests := []struct {
+		name        string
+		id          string
+		actor       string
+		admin       bool
+		wantUpdate  error
+		wantDelete  error
+		wantRestore error
+	}{
+		{name: "owner", id: "1", actor: "owner@example.com"},
+		{name: "editor", id: "1", actor: "editor@example.com", wantDelete: service.ErrForbidden, wantRestore: service.ErrForbidden},
+		{name: "reviewer", id: "1", actor: "reviewer@example.com", wantUpdate: service.ErrForbidden, wantDelete: service.ErrForbidden, wantRestore: service.ErrForbidden},
+		{name: "stranger", id: "1", actor: "stranger@example.com", wantUpdate: service.ErrForbidden, wantDelete: service.ErrForbidden, wantRestore: service.ErrForbidden},
+		{name: "anonymous", id: "1", actor: "", wantUpdate: service.ErrForbidden, wantDelete: service.ErrForbidden, wantRestore: service.ErrForbidden},
+		{name: "post without contributors", id: "2", actor: "owner@example.com", wantUpdate: service.ErrForbidden, wantDelete: service.ErrForbidden, wantRestore: service.ErrForbidden},
+		{name: "anonymous on post without contributors", id: "2", actor: "", wantUpdate: service.ErrForbidden, wantDelete: service.ErrForbidden, wantRestore: service.ErrForbidden},
+		{name: "admin", id: "1", actor: "admin@example.com", admin: true},
+		{name: "admin on post without contributors", id: "2", actor: "admin@example.com", admin: true},
+		{name: "anonymous admin", id: "2", actor: "", admin: true, wantUpdate: service.ErrForbidden, wantDelete: service.ErrForbidden, wantRestore: service.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := service.WithActor(context.Background(), tt.actor)
+			if tt.admin {
+				ctx = service.WithAdmin(ctx)
+			}
+			if err := svc.Update(ctx, tt.id, &domain.Post{Name: "Edited"}); !errors.Is(err, tt.wantUpdate) {
+				t.Errorf("Update: expected %v, got %v", tt.wantUpdate, err)
+			}
+			if err := svc.Delete(ctx, tt.id, 0); !errors.Is(err, tt.wantDelete) {
+				t.Errorf("Delete: expected %v, got %v", tt.wantDelete, err)
+			}
+			if _, err := svc.Restore(ctx, tt.id); !errors.Is(err, tt.wantRestore) {
+				t.Errorf("Restore: expected %v, got %v", tt.wantRestore, err)
+			}
+		})
+	}
+}
+
+func TestPostService_CreateAddsOwner(t *testing.T) {
+	repo := &mockPostRepository{
+		createFunc: func(ctx context.Context, post *domain.Post) error {
+			post.ID = 7
+			return nil
+		},
+	}
+	contributors := &mockContributorRepository{}
+	repo.contributors = contributors
+	svc := service.NewPostService(repo, &mockPostRevisionRepository{}, &mockCategoryRepository{}, contributors, &mockReviewRepository{}, nil, nil, &mockLogger{})
+
+	// Nobody could change a post without an owner
+	if err := svc.Create(context.Background(), &domain.Post{Name: "Anonymous"}); !errors.Is(err, service.ErrForbidden) {
+		t.Fatalf("expected %v, got %v", service.ErrForbidden, err)
+	}
+
+	ctx := service.WithActor(context.Background(), "author@example.com")
+	if err := svc.Create(ctx, &domain.Post{Name: "Signed"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(contributors.contributors) != 1 {
+		t.Fatalf("expected one owner, got %d contributors", len(contributors.contributors))
+	}
+	owner := contributors.contributors[0]
+	if owner.PostID != 7 || owner.Email != "author@example.com" || owner.Role != domain.RoleOwner {
+		t.Errorf("unexpected owner: %+v", owner)
+	}
+}
+
+func TestPostService_ReviewWorkflow(t *testing.T) {
+	stored := &domain.Post{ID: 1, Name: "Draft", Status: domain.PostDraft, Version: 1}
+	repo := &mockPostRepository{
+		getByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			post := *stored
+			return &post, nil
+		},
+	}
+	contributors := &mockContributorRepository{contributors: []*domain.PostContributor{
+		{PostID: 1, Email: "owner@example.com", Role: domain.RoleOwner},
+		{PostID: 1, Email: "editor@example.com", Role: domain.RoleEditor},
+		{PostID: 1, Email: "reviewer@example.com", Role: domain.RoleReviewer},
+	}}
+	reviews := &mockReviewRepository{}
+	svc := service.NewPostService(repo, &mockPostRevisionRepository{}, &mockCategoryRepository{}, contributors, reviews, nil, nil, &mockLogger{})
+
+	var events []service.PostEventType
+	svc.Subscribe(func(ctx context.Context, event service.PostEvent) {
+		events = append(events, event.Type)
+	})
+
+	as := func(actor string) context.Context {
+		return service.WithActor(context.Background(), actor)
+	}
+	step := func(name string, err error, post *domain.Post, want error) {
+		t.Helper()
+		if !errors.Is(err, want) {
+			t.Fatalf("%s: expected %v, got %v", name, want, err)
+		}
+		if err == nil {
+			stored = post
+		}
+	}
+
+	post, err := svc.Approve(as("reviewer@example.com"), "1", nil)
+	step("approve a draft", err, post, service.ErrForbidden)
+
+	post, err = svc.SubmitForReview(as("editor@example.com"), "1", &domain.SubmitReviewRequest{})
+	step("submit without reviewer", err, post, repository.ErrInvalidInput)
+	post, err = svc.SubmitForReview(as("editor@example.com"), "1", &domain.SubmitReviewRequest{Reviewer: "editor@example.com"})
+	step("submit to a non-reviewer", err, post, repository.ErrInvalidInput)
+	post, err = svc.SubmitForReview(as("reviewer@example.com"), "1", &domain.SubmitReviewRequest{Reviewer: "owner@example.com"})
+	step("submit as reviewer", err, post, service.ErrForbidden)
+	post, err = svc.SubmitForReview(as("editor@example.com"), "1", &domain.SubmitReviewRequest{Reviewer: "reviewer@example.com"})
+	step("submit", err, post, nil)
+
+	post, err = svc.Publish(as("owner@example.com"), "1", nil)
+	step("publish before approval", err, post, service.ErrInvalidTransition)
+	post, err = svc.Approve(as("owner@example.com"), "1", nil)
+	step("approve as someone else", err, post, service.ErrForbidden)
+	post, err = svc.RequestChanges(as("reviewer@example.com"), "1", nil)
+	step("request changes without comment", err, post, repository.ErrInvalidInput)
+	post, err = svc.RequestChanges(as("reviewer@example.com"), "1", &domain.ReviewRequest{Comment: "Needs an example"})
+	step("request changes", err, post, nil)
+
+	post, err = svc.SubmitForReview(as("editor@example.com"), "1", nil)
+	step("resubmit", err, post, nil)
+	post, err = svc.Approve(as("reviewer@example.com"), "1", &domain.ReviewRequest{Comment: "Good"})
+	step("approve", err, post, nil)
+	post, err = svc.Publish(as("editor@example.com"), "1", nil)
+	step("publish as editor", err, post, service.ErrForbidden)
+	post, err = svc.Publish(as("owner@example.com"), "1", nil)
+	step("publish", err, post, nil)
+
+	if stored.Status != domain.PostPublished || stored.Reviewer != "reviewer@example.com" {
+		t.Errorf("unexpected post after review: status %q, reviewer %q", stored.Status, stored.Reviewer)
+	}
+
+	wantEvents := []service.PostEventType{service.PostSubmitted, service.PostChangesRequested, service.PostSubmitted, service.PostApproved, service.PostPublished}
+	if fmt.Sprint(events) != fmt.Sprint(wantEvents) {
+		t.Errorf("expected events %v, got %v", wantEvents, events)
+	}
+	if len(reviews.reviews) != len(wantEvents) {
+		t.Errorf("expected %d review steps, got %d", len(wantEvents), len(reviews.reviews))
+	}
+}
+
+func TestPostService_CreateHeldForModeration(t *testing.T) {
+	repo := &mockPostRepository{
+		createFunc: func(ctx context.Context, post *domain.Post) error {
+			post.ID = 3
+			post.PublicID = "held-post"
+			return nil
+		},
+	}
+	moderator := service.NewFilterModerator(&service.ModerationConfig{Words: []string{"casino"}, MaxLinks: 1})
+	queue := &mockModerationRepository{}
+	repo.queue = queue
+	svc := service.NewPostService(repo, &mockPostRevisionRepository{}, &mockCategoryRepository{}, &mockContributorRepository{}, &mockReviewRepository{}, moderator, queue, &mockLogger{})
+
+	clean := &domain.Post{Name: "Release notes", Description: "See https://example.com for details"}
+	if err := svc.Create(service.WithActor(context.Background(), "author@example.com"), clean); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if clean.Status != domain.PostPublished || len(queue.items) != 0 {
+		t.Fatalf("expected a clean post to be published, got status %q and %d queued", clean.Status, len(queue.items))
+	}
+
+	spam := &domain.Post{Name: "Best Casino bonuses", Description: "http://a.example http://b.example"}
+	if err := svc.Create(service.WithActor(context.Background(), "author@example.com"), spam); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spam.Status != domain.PostHeld {
+		t.Errorf("expected flagged post to be held, got %q", spam.Status)
+	}
+	if len(queue.items) != 1 {
+		t.Fatalf("expected one queued item, got %d", len(queue.items))
+	}
+	item := queue.items[0]
+	if item.Kind != domain.ModerationPost || item.TargetID != 3 || item.TargetPublicID != "held-post" || item.ReleaseStatus != domain.PostPublished {
+		t.Errorf("unexpected queued item: %+v", item)
+	}
+	if len(item.Reasons) != 2 {
+		t.Errorf("expected word and link reasons, got %v", item.Reasons)
+	}
+}
+
+func TestPostService_ListByMonth(t *testing.T) {
+	var got repository.ListFilter
+	repo := &mockPostRepository{
+		listFunc: func(ctx context.Context, filter repository.ListFilter) ([]*domain.Post, *repository.ListResult, error) {
+			got = filter
+			return nil, &repository.ListResult{}, nil
+		},
+	}
+	svc := service.NewPostService(repo, &mockPostRevisionRepository{}, &mockCategoryRepository{}, &mockContributorRepository{}, &mockReviewRepository{}, nil, nil, &mockLogger{})
+
+	tests := []struct {
+		name        string
+		year, month int
+		wantErr     error
+	}{
+		{name: "month", year: 2023, month: 1},
+		{name: "whole year", year: 2023},
+		{name: "month out of range", year: 2023, month: 13, wantErr: repository.ErrInvalidInput},
+		{name: "negative year", year: -1, wantErr: repository.ErrInvalidInput},
+		{name: "month without year", month: 4, wantErr: repository.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = repository.ListFilter{}
+			_, _, err := svc.List(context.Background(), repository.ListFilter{Year: tt.year, Month: tt.month})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Year != tt.year || got.Month != tt.month || got.Status != domain.PostPublished {
+				t.Errorf("unexpected filter: %+v", got)
+			}
+		})
+	}
+}
+
+func TestPostService_ListTrash(t *testing.T) {
+	var got repository.ListFilter
+	repo := &mockPostRepository{
+		listDeletedFunc: func(ctx context.Context, filter repository.ListFilter) ([]*domain.Post, *repository.ListResult, error) {
+			got = filter
+			return nil, &repository.ListResult{}, nil
+		},
+	}
+	svc := service.NewPostService(repo, &mockPostRevisionRepository{}, &mockCategoryRepository{}, &mockContributorRepository{}, &mockReviewRepository{}, nil, nil, &mockLogger{})
+	ann := service.WithActor(context.Background(), "ann@example.com")
+
+	tests := []struct {
+		name            string
+		ctx             context.Context
+		wantContributor string
+		wantErr         error
+	}{
+		{name: "posts the user owns", ctx: ann, wantContributor: "ann@example.com"},
+		{name: "every post for admins", ctx: service.WithAdmin(ann)},
+		{name: "anonymous", ctx: context.Background(), wantErr: service.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = repository.ListFilter{}
+			// A contributor given by the caller is never trusted
+			_, _, err := svc.ListTrash(tt.ctx, repository.ListFilter{Contributor: "bob@example.com"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Contributor != tt.wantContributor {
+				t.Errorf("expected contributor %q, got %q", tt.wantContributor, got.Contributor)
+			}
+			if tt.wantContributor != "" && (len(got.Roles) != 1 || got.Roles[0] != domain.RoleOwner) {
+				t.Errorf("expected the owner role, got %v", got.Roles)
+			}
+		})
+	}
+}
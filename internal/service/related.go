@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/pkg/tfidf"
+)
+
+const (
+	// tagWeight and textWeight are the shares of shared tags and text
+	// similarity in the score of a related post
+	tagWeight  = 0.5
+	textWeight = 0.5
+	// maxRelated is the most related posts ranked and cached per post
+	maxRelated = 20
+)
+
+// relatedPost is what related post scoring keeps of a post
+type relatedPost struct {
+	publicID string
+	name     string
+	slug     string
+	// tags maps lowercase tag names to their names as given
+	tags map[string]string
+}
+
+type relatedService struct {
+	posts  repository.PostRepository
+	logger Logger
+
+	mu     sync.Mutex
+	loaded bool
+	text   *tfidf.Index
+	byID   map[uint]*relatedPost
+	tagged map[string]map[uint]bool
+	// cache holds the ranked related posts per post
+	cache map[uint][]*domain.RelatedPost
+}
+
+// NewRelatedService creates a new related post service. It reads every
+// post the first time related posts are asked for; after that PostChanged
+// must be subscribed to the post service to keep it up to date.
+func NewRelatedService(posts repository.PostRepository, logger Logger) RelatedService {
+	return &relatedService{
+		posts:  posts,
+		logger: logger,
+		text:   tfidf.New(),
+		byID:   make(map[uint]*relatedPost),
+		tagged: make(map[string]map[uint]bool),
+		cache:  make(map[uint][]*domain.RelatedPost),
+	}
+}
+
+func (s *relatedService) Related(ctx context.Context, postID string, limit int) ([]*domain.RelatedPost, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	if limit > maxRelated {
+		limit = maxRelated // Max limit
+	}
+
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+
+	related, ok := s.cache[post.ID]
+	if !ok {
+		related = s.rank(post.ID)
+		s.cache[post.ID] = related
+	}
+
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	return related, nil
+}
+
+func (s *relatedService) PostChanged(ctx context.Context, event PostEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Until loaded, the posts are read as they are once needed
+	if !s.loaded {
+		return
+	}
+
+	// Only published posts are recommended
+	if event.Type == PostDeleted || event.Post.Status != domain.PostPublished {
+		s.remove(event.Post.ID)
+	} else {
+		s.add(event.Post)
+	}
+
+	// Any post changes how common its terms and tags are, and so the
+	// scores of others, so no cached ranking is kept
+	s.cache = make(map[uint][]*domain.RelatedPost)
+}
+
+// load reads every post the first time it is called. s.mu must be held.
+func (s *relatedService) load(ctx context.Context) error {
+	if s.loaded {
+		return nil
+	}
+
+	err := s.posts.Stream(ctx, repository.ListFilter{Status: domain.PostPublished}, func(post *domain.Post) error {
+		s.add(post)
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("failed to load posts for related posts", "error", err)
+		return fmt.Errorf("load related posts: %w", err)
+	}
+
+	s.loaded = true
+	s.logger.Info("related posts loaded", "posts", len(s.byID))
+	return nil
+}
+
+// add indexes the post, replacing an earlier version. s.mu must be held.
+func (s *relatedService) add(post *domain.Post) {
+	s.remove(post.ID)
+
+	entry := &relatedPost{publicID: post.PublicID, name: post.Name, slug: post.Slug, tags: make(map[string]string)}
+	for _, tag := range post.Tags {
+		key := strings.ToLower(tag.Name)
+		entry.tags[key] = tag.Name
+		if s.tagged[key] == nil {
+			s.tagged[key] = make(map[uint]bool)
+		}
+		s.tagged[key][post.ID] = true
+	}
+
+	s.byID[post.ID] = entry
+	s.text.Add(docID(post.ID), post.Name+"\n"+post.Description)
+}
+
+// remove drops the post from the index. s.mu must be held.
+func (s *relatedService) remove(id uint) {
+	entry, ok := s.byID[id]
+	if !ok {
+		return
+	}
+
+	for key := range entry.tags {
+		delete(s.tagged[key], id)
+		if len(s.tagged[key]) == 0 {
+			delete(s.tagged, key)
+		}
+	}
+	delete(s.byID, id)
+	s.text.Remove(docID(id))
+}
+
+// rank scores every post sharing tags or terms with the post and returns
+// the best ones first. The tag share of a score is the Jaccard similarity
+// of both posts' tags. s.mu must be held.
+func (s *relatedService) rank(id uint) []*domain.RelatedPost {
+	entry, ok := s.byID[id]
+	if !ok {
+		return nil
+	}
+
+	scores := make(map[uint]float64)
+	for doc, score := range s.text.Scores(docID(id)) {
+		other, _ := strconv.ParseUint(doc, 10, 64)
+		scores[uint(other)] = textWeight * score
+	}
+
+	shared := make(map[uint][]string)
+	for key, name := range entry.tags {
+		for other := range s.tagged[key] {
+			if other != id {
+				shared[other] = append(shared[other], name)
+			}
+		}
+	}
+	for other, tags := range shared {
+		union := len(entry.tags) + len(s.byID[other].tags) - len(tags)
+		scores[other] += tagWeight * float64(len(tags)) / float64(union)
+	}
+
+	related := make([]*domain.RelatedPost, 0, len(scores))
+	for other, score := range scores {
+		post := s.byID[other]
+		tags := shared[other]
+		sort.Strings(tags)
+		related = append(related, &domain.RelatedPost{
+			PostID:     post.publicID,
+			Name:       post.name,
+			Slug:       post.slug,
+			Score:      score,
+			SharedTags: tags,
+		})
+	}
+	sort.Slice(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+		return related[i].PostID < related[j].PostID
+	})
+
+	if len(related) > maxRelated {
+		related = related[:maxRelated]
+	}
+	return related
+}
+
+func (s *relatedService) getPost(ctx context.Context, id string) (*domain.Post, error) {
+	if id == "" {
+		return nil, repository.ErrInvalidInput
+	}
+
+	post, err := s.posts.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get post", "id", id, "error", err)
+		return nil, fmt.Errorf("get post: %w", err)
+	}
+
+	// Unpublished posts are not recommended, nor shown to exist
+	if post.Status != domain.PostPublished {
+		return nil, repository.ErrNotFound
+	}
+
+	return post, nil
+}
+
+// docID is the text index document of a post
+func docID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
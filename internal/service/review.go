@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+)
+
+// PostStatusAll lists posts in every review status
+const PostStatusAll = "all"
+
+// ErrInvalidTransition is returned for a review step the post's status does not allow
+var ErrInvalidTransition = errors.New("review step not allowed in the post's status")
+
+// reviewStep describes a review action: the statuses it may be taken in,
+// the status it moves the post to, and who may take it
+type reviewStep struct {
+	from []string
+	// to is empty for steps that leave the status as it is
+	to string
+	// roles may take the step; with none, only the assigned reviewer may
+	roles []string
+	event PostEventType
+}
+
+// reviewSteps is the review workflow: drafts are submitted to a reviewer,
+// who approves them or requests changes, and approved posts are published
+var reviewSteps = map[string]reviewStep{
+	domain.ReviewSubmit: {
+		from:  []string{domain.PostDraft, domain.PostChangesRequested},
+		to:    domain.PostInReview,
+		roles: []string{domain.RoleOwner, domain.RoleEditor},
+		event: PostSubmitted,
+	},
+	domain.ReviewAssign: {
+		from:  []string{domain.PostDraft, domain.PostInReview, domain.PostChangesRequested},
+		roles: []string{domain.RoleOwner, domain.RoleEditor},
+		event: PostReviewerAssigned,
+	},
+	domain.ReviewApprove: {
+		from:  []string{domain.PostInReview},
+		to:    domain.PostApproved,
+		event: PostApproved,
+	},
+	domain.ReviewRequestChanges: {
+		from:  []string{domain.PostInReview},
+		to:    domain.PostChangesRequested,
+		event: PostChangesRequested,
+	},
+	domain.ReviewPublish: {
+		from:  []string{domain.PostApproved},
+		to:    domain.PostPublished,
+		roles: []string{domain.RoleOwner},
+		event: PostPublished,
+	},
+}
+
+func (s *postService) GetReview(ctx context.Context, id string) (*domain.Review, error) {
+	if ActorFromContext(ctx) == "" {
+		return nil, ErrForbidden
+	}
+
+	post, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, post, domain.RoleOwner, domain.RoleEditor, domain.RoleReviewer); err != nil {
+		return nil, err
+	}
+
+	history, err := s.reviews.List(ctx, post.ID)
+	if err != nil {
+		s.logger.Error("failed to list reviews", "id", id, "error", err)
+		return nil, fmt.Errorf("get review: %w", err)
+	}
+
+	return &domain.Review{Status: post.Status, Reviewer: post.Reviewer, History: history}, nil
+}
+
+func (s *postService) SubmitForReview(ctx context.Context, id string, req *domain.SubmitReviewRequest) (*domain.Post, error) {
+	if req == nil {
+		req = &domain.SubmitReviewRequest{}
+	}
+	return s.review(ctx, id, domain.ReviewSubmit, req.Reviewer, req.Comment)
+}
+
+func (s *postService) AssignReviewer(ctx context.Context, id string, req *domain.AssignReviewerRequest) (*domain.Post, error) {
+	if req == nil || strings.TrimSpace(req.Reviewer) == "" {
+		return nil, fmt.Errorf("%w: reviewer is required", repository.ErrInvalidInput)
+	}
+	return s.review(ctx, id, domain.ReviewAssign, req.Reviewer, req.Comment)
+}
+
+func (s *postService) Approve(ctx context.Context, id string, req *domain.ReviewRequest) (*domain.Post, error) {
+	return s.review(ctx, id, domain.ReviewApprove, "", reviewComment(req))
+}
+
+func (s *postService) RequestChanges(ctx context.Context, id string, req *domain.ReviewRequest) (*domain.Post, error) {
+	// The author needs to know what to change
+	if reviewComment(req) == "" {
+		return nil, fmt.Errorf("%w: comment is required", repository.ErrInvalidInput)
+	}
+	return s.review(ctx, id, domain.ReviewRequestChanges, "", reviewComment(req))
+}
+
+func (s *postService) Publish(ctx context.Context, id string, req *domain.ReviewRequest) (*domain.Post, error) {
+	return s.review(ctx, id, domain.ReviewPublish, "", reviewComment(req))
+}
+
+func (s *postService) CommentOnReview(ctx context.Context, id string, req *domain.ReviewRequest) (*domain.PostReview, error) {
+	comment := reviewComment(req)
+	if comment == "" {
+		return nil, fmt.Errorf("%w: comment is required", repository.ErrInvalidInput)
+	}
+
+	actor := ActorFromContext(ctx)
+	if actor == "" {
+		return nil, ErrForbidden
+	}
+
+	post, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Anyone with a role on the post may take part in its review
+	role, err := s.roleOf(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, ErrForbidden
+	}
+
+	review := &domain.PostReview{
+		PostID:   post.ID,
+		Author:   actor,
+		Action:   domain.ReviewComment,
+		Status:   post.Status,
+		Reviewer: post.Reviewer,
+		Comment:  comment,
+	}
+	if err := s.reviews.Add(ctx, review); err != nil {
+		s.logger.Error("failed to add review comment", "id", id, "error", err)
+		return nil, fmt.Errorf("comment on review: %w", err)
+	}
+
+	s.logger.Info("review comment added", "id", id, "by", actor)
+	s.publishReview(ctx, PostReviewCommented, post, review)
+	return review, nil
+}
+
+// review takes the review step action on the post, assigning reviewer
+// when one is given, and records it in the post's review history
+func (s *postService) review(ctx context.Context, id, action, reviewer, comment string) (*domain.Post, error) {
+	step := reviewSteps[action]
+
+	actor := ActorFromContext(ctx)
+	if actor == "" {
+		return nil, ErrForbidden
+	}
+
+	post, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	contributors, err := s.listContributors(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+	if !mayTakeStep(step, post, actorRole(ctx, contributors), actor) {
+		s.logger.Info("review step forbidden", "id", id, "action", action, "actor", actor)
+		return nil, ErrForbidden
+	}
+
+	if !containsStatus(step.from, post.Status) {
+		return nil, fmt.Errorf("%w: post is %s", ErrInvalidTransition, post.Status)
+	}
+
+	if reviewer = strings.TrimSpace(reviewer); reviewer != "" {
+		if err := checkReviewer(contributors, reviewer, actor); err != nil {
+			return nil, err
+		}
+		post.Reviewer = reviewer
+	}
+	if post.Reviewer == "" {
+		return nil, fmt.Errorf("%w: reviewer is required", repository.ErrInvalidInput)
+	}
+
+	if step.to != "" {
+		post.Status = step.to
+	}
+
+	review := &domain.PostReview{
+		Author:   actor,
+		Action:   action,
+		Status:   post.Status,
+		Reviewer: post.Reviewer,
+		Comment:  comment,
+	}
+	if err := s.reviews.Transition(ctx, post, review); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			s.logger.Info("post modified concurrently", "id", id)
+			return nil, err
+		}
+		s.logger.Error("failed to review post", "id", id, "action", action, "error", err)
+		return nil, fmt.Errorf("review post: %w", err)
+	}
+
+	s.logger.Info("post reviewed", "id", id, "action", action, "status", post.Status, "by", actor)
+	s.publishReview(ctx, step.event, post, review)
+	return post, nil
+}
+
+// initialStatus checks the status a post is created in. Posts are
+// published unless created as drafts, which need an author to own them.
+func initialStatus(ctx context.Context, post *domain.Post) error {
+	switch post.Status {
+	case "":
+		post.Status = domain.PostPublished
+	case domain.PostPublished:
+	case domain.PostDraft:
+		if ActorFromContext(ctx) == "" {
+			return fmt.Errorf("%w: drafts need a signed-in author", repository.ErrInvalidInput)
+		}
+	default:
+		return fmt.Errorf("%w: posts are created as %s or %s", repository.ErrInvalidInput, domain.PostDraft, domain.PostPublished)
+	}
+
+	post.Reviewer = ""
+	return nil
+}
+
+// withdrawApproval sends an approved post that is being changed back to
+// its reviewer, so only reviewed content gets published
+func withdrawApproval(post *domain.Post) {
+	if post.Status == domain.PostApproved {
+		post.Status = domain.PostInReview
+	}
+}
+
+// mayTakeStep reports whether the actor, with role on the post, may take step
+func mayTakeStep(step reviewStep, post *domain.Post, role, actor string) bool {
+	if role == "" {
+		return false
+	}
+	if len(step.roles) == 0 {
+		return strings.EqualFold(post.Reviewer, actor)
+	}
+	for _, allowed := range step.roles {
+		if role == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// checkReviewer makes sure the reviewer is one of the post's reviewers or
+// owners, and not the actor assigning them
+func checkReviewer(contributors []*domain.PostContributor, reviewer, actor string) error {
+	if strings.EqualFold(reviewer, actor) {
+		return fmt.Errorf("%w: posts cannot be reviewed by whoever submits them", repository.ErrInvalidInput)
+	}
+	for _, c := range contributors {
+		if strings.EqualFold(c.Email, reviewer) && (c.Role == domain.RoleReviewer || c.Role == domain.RoleOwner) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is not a reviewer of the post", repository.ErrInvalidInput, reviewer)
+}
+
+// statusFilter narrows filter to the posts the actor may list or export
+// in the status it asks for, published by default. Posts in other
+// statuses are only shown to their contributors and to admins.
+func statusFilter(ctx context.Context, filter *repository.ListFilter) error {
+	filter.Contributor, filter.Roles, filter.IncludePublished = "", nil, false
+
+	switch filter.Status {
+	case "", domain.PostPublished:
+		filter.Status = domain.PostPublished
+		return nil
+	case domain.PostDraft, domain.PostInReview, domain.PostChangesRequested, domain.PostApproved, domain.PostHeld, PostStatusAll:
+	default:
+		return fmt.Errorf("%w: unknown post status %q", repository.ErrInvalidInput, filter.Status)
+	}
+
+	actor := ActorFromContext(ctx)
+	if actor == "" {
+		return ErrForbidden
+	}
+	if IsAdmin(ctx) {
+		if filter.Status == PostStatusAll {
+			filter.Status = ""
+		}
+		return nil
+	}
+	filter.Contributor = actor
+	if filter.Status == PostStatusAll {
+		filter.Status, filter.IncludePublished = "", true
+	}
+	return nil
+}
+
+func containsStatus(statuses []string, status string) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func reviewComment(req *domain.ReviewRequest) string {
+	if req == nil {
+		return ""
+	}
+	return strings.TrimSpace(req.Comment)
+}
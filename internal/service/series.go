@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+)
+
+var (
+	// ErrSeriesNotFound is returned when no series has the given ID
+	ErrSeriesNotFound = fmt.Errorf("series %w", repository.ErrNotFound)
+	// ErrSeriesEntryNotFound is returned when the post is not in the series
+	ErrSeriesEntryNotFound = fmt.Errorf("series entry %w", repository.ErrNotFound)
+)
+
+type seriesService struct {
+	series       repository.SeriesRepository
+	posts        repository.PostRepository
+	contributors repository.ContributorRepository
+	logger       Logger
+}
+
+// NewSeriesService creates a new series service
+func NewSeriesService(series repository.SeriesRepository, posts repository.PostRepository, contributors repository.ContributorRepository, logger Logger) SeriesService {
+	return &seriesService{
+		series:       series,
+		posts:        posts,
+		contributors: contributors,
+		logger:       logger,
+	}
+}
+
+func (s *seriesService) List(ctx context.Context) ([]*domain.Series, error) {
+	series, err := s.series.List(ctx)
+	if err != nil {
+		s.logger.Error("failed to list series", "error", err)
+		return nil, fmt.Errorf("list series: %w", err)
+	}
+	return series, nil
+}
+
+func (s *seriesService) GetByID(ctx context.Context, id string) (*domain.Series, error) {
+	series, err := s.getSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withEntries(ctx, series)
+}
+
+func (s *seriesService) Create(ctx context.Context, req *domain.SeriesRequest) (*domain.Series, error) {
+	if req == nil {
+		return nil, repository.ErrInvalidInput
+	}
+	actor := ActorFromContext(ctx)
+	if actor == "" {
+		return nil, ErrForbidden
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", repository.ErrInvalidInput)
+	}
+
+	series := &domain.Series{Name: name, Description: req.Description, Owner: actor}
+	if err := s.series.Create(ctx, series); err != nil {
+		s.logger.Error("failed to create series", "error", err)
+		return nil, fmt.Errorf("create series: %w", err)
+	}
+
+	s.logger.Info("series created", "id", series.PublicID, "name", series.Name)
+	return series, nil
+}
+
+func (s *seriesService) Update(ctx context.Context, id string, req *domain.SeriesRequest) (*domain.Series, error) {
+	if req == nil {
+		return nil, repository.ErrInvalidInput
+	}
+	if ActorFromContext(ctx) == "" {
+		return nil, ErrForbidden
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", repository.ErrInvalidInput)
+	}
+
+	series, err := s.getOwnSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	series.Name = name
+	series.Description = req.Description
+	if err := s.series.Update(ctx, series); err != nil {
+		s.logger.Error("failed to update series", "id", id, "error", err)
+		return nil, fmt.Errorf("update series: %w", err)
+	}
+
+	s.logger.Info("series updated", "id", id)
+	return s.withEntries(ctx, series)
+}
+
+func (s *seriesService) Delete(ctx context.Context, id string) error {
+	if ActorFromContext(ctx) == "" {
+		return ErrForbidden
+	}
+
+	series, err := s.getOwnSeries(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.series.Delete(ctx, series.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrSeriesNotFound
+		}
+		s.logger.Error("failed to delete series", "id", id, "error", err)
+		return fmt.Errorf("delete series: %w", err)
+	}
+
+	s.logger.Info("series deleted", "id", id)
+	return nil
+}
+
+func (s *seriesService) AddPost(ctx context.Context, id string, req *domain.AddSeriesPostRequest) (*domain.Series, error) {
+	if req == nil || req.PostID == "" {
+		return nil, repository.ErrInvalidInput
+	}
+	if req.Position < 0 {
+		return nil, fmt.Errorf("%w: position must not be negative", repository.ErrInvalidInput)
+	}
+	if ActorFromContext(ctx) == "" {
+		return nil, ErrForbidden
+	}
+
+	series, err := s.getOwnSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := s.getPost(ctx, req.PostID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizePost(ctx, post); err != nil {
+		return nil, err
+	}
+
+	if err := s.series.AddPost(ctx, series.ID, post.ID, req.Position); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, err
+		}
+		s.logger.Error("failed to add post to series", "id", id, "post", req.PostID, "error", err)
+		return nil, fmt.Errorf("add post to series: %w", err)
+	}
+
+	s.logger.Info("post added to series", "id", id, "post", post.PublicID)
+	return s.withEntries(ctx, series)
+}
+
+func (s *seriesService) RemovePost(ctx context.Context, id, postID string) (*domain.Series, error) {
+	if postID == "" {
+		return nil, repository.ErrInvalidInput
+	}
+	if ActorFromContext(ctx) == "" {
+		return nil, ErrForbidden
+	}
+
+	series, err := s.getOwnSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.series.RemovePost(ctx, series.ID, post.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSeriesEntryNotFound
+		}
+		s.logger.Error("failed to remove post from series", "id", id, "post", postID, "error", err)
+		return nil, fmt.Errorf("remove post from series: %w", err)
+	}
+
+	s.logger.Info("post removed from series", "id", id, "post", post.PublicID)
+	return s.withEntries(ctx, series)
+}
+
+func (s *seriesService) Reorder(ctx context.Context, id string, req *domain.ReorderSeriesRequest) (*domain.Series, error) {
+	if req == nil {
+		return nil, repository.ErrInvalidInput
+	}
+	if ActorFromContext(ctx) == "" {
+		return nil, ErrForbidden
+	}
+
+	series, err := s.getOwnSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.entries(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+
+	// Posts may be listed by public ID or slug; the repository orders them by public ID
+	byKey := make(map[string]string, 2*len(entries))
+	for _, entry := range entries {
+		byKey[entry.PostID] = entry.PostID
+		byKey[entry.Slug] = entry.PostID
+	}
+	postIDs := make([]string, len(req.PostIDs))
+	for i, key := range req.PostIDs {
+		postID, ok := byKey[key]
+		if !ok {
+			postID, ok = byKey[strings.ToLower(key)]
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: post %q is not in the series", repository.ErrInvalidInput, key)
+		}
+		postIDs[i] = postID
+	}
+
+	if err := s.series.Reorder(ctx, series.ID, postIDs); err != nil {
+		if errors.Is(err, repository.ErrInvalidInput) {
+			return nil, err
+		}
+		s.logger.Error("failed to reorder series", "id", id, "error", err)
+		return nil, fmt.Errorf("reorder series: %w", err)
+	}
+
+	s.logger.Info("series reordered", "id", id)
+	return s.withEntries(ctx, series)
+}
+
+func (s *seriesService) getSeries(ctx context.Context, id string) (*domain.Series, error) {
+	if id == "" {
+		return nil, repository.ErrInvalidInput
+	}
+
+	series, err := s.series.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSeriesNotFound
+		}
+		s.logger.Error("failed to get series", "id", id, "error", err)
+		return nil, fmt.Errorf("get series: %w", err)
+	}
+
+	return series, nil
+}
+
+// getOwnSeries returns the series when the actor owns it or is an admin
+func (s *seriesService) getOwnSeries(ctx context.Context, id string) (*domain.Series, error) {
+	series, err := s.getSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	actor := ActorFromContext(ctx)
+	if !IsAdmin(ctx) && (series.Owner == "" || !strings.EqualFold(series.Owner, actor)) {
+		s.logger.Info("series change forbidden", "id", series.PublicID, "actor", actor)
+		return nil, ErrForbidden
+	}
+	return series, nil
+}
+
+// authorizePost checks that the actor may place the post in a series,
+// which owners and editors of the post and admins do
+func (s *seriesService) authorizePost(ctx context.Context, post *domain.Post) error {
+	contributors, err := s.contributors.List(ctx, post.ID)
+	if err != nil {
+		s.logger.Error("failed to list contributors", "id", post.PublicID, "error", err)
+		return fmt.Errorf("list contributors: %w", err)
+	}
+
+	if !hasRole(ctx, contributors, domain.RoleOwner, domain.RoleEditor) {
+		s.logger.Info("series post forbidden", "id", post.PublicID, "actor", ActorFromContext(ctx), "role", actorRole(ctx, contributors))
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s *seriesService) getPost(ctx context.Context, id string) (*domain.Post, error) {
+	post, err := s.posts.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get post", "id", id, "error", err)
+		return nil, fmt.Errorf("get post: %w", err)
+	}
+	return post, nil
+}
+
+func (s *seriesService) entries(ctx context.Context, series *domain.Series) ([]domain.SeriesEntry, error) {
+	entries, err := s.series.Entries(ctx, series.ID)
+	if err != nil {
+		s.logger.Error("failed to list series posts", "id", series.PublicID, "error", err)
+		return nil, fmt.Errorf("list series posts: %w", err)
+	}
+	return entries, nil
+}
+
+// withEntries fills in the posts of the series
+func (s *seriesService) withEntries(ctx context.Context, series *domain.Series) (*domain.Series, error) {
+	entries, err := s.entries(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+	series.Posts = entries
+	return series, nil
+}
@@ -0,0 +1,271 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/internal/repository/gormrepo"
+	"github.com/yakuter/ugin/internal/service"
+	"gorm.io/gorm"
+)
+
+// seedSeries stores the posts and a series holding them in the given
+// order, all owned by ann@example.com
+func seedSeries(t *testing.T, db *gorm.DB, svc service.SeriesService, posts ...string) *domain.Series {
+	t.Helper()
+
+	seedEngagement(t, db, posts, []string{"ann@example.com"})
+	ownPosts(t, db, "ann@example.com", domain.RoleOwner, posts...)
+	ctx := service.WithActor(context.Background(), "ann@example.com")
+	series, err := svc.Create(ctx, &domain.SeriesRequest{Name: "Learning Go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, slug := range posts {
+		if _, err := svc.AddPost(ctx, series.PublicID, &domain.AddSeriesPostRequest{PostID: slug}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return series
+}
+
+// ownPosts gives the user with email the role on the posts with the given slugs
+func ownPosts(t *testing.T, db *gorm.DB, email, role string, slugs ...string) {
+	t.Helper()
+
+	contributors := gormrepo.NewContributorRepository(db)
+	for _, slug := range slugs {
+		var post domain.Post
+		if err := db.Where("slug = ?", slug).Take(&post).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := contributors.Add(context.Background(), &domain.PostContributor{PostID: post.ID, Role: role}, email); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func entrySlugs(entries []domain.SeriesEntry) string {
+	var slugs []string
+	for _, entry := range entries {
+		slugs = append(slugs, entry.Slug)
+	}
+	return fmt.Sprint(slugs)
+}
+
+func TestSeriesService_Reorder(t *testing.T) {
+	db := engagementDB(t)
+	posts := gormrepo.NewPostRepository(db)
+	svc := service.NewSeriesService(gormrepo.NewSeriesRepository(db), posts, gormrepo.NewContributorRepository(db), &mockLogger{})
+	series := seedSeries(t, db, svc, "first", "second", "third")
+
+	third, err := posts.GetByID(context.Background(), "third")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := service.WithActor(context.Background(), "ann@example.com")
+
+	// Each case starts from the order left by the one before
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		postIDs []string
+		want    string
+		wantErr error
+	}{
+		{name: "by slug", ctx: ctx, postIDs: []string{"third", "first", "second"}, want: "[third first second]"},
+		{name: "by public ID", ctx: ctx, postIDs: []string{"first", third.PublicID, "second"}, want: "[first third second]"},
+		{name: "missing a post", ctx: ctx, postIDs: []string{"second", "first"}, want: "[first third second]", wantErr: repository.ErrInvalidInput},
+		{name: "post listed twice", ctx: ctx, postIDs: []string{"first", "first", "second"}, want: "[first third second]", wantErr: repository.ErrInvalidInput},
+		{name: "post not in the series", ctx: ctx, postIDs: []string{"first", "third", "fourth"}, want: "[first third second]", wantErr: repository.ErrInvalidInput},
+		{name: "anonymous", ctx: context.Background(), postIDs: []string{"second", "third", "first"}, want: "[first third second]", wantErr: service.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Reorder(tt.ctx, series.PublicID, &domain.ReorderSeriesRequest{PostIDs: tt.postIDs})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			got, err := svc.GetByID(context.Background(), series.PublicID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if entrySlugs(got.Posts) != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, entrySlugs(got.Posts))
+			}
+		})
+	}
+
+	// Trashed posts are left out of the order and keep their place after it
+	if err := db.Where("slug = ?", "third").Delete(&domain.Post{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Reorder(ctx, series.PublicID, &domain.ReorderSeriesRequest{PostIDs: []string{"second", "first"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := svc.GetByID(context.Background(), series.PublicID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entrySlugs(got.Posts) != "[second first]" || got.Posts[1].Position != 2 {
+		t.Errorf("expected [second first], got %s", entrySlugs(got.Posts))
+	}
+}
+
+func TestSeriesService_Navigation(t *testing.T) {
+	db := engagementDB(t)
+	posts := gormrepo.NewPostRepository(db)
+	svc := service.NewSeriesService(gormrepo.NewSeriesRepository(db), posts, gormrepo.NewContributorRepository(db), &mockLogger{})
+	series := seedSeries(t, db, svc, "first", "third")
+
+	// Inserting at a position moves the posts from there on along
+	seedEngagement(t, db, []string{"second"}, nil)
+	ownPosts(t, db, "ann@example.com", domain.RoleEditor, "second")
+	ctx := service.WithActor(context.Background(), "ann@example.com")
+	if _, err := svc.AddPost(ctx, series.PublicID, &domain.AddSeriesPostRequest{PostID: "second", Position: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		slug      string
+		trash     string
+		unpublish string
+		position  int
+		total     int
+		prev      string
+		next      string
+	}{
+		{slug: "first", position: 1, total: 3, next: "second"},
+		{slug: "second", position: 2, total: 3, prev: "first", next: "third"},
+		{slug: "third", position: 3, total: 3, prev: "second"},
+		// A trashed post is skipped by its neighbours
+		{slug: "first", trash: "second", position: 1, total: 2, next: "third"},
+		{slug: "third", position: 2, total: 2, prev: "first"},
+		// So is a post that is not published
+		{slug: "first", unpublish: "third", position: 1, total: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			if tt.trash != "" {
+				if err := db.Where("slug = ?", tt.trash).Delete(&domain.Post{}).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.unpublish != "" {
+				if err := db.Model(&domain.Post{}).Where("slug = ?", tt.unpublish).Update("status", domain.PostDraft).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			post, err := posts.GetByID(context.Background(), tt.slug)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			nav := post.Series
+			if nav == nil {
+				t.Fatal("expected the post to be in a series")
+			}
+			if nav.ID != series.PublicID || nav.Position != tt.position || nav.Total != tt.total {
+				t.Errorf("expected %d of %d, got %d of %d", tt.position, tt.total, nav.Position, nav.Total)
+			}
+
+			var prev, next string
+			if nav.Prev != nil {
+				prev = nav.Prev.Slug
+			}
+			if nav.Next != nil {
+				next = nav.Next.Slug
+			}
+			if prev != tt.prev || next != tt.next {
+				t.Errorf("expected prev %q next %q, got prev %q next %q", tt.prev, tt.next, prev, next)
+			}
+		})
+	}
+
+	// Removing a post from its series drops its navigation
+	if _, err := svc.RemovePost(ctx, series.PublicID, "first"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	post, err := posts.GetByID(context.Background(), "first")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if post.Series != nil {
+		t.Errorf("expected no series, got %+v", post.Series)
+	}
+}
+
+func TestSeriesService_Rights(t *testing.T) {
+	db := engagementDB(t)
+	svc := service.NewSeriesService(gormrepo.NewSeriesRepository(db), gormrepo.NewPostRepository(db), gormrepo.NewContributorRepository(db), &mockLogger{})
+	series := seedSeries(t, db, svc, "first")
+
+	seedEngagement(t, db, []string{"edited", "reviewed", "foreign"}, []string{"bob@example.com"})
+	ownPosts(t, db, "ann@example.com", domain.RoleEditor, "edited")
+	ownPosts(t, db, "ann@example.com", domain.RoleReviewer, "reviewed")
+	ownPosts(t, db, "bob@example.com", domain.RoleOwner, "foreign")
+
+	ann := service.WithActor(context.Background(), "ann@example.com")
+	bob := service.WithActor(context.Background(), "bob@example.com")
+	admin := service.WithAdmin(service.WithActor(context.Background(), "admin@example.com"))
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{name: "others may not rename", call: func() error {
+			_, err := svc.Update(bob, series.PublicID, &domain.SeriesRequest{Name: "Mine"})
+			return err
+		}, wantErr: service.ErrForbidden},
+		{name: "others may not reorder", call: func() error {
+			_, err := svc.Reorder(bob, series.PublicID, &domain.ReorderSeriesRequest{PostIDs: []string{"first"}})
+			return err
+		}, wantErr: service.ErrForbidden},
+		{name: "others may not add their own posts", call: func() error {
+			_, err := svc.AddPost(bob, series.PublicID, &domain.AddSeriesPostRequest{PostID: "foreign"})
+			return err
+		}, wantErr: service.ErrForbidden},
+		{name: "others may not remove posts", call: func() error {
+			_, err := svc.RemovePost(bob, series.PublicID, "first")
+			return err
+		}, wantErr: service.ErrForbidden},
+		{name: "others may not delete", call: func() error {
+			return svc.Delete(bob, series.PublicID)
+		}, wantErr: service.ErrForbidden},
+		{name: "owner may not add posts of others", call: func() error {
+			_, err := svc.AddPost(ann, series.PublicID, &domain.AddSeriesPostRequest{PostID: "foreign"})
+			return err
+		}, wantErr: service.ErrForbidden},
+		{name: "owner may not add posts they review", call: func() error {
+			_, err := svc.AddPost(ann, series.PublicID, &domain.AddSeriesPostRequest{PostID: "reviewed"})
+			return err
+		}, wantErr: service.ErrForbidden},
+		{name: "owner adds posts they edit", call: func() error {
+			_, err := svc.AddPost(ann, series.PublicID, &domain.AddSeriesPostRequest{PostID: "edited"})
+			return err
+		}},
+		{name: "admin renames", call: func() error {
+			_, err := svc.Update(admin, series.PublicID, &domain.SeriesRequest{Name: "Learning Go well"})
+			return err
+		}},
+		{name: "owner deletes", call: func() error {
+			return svc.Delete(ann, series.PublicID)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
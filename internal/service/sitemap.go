@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/pkg/sitemap"
+)
+
+// ErrSitemapNotFound is returned for a sitemap page the site does not have
+var ErrSitemapNotFound = fmt.Errorf("sitemap %w", repository.ErrNotFound)
+
+const (
+	// sitemapBatchSize is the number of changed posts read per query
+	sitemapBatchSize = 1000
+	// sitemapOverlap is how far before the newest change seen a refresh
+	// starts reading, so rows committed late with earlier timestamps are
+	// not missed
+	sitemapOverlap = time.Minute
+)
+
+// SitemapConfig holds sitemap configuration
+type SitemapConfig struct {
+	// BaseURL is the absolute URL the API is served at, without a trailing slash
+	BaseURL string
+	// Refresh is how long the sitemap is served before changed posts are read
+	Refresh time.Duration
+	// MaxURLs is the most URLs per sitemap, sitemap.MaxURLs when zero
+	MaxURLs int
+}
+
+// sitemapPost is what the sitemap keeps of a post
+type sitemapPost struct {
+	slug    string
+	updated time.Time
+	tags    []string
+}
+
+type sitemapService struct {
+	posts  repository.PostRepository
+	cfg    SitemapConfig
+	logger Logger
+
+	mu      sync.Mutex
+	entries map[uint]sitemapPost
+	// watermark is the newest change read so far
+	watermark time.Time
+	checked   time.Time
+	loaded    bool
+	// root and pages are the rendered documents, nil after a change
+	root  []byte
+	pages [][]byte
+}
+
+// NewSitemapService creates a new sitemap service
+func NewSitemapService(posts repository.PostRepository, cfg *SitemapConfig, logger Logger) SitemapService {
+	s := &sitemapService{
+		posts:   posts,
+		cfg:     *cfg,
+		logger:  logger,
+		entries: make(map[uint]sitemapPost),
+	}
+	if s.cfg.MaxURLs <= 0 || s.cfg.MaxURLs > sitemap.MaxURLs {
+		s.cfg.MaxURLs = sitemap.MaxURLs
+	}
+	return s
+}
+
+func (s *sitemapService) Sitemap(ctx context.Context) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s.root, nil
+}
+
+func (s *sitemapService) Page(ctx context.Context, page int) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	// A site fitting into one sitemap has no pages
+	if page < 1 || page > len(s.pages) {
+		return nil, ErrSitemapNotFound
+	}
+	return s.pages[page-1], nil
+}
+
+// refresh applies the posts changed since the last refresh, once the
+// refresh interval has passed, and renders the documents again if any
+// entry changed. s.mu must be held.
+func (s *sitemapService) refresh(ctx context.Context) error {
+	now := time.Now()
+	if s.loaded && now.Sub(s.checked) < s.cfg.Refresh {
+		return nil
+	}
+
+	since := time.Time{}
+	if s.loaded {
+		since = s.watermark.Add(-sitemapOverlap)
+	}
+
+	changed := 0
+	var afterID uint
+	for {
+		posts, err := s.posts.ListChangedSince(ctx, since, afterID, sitemapBatchSize)
+		if err != nil {
+			s.logger.Error("failed to list changed posts", "since", since, "error", err)
+			return fmt.Errorf("refresh sitemap: %w", err)
+		}
+
+		for _, post := range posts {
+			afterID = post.ID
+			if post.UpdatedAt.After(s.watermark) {
+				s.watermark = post.UpdatedAt
+			}
+
+			if post.DeletedAt.Valid && post.DeletedAt.Time.After(s.watermark) {
+				s.watermark = post.DeletedAt.Time
+			}
+
+			// Deleted posts and posts still under review are left out
+			if post.DeletedAt.Valid || post.Status != domain.PostPublished {
+				if _, ok := s.entries[post.ID]; ok {
+					delete(s.entries, post.ID)
+					changed++
+				}
+				continue
+			}
+
+			entry := sitemapPost{slug: post.Slug, updated: post.UpdatedAt}
+			for _, tag := range post.Tags {
+				entry.tags = append(entry.tags, tag.Name)
+			}
+			if old, ok := s.entries[post.ID]; !ok || !old.equal(entry) {
+				s.entries[post.ID] = entry
+				changed++
+			}
+		}
+
+		if len(posts) < sitemapBatchSize {
+			break
+		}
+	}
+
+	s.loaded = true
+	s.checked = now
+	if changed > 0 || s.root == nil {
+		if err := s.render(); err != nil {
+			s.logger.Error("failed to render sitemap", "error", err)
+			return fmt.Errorf("render sitemap: %w", err)
+		}
+		s.logger.Debug("sitemap refreshed", "changed", changed, "posts", len(s.entries))
+	}
+	return nil
+}
+
+// render builds the sitemap documents from the entries. s.mu must be held.
+func (s *sitemapService) render() error {
+	urls := s.urls()
+
+	if len(urls) <= s.cfg.MaxURLs {
+		root, err := sitemap.URLSet(urls)
+		if err != nil {
+			return err
+		}
+		s.root, s.pages = root, nil
+		return nil
+	}
+
+	chunks := sitemap.Split(urls, s.cfg.MaxURLs)
+	pages := make([][]byte, len(chunks))
+	index := make([]sitemap.URL, len(chunks))
+	for i, chunk := range chunks {
+		page, err := sitemap.URLSet(chunk)
+		if err != nil {
+			return err
+		}
+		pages[i] = page
+		index[i] = sitemap.URL{Loc: s.cfg.BaseURL + "/sitemaps/" + strconv.Itoa(i+1) + ".xml", LastMod: lastMod(chunk)}
+	}
+
+	root, err := sitemap.Index(index)
+	if err != nil {
+		return err
+	}
+	s.root, s.pages = root, pages
+	return nil
+}
+
+// urls lists every post in ID order followed by every tag page by name. A
+// tag page last changed when the latest of its posts did.
+func (s *sitemapService) urls() []sitemap.URL {
+	ids := make([]uint, 0, len(s.entries))
+	for id := range s.entries {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	type tagPage struct {
+		name    string
+		updated time.Time
+	}
+	tags := make(map[string]*tagPage)
+
+	urls := make([]sitemap.URL, 0, len(ids))
+	for _, id := range ids {
+		entry := s.entries[id]
+		urls = append(urls, sitemap.URL{Loc: s.cfg.BaseURL + "/api/v1/posts/" + url.PathEscape(entry.slug), LastMod: entry.updated})
+
+		for _, name := range entry.tags {
+			// Tags are matched ignoring case, so they share one page
+			key := strings.ToLower(name)
+			page, ok := tags[key]
+			if !ok {
+				page = &tagPage{name: key}
+				tags[key] = page
+			}
+			if entry.updated.After(page.updated) {
+				page.updated = entry.updated
+			}
+		}
+	}
+
+	pages := make([]*tagPage, 0, len(tags))
+	for _, page := range tags {
+		pages = append(pages, page)
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].name < pages[j].name })
+	for _, page := range pages {
+		urls = append(urls, sitemap.URL{Loc: s.cfg.BaseURL + "/api/v1/posts?Tag=" + url.QueryEscape(page.name), LastMod: page.updated})
+	}
+
+	return urls
+}
+
+func (e sitemapPost) equal(other sitemapPost) bool {
+	if e.slug != other.slug || !e.updated.Equal(other.updated) || len(e.tags) != len(other.tags) {
+		return false
+	}
+	for i := range e.tags {
+		if e.tags[i] != other.tags[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// lastMod returns the latest change of urls
+func lastMod(urls []sitemap.URL) time.Time {
+	var latest time.Time
+	for _, u := range urls {
+		if u.LastMod.After(latest) {
+			latest = u.LastMod
+		}
+	}
+	return latest
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/pkg/tfidf"
+	"github.com/yakuter/ugin/pkg/trie"
+)
+
+const (
+	// maxSuggestions is the most posts, tags or proposed tags returned
+	maxSuggestions = 20
+	// tagNeighbours is the number of similar posts whose tags are proposed
+	// for a draft
+	tagNeighbours = 10
+)
+
+// suggestPost is what suggestions keep of a post
+type suggestPost struct {
+	publicID string
+	name     string
+	slug     string
+	// tags holds the lowercase names of the post's tags
+	tags []string
+}
+
+// suggestTag is a tag with the posts that have it
+type suggestTag struct {
+	name  string
+	posts map[uint]bool
+}
+
+type suggestService struct {
+	posts  repository.PostRepository
+	logger Logger
+
+	mu sync.RWMutex
+	// names maps the normalized name of every post, from each of its
+	// words on, to the post's ID
+	names trie.Trie
+	// tagNames maps lowercase tag names to themselves
+	tagNames trie.Trie
+	byID     map[uint]*suggestPost
+	tags     map[string]*suggestTag
+	text     *tfidf.Index
+}
+
+// NewSuggestService creates a new suggestion service. Load must be called
+// before suggestions are made, and PostChanged subscribed to the post
+// service to keep them up to date.
+func NewSuggestService(posts repository.PostRepository, logger Logger) SuggestService {
+	return &suggestService{
+		posts:  posts,
+		logger: logger,
+		byID:   make(map[uint]*suggestPost),
+		tags:   make(map[string]*suggestTag),
+		text:   tfidf.New(),
+	}
+}
+
+func (s *suggestService) Load(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.posts.Stream(ctx, repository.ListFilter{Status: domain.PostPublished}, func(post *domain.Post) error {
+		s.add(post)
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("failed to load posts for suggestions", "error", err)
+		return fmt.Errorf("load suggestions: %w", err)
+	}
+
+	s.logger.Info("suggestions loaded", "posts", len(s.byID), "tags", len(s.tags))
+	return nil
+}
+
+func (s *suggestService) PostChanged(ctx context.Context, event PostEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Only published posts are suggested
+	if event.Type == PostDeleted || event.Post.Status != domain.PostPublished {
+		s.remove(event.Post.ID)
+	} else {
+		s.add(event.Post)
+	}
+}
+
+func (s *suggestService) Suggest(ctx context.Context, q string, limit int) (*domain.Suggestions, error) {
+	q = normalizeName(q)
+	if q == "" {
+		return nil, fmt.Errorf("%w: q is required", repository.ErrInvalidInput)
+	}
+	limit = suggestionLimit(limit)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	suggestions := &domain.Suggestions{Posts: []domain.PostSuggestion{}, Tags: []domain.TagSuggestion{}}
+	for _, id := range s.names.Search(q, limit) {
+		postID, _ := strconv.ParseUint(id, 10, 64)
+		post := s.byID[uint(postID)]
+		suggestions.Posts = append(suggestions.Posts, domain.PostSuggestion{PostID: post.publicID, Name: post.name, Slug: post.slug})
+	}
+
+	// Matching tags are few enough to rank all of them by use
+	for _, key := range s.tagNames.Search(q, 0) {
+		tag := s.tags[key]
+		suggestions.Tags = append(suggestions.Tags, domain.TagSuggestion{Name: tag.name, Posts: len(tag.posts)})
+	}
+	sort.SliceStable(suggestions.Tags, func(i, j int) bool {
+		return suggestions.Tags[i].Posts > suggestions.Tags[j].Posts
+	})
+	if len(suggestions.Tags) > limit {
+		suggestions.Tags = suggestions.Tags[:limit]
+	}
+
+	return suggestions, nil
+}
+
+func (s *suggestService) SuggestTags(ctx context.Context, req *domain.TagSuggestionRequest, limit int) ([]domain.TagSuggestion, error) {
+	if req == nil {
+		return nil, repository.ErrInvalidInput
+	}
+	text := req.Name + "\n" + req.Description
+	terms := tfidf.Terms(text)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: name or description is required", repository.ErrInvalidInput)
+	}
+	limit = suggestionLimit(limit)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Tags of similar posts are proposed by how similar those posts are
+	scores := make(map[string]float64)
+	for _, match := range s.text.SimilarTo(text, tagNeighbours) {
+		postID, _ := strconv.ParseUint(match.ID, 10, 64)
+		for _, key := range s.byID[uint(postID)].tags {
+			scores[key] += match.Score
+		}
+	}
+
+	// Tags the draft mentions are proposed ahead of those
+	mentioned := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		mentioned[term] = true
+	}
+	for key := range s.tags {
+		if tagMentioned(key, mentioned) {
+			scores[key]++
+		}
+	}
+
+	var best float64
+	for _, score := range scores {
+		if score > best {
+			best = score
+		}
+	}
+
+	proposed := make([]domain.TagSuggestion, 0, len(scores))
+	for key, score := range scores {
+		tag := s.tags[key]
+		proposed = append(proposed, domain.TagSuggestion{Name: tag.name, Posts: len(tag.posts), Score: score / best})
+	}
+	sort.Slice(proposed, func(i, j int) bool {
+		if proposed[i].Score != proposed[j].Score {
+			return proposed[i].Score > proposed[j].Score
+		}
+		if proposed[i].Posts != proposed[j].Posts {
+			return proposed[i].Posts > proposed[j].Posts
+		}
+		return proposed[i].Name < proposed[j].Name
+	})
+
+	if len(proposed) > limit {
+		proposed = proposed[:limit]
+	}
+	return proposed, nil
+}
+
+// add indexes the post, replacing an earlier version. s.mu must be held.
+func (s *suggestService) add(post *domain.Post) {
+	s.remove(post.ID)
+
+	id := docID(post.ID)
+	entry := &suggestPost{publicID: post.PublicID, name: post.Name, slug: post.Slug}
+	for _, key := range nameKeys(post.Name) {
+		s.names.Insert(key, id)
+	}
+
+	for _, t := range post.Tags {
+		key := strings.ToLower(strings.TrimSpace(t.Name))
+		if key == "" {
+			continue
+		}
+		tag, ok := s.tags[key]
+		if !ok {
+			// The first spelling of a tag is the one suggested
+			tag = &suggestTag{name: t.Name, posts: make(map[uint]bool)}
+			s.tags[key] = tag
+			s.tagNames.Insert(key, key)
+		}
+		if !tag.posts[post.ID] {
+			tag.posts[post.ID] = true
+			entry.tags = append(entry.tags, key)
+		}
+	}
+
+	s.byID[post.ID] = entry
+	s.text.Add(id, post.Name+"\n"+post.Description)
+}
+
+// remove drops the post from the index. s.mu must be held.
+func (s *suggestService) remove(postID uint) {
+	entry, ok := s.byID[postID]
+	if !ok {
+		return
+	}
+
+	id := docID(postID)
+	for _, key := range nameKeys(entry.name) {
+		s.names.Delete(key, id)
+	}
+
+	for _, key := range entry.tags {
+		tag := s.tags[key]
+		delete(tag.posts, postID)
+		if len(tag.posts) == 0 {
+			delete(s.tags, key)
+			s.tagNames.Delete(key, key)
+		}
+	}
+
+	delete(s.byID, postID)
+	s.text.Remove(id)
+}
+
+// nameKeys returns the normalized name from each of its words on, so
+// prefixes of any word find the post
+func nameKeys(name string) []string {
+	words := strings.Fields(strings.ToLower(name))
+	keys := make([]string, len(words))
+	for i := range words {
+		keys[i] = strings.Join(words[i:], " ")
+	}
+	return keys
+}
+
+// normalizeName lowercases s and collapses its whitespace
+func normalizeName(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// tagMentioned reports whether every term of the tag named key occurs in
+// the mentioned terms
+func tagMentioned(key string, mentioned map[string]bool) bool {
+	terms := tfidf.Terms(key)
+	if len(terms) == 0 {
+		return false
+	}
+	for _, term := range terms {
+		if !mentioned[term] {
+			return false
+		}
+	}
+	return true
+}
+
+func suggestionLimit(limit int) int {
+	if limit <= 0 {
+		return 5
+	}
+	if limit > maxSuggestions {
+		return maxSuggestions // Max limit
+	}
+	return limit
+}
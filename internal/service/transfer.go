@@ -0,0 +1,291 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+)
+
+const (
+	// maxImportLineSize bounds a single NDJSON line of an import
+	maxImportLineSize = 1 << 20
+	// maxImportErrors bounds the per-line errors listed in an import result
+	maxImportErrors = 100
+)
+
+// csvColumns is the header of exported CSV files
+var csvColumns = []string{"id", "slug", "name", "description", "format", "category_id", "tags", "version", "created_at", "updated_at"}
+
+// exportTag is how a tag is written to the tags column of a CSV export
+type exportTag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+func (s *postService) Export(ctx context.Context, w io.Writer, format, status string) error {
+	filter := repository.ListFilter{Status: status}
+	if err := statusFilter(ctx, &filter); err != nil {
+		return err
+	}
+
+	var write func(*domain.Post) error
+
+	switch format {
+	case domain.FileFormatNDJSON:
+		enc := json.NewEncoder(w)
+		write = func(post *domain.Post) error {
+			return enc.Encode(post)
+		}
+	case domain.FileFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvColumns); err != nil {
+			return fmt.Errorf("export posts: %w", err)
+		}
+		defer cw.Flush()
+
+		write = func(post *domain.Post) error {
+			tags := make([]exportTag, 0, len(post.Tags))
+			for _, tag := range post.Tags {
+				tags = append(tags, exportTag{Name: tag.Name, Description: tag.Description})
+			}
+			encoded, err := json.Marshal(tags)
+			if err != nil {
+				return err
+			}
+
+			return cw.Write([]string{
+				post.PublicID,
+				post.Slug,
+				post.Name,
+				post.Description,
+				post.Format,
+				post.CategoryPublicID,
+				string(encoded),
+				strconv.FormatUint(uint64(post.Version), 10),
+				post.CreatedAt.UTC().Format(time.RFC3339),
+				post.UpdatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+	default:
+		return fmt.Errorf("%w: unsupported format %q", repository.ErrInvalidInput, format)
+	}
+
+	exported := 0
+	err := s.repo.Stream(ctx, filter, func(post *domain.Post) error {
+		exported++
+		return write(post)
+	})
+	if err != nil {
+		s.logger.Error("failed to export posts", "format", format, "exported", exported, "error", err)
+		return fmt.Errorf("export posts: %w", err)
+	}
+
+	s.logger.Info("posts exported", "format", format, "status", filter.Status, "count", exported)
+	return nil
+}
+
+func (s *postService) Import(ctx context.Context, r io.Reader, format string, dryRun bool) (*domain.ImportResult, error) {
+	result := &domain.ImportResult{DryRun: dryRun}
+
+	handle := func(line int, post *domain.Post, err error) error {
+		result.Lines++
+		if err == nil {
+			err = validateImport(post)
+		}
+		if err == nil && dryRun {
+			err = s.resolveCategory(ctx, post)
+		}
+		if err == nil {
+			if dryRun {
+				result.Valid++
+				return nil
+			}
+			err = s.Create(ctx, post)
+			if err == nil {
+				result.Valid++
+				result.Imported++
+				return nil
+			}
+			// Anything but a problem with the line itself ends the import
+			if !errors.Is(err, repository.ErrInvalidInput) && !errors.Is(err, repository.ErrAlreadyExists) {
+				return err
+			}
+		}
+
+		result.Failed++
+		if len(result.Errors) < maxImportErrors {
+			result.Errors = append(result.Errors, domain.ImportError{Line: line, Error: err.Error()})
+		}
+		return nil
+	}
+
+	var err error
+	switch format {
+	case domain.FileFormatNDJSON:
+		err = readNDJSON(r, handle)
+	case domain.FileFormatCSV:
+		err = readCSV(r, handle)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", repository.ErrInvalidInput, format)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidInput) {
+			return result, err
+		}
+		s.logger.Error("failed to import posts", "format", format, "imported", result.Imported, "error", err)
+		return result, fmt.Errorf("import posts: %w", err)
+	}
+
+	s.logger.Info("posts imported", "format", format, "dry_run", dryRun, "imported", result.Imported, "failed", result.Failed)
+	return result, nil
+}
+
+// validateImport applies the request validation an imported post skipped
+func validateImport(post *domain.Post) error {
+	if post.Name == "" {
+		return fmt.Errorf("%w: name is required", repository.ErrInvalidInput)
+	}
+	if err := checkFormat(post.Format); err != nil {
+		return err
+	}
+	for i, tag := range post.Tags {
+		if tag.Name == "" {
+			return fmt.Errorf("%w: tag %d: name is required", repository.ErrInvalidInput, i+1)
+		}
+	}
+	return nil
+}
+
+// readNDJSON decodes one post per non-blank line and passes it to handle
+// together with its line number and any decoding error
+func readNDJSON(r io.Reader, handle func(line int, post *domain.Post, err error) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var post domain.Post
+		var err error
+		if err = json.Unmarshal(data, &post); err != nil {
+			err = fmt.Errorf("%w: %v", repository.ErrInvalidInput, err)
+		}
+		if err := handle(line, &post, err); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return fmt.Errorf("%w: line %d is longer than %d bytes", repository.ErrInvalidInput, line+1, maxImportLineSize)
+		}
+		return err
+	}
+	return nil
+}
+
+// readCSV reads posts from a CSV file whose header names the columns. The
+// name column is required; description and tags are optional and any
+// other column, such as those of an export, is ignored. Tags are either a
+// JSON array of objects as exported or a comma-separated list of names.
+func readCSV(r io.Reader, handle func(line int, post *domain.Post, err error) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("%w: missing CSV header", repository.ErrInvalidInput)
+		}
+		return fmt.Errorf("%w: %v", repository.ErrInvalidInput, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return fmt.Errorf("%w: CSV header has no name column", repository.ErrInvalidInput)
+	}
+
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		line, _ := reader.FieldPos(0)
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			// The reader resumes at the next record after a malformed one
+			if err := handle(parseErr.StartLine, nil, fmt.Errorf("%w: %v", repository.ErrInvalidInput, parseErr.Err)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		post := &domain.Post{
+			Name:             field(record, "name"),
+			Description:      field(record, "description"),
+			Format:           field(record, "format"),
+			CategoryPublicID: field(record, "category_id"),
+		}
+		post.Tags, err = parseCSVTags(field(record, "tags"))
+		if err := handle(line, post, err); err != nil {
+			return err
+		}
+	}
+}
+
+// parseCSVTags reads the tags column of a CSV import
+func parseCSVTags(value string) ([]domain.Tag, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	var tags []domain.Tag
+	if strings.HasPrefix(value, "[") {
+		var decoded []exportTag
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			return nil, fmt.Errorf("%w: tags: %v", repository.ErrInvalidInput, err)
+		}
+		for _, tag := range decoded {
+			tags = append(tags, domain.Tag{Name: tag.Name, Description: tag.Description})
+		}
+		return tags, nil
+	}
+
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			tags = append(tags, domain.Tag{Name: name})
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yakuter/ugin/internal/domain"
+	"github.com/yakuter/ugin/internal/repository"
+	"github.com/yakuter/ugin/pkg/locale"
+)
+
+// ErrTranslationNotFound is returned when a post has no translation to the requested locale
+var ErrTranslationNotFound = fmt.Errorf("translation %w", repository.ErrNotFound)
+
+type translationService struct {
+	translations  repository.PostTranslationRepository
+	posts         repository.PostRepository
+	contributors  repository.ContributorRepository
+	defaultLocale string
+	logger        Logger
+}
+
+// NewTranslationService creates a new translation service. Posts
+// themselves are written in defaultLocale, so they take no translation to
+// it. Translations are changed by the owners and editors of their post.
+func NewTranslationService(translations repository.PostTranslationRepository, posts repository.PostRepository, contributors repository.ContributorRepository, defaultLocale string, logger Logger) TranslationService {
+	return &translationService{
+		translations:  translations,
+		posts:         posts,
+		contributors:  contributors,
+		defaultLocale: defaultLocale,
+		logger:        logger,
+	}
+}
+
+func (s *translationService) List(ctx context.Context, postID string) ([]*domain.PostTranslation, error) {
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	translations, err := s.translations.ListByPostID(ctx, post.ID)
+	if err != nil {
+		s.logger.Error("failed to list translations", "post_id", postID, "error", err)
+		return nil, fmt.Errorf("list translations: %w", err)
+	}
+
+	return translations, nil
+}
+
+func (s *translationService) Put(ctx context.Context, postID, loc string, req *domain.PostTranslationRequest) (*domain.PostTranslation, bool, error) {
+	if req == nil {
+		return nil, false, repository.ErrInvalidInput
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, false, fmt.Errorf("%w: name is required", repository.ErrInvalidInput)
+	}
+	if err := checkFormat(req.Format); err != nil {
+		return nil, false, err
+	}
+
+	loc, err := s.checkLocale(loc)
+	if err != nil {
+		return nil, false, err
+	}
+
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.authorize(ctx, post); err != nil {
+		return nil, false, err
+	}
+
+	translation, err := s.translations.Get(ctx, post.ID, loc)
+	created := errors.Is(err, repository.ErrNotFound)
+	if err != nil && !created {
+		s.logger.Error("failed to get translation", "post_id", postID, "locale", loc, "error", err)
+		return nil, false, fmt.Errorf("get translation: %w", err)
+	}
+	if created {
+		translation = &domain.PostTranslation{PostID: post.ID, Locale: loc}
+	}
+
+	// Translations are written in the format of their post unless told otherwise
+	format := req.Format
+	if format == "" {
+		format = post.Format
+	}
+	if format == "" {
+		format = domain.FormatPlain
+	}
+	translation.Name = name
+	translation.Description = req.Description
+	translation.Format = format
+	translation.DescriptionHTML = renderMarkup(format, req.Description)
+
+	if created {
+		err = s.translations.Create(ctx, translation)
+	} else {
+		err = s.translations.Update(ctx, translation)
+	}
+	if err != nil {
+		// Another request saved the same translation first
+		if errors.Is(err, repository.ErrAlreadyExists) || errors.Is(err, repository.ErrConflict) {
+			s.logger.Info("translation modified concurrently", "post_id", postID, "locale", loc)
+			return nil, false, repository.ErrConflict
+		}
+		s.logger.Error("failed to save translation", "post_id", postID, "locale", loc, "error", err)
+		return nil, false, fmt.Errorf("save translation: %w", err)
+	}
+
+	s.logger.Info("translation saved", "post_id", postID, "locale", loc, "created", created)
+	return translation, created, nil
+}
+
+func (s *translationService) Delete(ctx context.Context, postID, loc string) error {
+	loc, err := s.checkLocale(loc)
+	if err != nil {
+		return err
+	}
+
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if err := s.authorize(ctx, post); err != nil {
+		return err
+	}
+
+	if err := s.translations.Delete(ctx, post.ID, loc); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTranslationNotFound
+		}
+		s.logger.Error("failed to delete translation", "post_id", postID, "locale", loc, "error", err)
+		return fmt.Errorf("delete translation: %w", err)
+	}
+
+	s.logger.Info("translation deleted", "post_id", postID, "locale", loc)
+	return nil
+}
+
+func (s *translationService) Translate(ctx context.Context, chain []string, posts ...*domain.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	// Only the locales before the default one can be preferred over the post itself
+	var locales []string
+	for _, loc := range chain {
+		if loc == s.defaultLocale {
+			break
+		}
+		locales = append(locales, loc)
+	}
+
+	ids := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+
+	translations, err := s.translations.ListForPosts(ctx, ids, locales)
+	if err != nil {
+		s.logger.Error("failed to list translations", "error", err)
+		return fmt.Errorf("translate posts: %w", err)
+	}
+
+	byPost := make(map[uint]map[string]*domain.PostTranslation, len(posts))
+	for _, translation := range translations {
+		if byPost[translation.PostID] == nil {
+			byPost[translation.PostID] = make(map[string]*domain.PostTranslation)
+		}
+		byPost[translation.PostID][translation.Locale] = translation
+	}
+
+	for _, post := range posts {
+		post.Locale = s.defaultLocale
+		for _, loc := range locales {
+			translation, ok := byPost[post.ID][loc]
+			if !ok {
+				continue
+			}
+			post.Name = translation.Name
+			post.Description = translation.Description
+			post.Format = translation.Format
+			post.DescriptionHTML = translation.DescriptionHTML
+			post.Locale = translation.Locale
+			post.Translation = translation
+			break
+		}
+	}
+
+	return nil
+}
+
+// checkLocale returns loc in canonical form, rejecting the default locale
+// posts are written in
+func (s *translationService) checkLocale(loc string) (string, error) {
+	canonical, err := locale.Canonical(loc)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", repository.ErrInvalidInput, err)
+	}
+	if canonical == s.defaultLocale {
+		return "", fmt.Errorf("%w: posts are written in %s; update the post itself instead", repository.ErrInvalidInput, canonical)
+	}
+	return canonical, nil
+}
+
+func (s *translationService) getPost(ctx context.Context, id string) (*domain.Post, error) {
+	if id == "" {
+		return nil, repository.ErrInvalidInput
+	}
+
+	post, err := s.posts.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get post", "id", id, "error", err)
+		return nil, fmt.Errorf("get post: %w", err)
+	}
+
+	if err := readable(ctx, s.contributors, s.logger, post); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// authorize checks that the actor owns or edits the post
+func (s *translationService) authorize(ctx context.Context, post *domain.Post) error {
+	contributors, err := s.contributors.List(ctx, post.ID)
+	if err != nil {
+		s.logger.Error("failed to list contributors", "id", post.PublicID, "error", err)
+		return fmt.Errorf("list contributors: %w", err)
+	}
+
+	if !hasRole(ctx, contributors, domain.RoleOwner, domain.RoleEditor) {
+		s.logger.Info("translation change forbidden", "post_id", post.PublicID, "actor", ActorFromContext(ctx))
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+// Package blob stores opaque binary objects under slash-separated keys on
+// the local filesystem or in an S3-compatible object store.
+package blob
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when no object is stored under a key
+	ErrNotFound = errors.New("blob not found")
+	// ErrInvalidKey is returned for keys that are empty, absolute or
+	// contain empty, "." or ".." segments
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// validKey reports whether key is a relative, clean slash-separated path
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
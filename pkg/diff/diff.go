@@ -0,0 +1,152 @@
+// Package diff computes line-oriented differences between two texts.
+package diff
+
+import "strings"
+
+// Operation describes how a line changed between two texts
+type Operation string
+
+const (
+	Equal  Operation = "equal"
+	Insert Operation = "insert"
+	Delete Operation = "delete"
+)
+
+// Line is a single line of a diff
+type Line struct {
+	Op   Operation `json:"op"`
+	Text string    `json:"text"`
+}
+
+// Lines returns the line diff that turns a into b.
+// Lines present in both texts are reported as Equal, lines only in a as
+// Delete and lines only in b as Insert.
+func Lines(a, b string) []Line {
+	return Slices(splitLines(a), splitLines(b))
+}
+
+// maxCells bounds the work spent aligning the changed lines of two texts,
+// counted as the product of their line counts. Longer changes are reported
+// as all lines of a deleted and all lines of b inserted.
+const maxCells = 1 << 24
+
+// Slices returns the diff that turns the lines of a into the lines of b
+func Slices(a, b []string) []Line {
+	// Trim the common prefix and suffix so only the region that actually
+	// changed is aligned
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]Line, 0, len(a)+len(b))
+	for _, text := range a[:prefix] {
+		result = append(result, Line{Op: Equal, Text: text})
+	}
+
+	changedA, changedB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(changedA)*len(changedB) > maxCells {
+		result = replace(result, changedA, changedB)
+	} else {
+		result = hirschberg(result, changedA, changedB)
+	}
+
+	for _, text := range a[len(a)-suffix:] {
+		result = append(result, Line{Op: Equal, Text: text})
+	}
+
+	return result
+}
+
+// hirschberg appends the diff of a and b to result following a longest
+// common subsequence, which it finds in space linear in the length of b
+// by splitting a in half and recursing on both sides
+func hirschberg(result []Line, a, b []string) []Line {
+	switch {
+	case len(a) == 0 || len(b) == 0:
+		return replace(result, a, b)
+	case len(a) == 1:
+		for j, text := range b {
+			if text == a[0] {
+				result = replace(result, nil, b[:j])
+				result = append(result, Line{Op: Equal, Text: text})
+				return replace(result, nil, b[j+1:])
+			}
+		}
+		return replace(result, a, b)
+	}
+
+	// Split b where the LCS of the top half of a with its start plus the
+	// LCS of the bottom half with its end is longest
+	mid := len(a) / 2
+	top := lcsLengths(a[:mid], b)
+	bottom := lcsLengthsReverse(a[mid:], b)
+	split := 0
+	for j := range top {
+		if top[j]+bottom[j] > top[split]+bottom[split] {
+			split = j
+		}
+	}
+
+	result = hirschberg(result, a[:mid], b[:split])
+	return hirschberg(result, a[mid:], b[split:])
+}
+
+// lcsLengths returns the LCS lengths of a with every prefix b[:j]
+func lcsLengths(a, b []string) []int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for _, text := range a {
+		for j := 1; j <= len(b); j++ {
+			if text == b[j-1] {
+				cur[j] = prev[j-1] + 1
+			} else {
+				cur[j] = max(prev[j], cur[j-1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// lcsLengthsReverse returns the LCS lengths of a with every suffix b[j:]
+func lcsLengthsReverse(a, b []string) []int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				cur[j] = prev[j+1] + 1
+			} else {
+				cur[j] = max(prev[j], cur[j+1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// replace appends every line of a as deleted and every line of b as inserted
+func replace(result []Line, a, b []string) []Line {
+	for _, text := range a {
+		result = append(result, Line{Op: Delete, Text: text})
+	}
+	for _, text := range b {
+		result = append(result, Line{Op: Insert, Text: text})
+	}
+	return result
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,100 @@
+package diff_test
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/yakuter/ugin/pkg/diff"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want []diff.Line
+	}{
+		{
+			name: "identical",
+			a:    "one\ntwo",
+			b:    "one\ntwo",
+			want: []diff.Line{
+				{Op: diff.Equal, Text: "one"},
+				{Op: diff.Equal, Text: "two"},
+			},
+		},
+		{
+			name: "empty to text",
+			a:    "",
+			b:    "one",
+			want: []diff.Line{
+				{Op: diff.Insert, Text: "one"},
+			},
+		},
+		{
+			name: "changed middle line",
+			a:    "one\ntwo\nthree",
+			b:    "one\n2\nthree",
+			want: []diff.Line{
+				{Op: diff.Equal, Text: "one"},
+				{Op: diff.Delete, Text: "two"},
+				{Op: diff.Insert, Text: "2"},
+				{Op: diff.Equal, Text: "three"},
+			},
+		},
+		{
+			name: "removed and appended",
+			a:    "a\nb\nc\nd",
+			b:    "a\nc\nd\ne",
+			want: []diff.Line{
+				{Op: diff.Equal, Text: "a"},
+				{Op: diff.Delete, Text: "b"},
+				{Op: diff.Equal, Text: "c"},
+				{Op: diff.Equal, Text: "d"},
+				{Op: diff.Insert, Text: "e"},
+			},
+		},
+		{
+			name: "reordered",
+			a:    "a\nb\nc\nd\ne",
+			b:    "b\na\nc\ne\nd",
+			want: []diff.Line{
+				{Op: diff.Delete, Text: "a"},
+				{Op: diff.Equal, Text: "b"},
+				{Op: diff.Insert, Text: "a"},
+				{Op: diff.Equal, Text: "c"},
+				{Op: diff.Delete, Text: "d"},
+				{Op: diff.Equal, Text: "e"},
+				{Op: diff.Insert, Text: "d"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diff.Lines(tt.a, tt.b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinesLarge(t *testing.T) {
+	// Texts too long to align line by line are replaced as a whole
+	var a, b strings.Builder
+	for i := 0; i < 5000; i++ {
+		a.WriteString("a" + strconv.Itoa(i) + "\n")
+		b.WriteString("b" + strconv.Itoa(i) + "\n")
+	}
+
+	got := diff.Lines(a.String(), b.String())
+	if len(got) != 10000 {
+		t.Fatalf("expected 10000 lines, got %d", len(got))
+	}
+	if got[0] != (diff.Line{Op: diff.Delete, Text: "a0"}) || got[5000] != (diff.Line{Op: diff.Insert, Text: "b0"}) {
+		t.Errorf("unexpected diff start: %v, %v", got[0], got[5000])
+	}
+}
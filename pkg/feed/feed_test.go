@@ -0,0 +1,137 @@
+package feed_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yakuter/ugin/pkg/feed"
+)
+
+func testFeed() *feed.Feed {
+	published := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	return &feed.Feed{
+		Title:       "UGin Blog",
+		Description: "Posts & news",
+		Link:        "https://example.com",
+		FeedURL:     "https://example.com/feed.rss",
+		Updated:     published.Add(time.Hour),
+		Items: []feed.Item{{
+			ID:          "urn:uuid:0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e",
+			Title:       "Getting <Started>",
+			Link:        "https://example.com/posts/getting-started",
+			ContentHTML: "<p>Hello &amp; welcome</p>",
+			Published:   published,
+			Updated:     published.Add(time.Hour),
+			Tags:        []string{"golang", "intro"},
+		}},
+	}
+}
+
+func TestRSS(t *testing.T) {
+	out, err := testFeed().RSS()
+	if err != nil {
+		t.Fatalf("RSS: %v", err)
+	}
+
+	var doc struct {
+		Channel struct {
+			Title string `xml:"title"`
+			Items []struct {
+				Title       string   `xml:"title"`
+				GUID        string   `xml:"guid"`
+				PubDate     string   `xml:"pubDate"`
+				Categories  []string `xml:"category"`
+				Description string   `xml:"description"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+
+	if len(doc.Channel.Items) != 1 {
+		t.Fatalf("got %d items, want 1", len(doc.Channel.Items))
+	}
+	item := doc.Channel.Items[0]
+	if item.Title != "Getting <Started>" || item.Description != "<p>Hello &amp; welcome</p>" {
+		t.Errorf("item text not round-tripped: %+v", item)
+	}
+	if item.PubDate != "Sat, 01 Mar 2025 10:00:00 +0000" {
+		t.Errorf("pubDate = %q", item.PubDate)
+	}
+	if len(item.Categories) != 2 {
+		t.Errorf("categories = %v", item.Categories)
+	}
+}
+
+func TestAtom(t *testing.T) {
+	out, err := testFeed().Atom()
+	if err != nil {
+		t.Fatalf("Atom: %v", err)
+	}
+	if !strings.Contains(string(out), `<feed xmlns="http://www.w3.org/2005/Atom">`) {
+		t.Errorf("missing Atom namespace:\n%s", out)
+	}
+
+	var doc struct {
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Content struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+
+	if doc.Updated != "2025-03-01T11:00:00Z" {
+		t.Errorf("updated = %q", doc.Updated)
+	}
+	if len(doc.Entries) != 1 || doc.Entries[0].Content.Type != "html" || doc.Entries[0].Content.Value != "<p>Hello &amp; welcome</p>" {
+		t.Errorf("unexpected entries: %+v", doc.Entries)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	out, err := testFeed().JSON()
+	if err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+
+	var doc struct {
+		Version string `json:"version"`
+		Items   []struct {
+			ID            string   `json:"id"`
+			DatePublished string   `json:"date_published"`
+			Tags          []string `json:"tags"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if doc.Version != "https://jsonfeed.org/version/1.1" {
+		t.Errorf("version = %q", doc.Version)
+	}
+	if len(doc.Items) != 1 || doc.Items[0].DatePublished != "2025-03-01T10:00:00Z" || len(doc.Items[0].Tags) != 2 {
+		t.Errorf("unexpected items: %+v", doc.Items)
+	}
+}
+
+func TestEmptyFeed(t *testing.T) {
+	f := &feed.Feed{Title: "Empty", Updated: time.Unix(0, 0)}
+	out, err := f.JSON()
+	if err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	if !strings.Contains(string(out), `"items": []`) {
+		t.Errorf("empty feeds must still list items:\n%s", out)
+	}
+}
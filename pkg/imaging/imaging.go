@@ -0,0 +1,199 @@
+// Package imaging decodes, orients, resizes and re-encodes images. Re-encoding
+// writes pixels alone, so EXIF and other metadata of the source never reach
+// the output.
+//
+// JPEG, PNG, GIF and WebP sources are decoded, WebP through
+// golang.org/x/image. JPEG, PNG and lossless WebP are written, WebP by the
+// small encoder of this package.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	_ "image/gif" // registers the GIF decoder
+
+	_ "golang.org/x/image/webp" // registers the WebP decoder
+)
+
+// Output formats
+const (
+	JPEG = "jpeg"
+	PNG  = "png"
+	WebP = "webp"
+)
+
+// MaxPixels is the largest image Decode accepts, which keeps small files
+// declaring huge dimensions from exhausting memory
+const MaxPixels = 50_000_000
+
+var (
+	// ErrUnsupportedFormat is returned for output formats that cannot be encoded
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	// ErrTooLarge is returned for images with more than MaxPixels pixels
+	ErrTooLarge = errors.New("image is too large")
+)
+
+// Supported reports whether images can be encoded in format
+func Supported(format string) bool {
+	return format == JPEG || format == PNG || format == WebP
+}
+
+// ContentType returns the media type of format
+func ContentType(format string) string {
+	return "image/" + format
+}
+
+// Extension returns the file extension of format
+func Extension(format string) string {
+	if format == JPEG {
+		return ".jpg"
+	}
+	return "." + format
+}
+
+// Decode reads an image and turns it upright according to its EXIF
+// orientation. The data is read fully, since the orientation is stored
+// ahead of the pixels.
+func Decode(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooLarge, cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return Orient(img, Orientation(data)), nil
+}
+
+// Encode writes img in format. JPEG has no transparency, so transparent
+// areas are flattened onto white. Quality only applies to JPEG; WebP is
+// written lossless.
+func Encode(w io.Writer, img image.Image, format string, quality int) error {
+	switch format {
+	case JPEG:
+		flat := image.NewRGBA(img.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+		return jpeg.Encode(w, flat, &jpeg.Options{Quality: quality})
+	case PNG:
+		encoder := png.Encoder{CompressionLevel: png.DefaultCompression}
+		return encoder.Encode(w, img)
+	case WebP:
+		return encodeWebP(w, img)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// Resize scales img to width pixels wide, keeping its aspect ratio. Each
+// output pixel is the area-weighted average of the source pixels it covers,
+// which keeps downscaled images free of aliasing.
+func Resize(img image.Image, width int) *image.RGBA {
+	bounds := img.Bounds()
+	if width <= 0 || bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return image.NewRGBA(image.Rect(0, 0, 0, 0))
+	}
+	height := max(bounds.Dy()*width/bounds.Dx(), 1)
+
+	// Averaging premultiplied colors keeps transparent pixels from
+	// darkening their neighbours
+	src := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	horizontal := resampleRows(src, width)
+	return resampleColumns(horizontal, height)
+}
+
+// contribution is the weight of one source pixel in an output pixel
+type contribution struct {
+	index  int
+	weight float64
+}
+
+// weights returns, for each of the dst output pixels, the source pixels it
+// covers out of src and how much of each it covers
+func weights(src, dst int) [][]contribution {
+	scale := float64(src) / float64(dst)
+	result := make([][]contribution, dst)
+
+	for i := range result {
+		start := float64(i) * scale
+		end := start + scale
+		for j := int(start); j < src && float64(j) < end; j++ {
+			overlap := min(end, float64(j+1)) - max(start, float64(j))
+			if overlap > 0 {
+				result[i] = append(result[i], contribution{index: j, weight: overlap / scale})
+			}
+		}
+	}
+
+	return result
+}
+
+func resampleRows(src *image.RGBA, width int) *image.RGBA {
+	height := src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	columns := weights(src.Bounds().Dx(), width)
+
+	for y := 0; y < height; y++ {
+		for x, contributions := range columns {
+			var pixel [4]float64
+			for _, c := range contributions {
+				offset := src.PixOffset(c.index, y)
+				for k := range pixel {
+					pixel[k] += float64(src.Pix[offset+k]) * c.weight
+				}
+			}
+			setPixel(dst, x, y, pixel)
+		}
+	}
+
+	return dst
+}
+
+func resampleColumns(src *image.RGBA, height int) *image.RGBA {
+	width := src.Bounds().Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	rows := weights(src.Bounds().Dy(), height)
+
+	for y, contributions := range rows {
+		for x := 0; x < width; x++ {
+			var pixel [4]float64
+			for _, c := range contributions {
+				offset := src.PixOffset(x, c.index)
+				for k := range pixel {
+					pixel[k] += float64(src.Pix[offset+k]) * c.weight
+				}
+			}
+			setPixel(dst, x, y, pixel)
+		}
+	}
+
+	return dst
+}
+
+func setPixel(img *image.RGBA, x, y int, pixel [4]float64) {
+	offset := img.PixOffset(x, y)
+	for k, v := range pixel {
+		img.Pix[offset+k] = uint8(min(max(v+0.5, 0), 255))
+	}
+}
@@ -0,0 +1,263 @@
+package imaging_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/yakuter/ugin/pkg/imaging"
+)
+
+// withOrientation inserts an APP1 Exif segment with the given orientation
+// right after the SOI marker of a JPEG
+func withOrientation(t *testing.T, data []byte, order binary.ByteOrder, orientation uint16) []byte {
+	t.Helper()
+
+	var tiff bytes.Buffer
+	if order == binary.LittleEndian {
+		tiff.WriteString("II")
+	} else {
+		tiff.WriteString("MM")
+	}
+	binary.Write(&tiff, order, uint16(42))
+	binary.Write(&tiff, order, uint32(8))
+	binary.Write(&tiff, order, uint16(1))
+	binary.Write(&tiff, order, uint16(0x0112))
+	binary.Write(&tiff, order, uint16(3))
+	binary.Write(&tiff, order, uint32(1))
+	binary.Write(&tiff, order, orientation)
+	binary.Write(&tiff, order, uint16(0))
+	binary.Write(&tiff, order, uint32(0))
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	var out bytes.Buffer
+	out.Write(data[:2])
+	out.Write([]byte{0xFF, 0xE1})
+	binary.Write(&out, binary.BigEndian, uint16(len(segment)+2))
+	out.Write(segment)
+	out.Write(data[2:])
+	return out.Bytes()
+}
+
+func TestOrientation(t *testing.T) {
+	var plain bytes.Buffer
+	if err := jpeg.Encode(&plain, image.NewGray(image.Rect(0, 0, 4, 2)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"no exif", plain.Bytes(), 1},
+		{"little endian", withOrientation(t, plain.Bytes(), binary.LittleEndian, 6), 6},
+		{"big endian", withOrientation(t, plain.Bytes(), binary.BigEndian, 3), 3},
+		{"out of range", withOrientation(t, plain.Bytes(), binary.BigEndian, 9), 1},
+		{"not a jpeg", []byte("\x89PNG\r\n\x1a\n"), 1},
+		{"truncated", withOrientation(t, plain.Bytes(), binary.BigEndian, 6)[:12], 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := imaging.Orientation(tt.data); got != tt.want {
+				t.Errorf("Orientation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A 2x1 image: red on the left, blue on the right
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	src.Set(0, 0, red)
+	src.Set(1, 0, blue)
+
+	tests := []struct {
+		orientation int
+		size        image.Point
+		red         image.Point
+	}{
+		{1, image.Pt(2, 1), image.Pt(0, 0)},
+		{2, image.Pt(2, 1), image.Pt(1, 0)},
+		{3, image.Pt(2, 1), image.Pt(1, 0)},
+		{4, image.Pt(2, 1), image.Pt(0, 0)},
+		{5, image.Pt(1, 2), image.Pt(0, 0)},
+		{6, image.Pt(1, 2), image.Pt(0, 0)},
+		{7, image.Pt(1, 2), image.Pt(0, 1)},
+		{8, image.Pt(1, 2), image.Pt(0, 1)},
+	}
+
+	for _, tt := range tests {
+		got := imaging.Orient(src, tt.orientation)
+		if size := got.Bounds().Size(); size != tt.size {
+			t.Errorf("orientation %d: size = %v, want %v", tt.orientation, size, tt.size)
+			continue
+		}
+		if c := color.RGBAModel.Convert(got.At(tt.red.X, tt.red.Y)); c != red {
+			t.Errorf("orientation %d: pixel at %v = %v, want red", tt.orientation, tt.red, c)
+		}
+	}
+}
+
+func TestResize(t *testing.T) {
+	// Alternating black and white columns average to grey
+	src := image.NewRGBA(image.Rect(0, 0, 8, 4))
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 4; y++ {
+			if x%2 == 0 {
+				src.Set(x, y, color.White)
+			} else {
+				src.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	got := imaging.Resize(src, 4)
+	if size := got.Bounds().Size(); size != image.Pt(4, 2) {
+		t.Fatalf("size = %v, want 4x2", size)
+	}
+	if c := got.RGBAAt(1, 1); c.R != 128 || c.A != 255 {
+		t.Errorf("pixel = %v, want opaque grey", c)
+	}
+
+	// Non-integer scale factors keep every output pixel covered
+	got = imaging.Resize(src, 3)
+	if size := got.Bounds().Size(); size != image.Pt(3, 1) {
+		t.Fatalf("size = %v, want 3x1", size)
+	}
+	for x := 0; x < 3; x++ {
+		if c := got.RGBAAt(x, 0); c.A != 255 {
+			t.Errorf("pixel %d alpha = %d, want 255", x, c.A)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 3))
+
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, src, imaging.JPEG, 80); err != nil {
+		t.Fatal(err)
+	}
+	img, err := jpeg.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Transparent pixels are flattened onto white
+	if r, _, _, _ := img.At(1, 1).RGBA(); r < 0xF000 {
+		t.Errorf("flattened pixel red = %#x, want white", r)
+	}
+
+	buf.Reset()
+	if err := imaging.Encode(&buf, src, imaging.PNG, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := png.Decode(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := imaging.Encode(&buf, src, "gif", 80); err == nil {
+		t.Error("expected an error for gif")
+	}
+}
+
+func TestEncodeWebP(t *testing.T) {
+	// A gradient with every alpha, a two-color image and a single pixel
+	// cover normal codes, simple codes and zero-bit codes
+	gradient := image.NewNRGBA(image.Rect(0, 0, 300, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 300; x++ {
+			gradient.SetNRGBA(x, y, color.NRGBA{uint8(x), uint8(y), uint8(x * y), uint8(x + y)})
+		}
+	}
+	stripes := image.NewNRGBA(image.Rect(0, 0, 7, 5))
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 7; x++ {
+			c := color.NRGBA{200, 30, 90, 255}
+			if x%2 == 0 {
+				c = color.NRGBA{10, 20, 30, 255}
+			}
+			stripes.SetNRGBA(x, y, c)
+		}
+	}
+	pixel := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	pixel.SetNRGBA(0, 0, color.NRGBA{1, 2, 3, 4})
+
+	tests := []struct {
+		name string
+		img  *image.NRGBA
+	}{
+		{"gradient", gradient},
+		{"stripes", stripes},
+		{"single pixel", pixel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := imaging.Encode(&buf, tt.img, imaging.WebP, 80); err != nil {
+				t.Fatal(err)
+			}
+			img, format, err := image.Decode(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != "webp" {
+				t.Fatalf("format = %q, want webp", format)
+			}
+
+			// Lossless: every pixel comes back unchanged
+			bounds := tt.img.Bounds()
+			if img.Bounds() != bounds {
+				t.Fatalf("bounds = %v, want %v", img.Bounds(), bounds)
+			}
+			for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+				for x := bounds.Min.X; x < bounds.Max.X; x++ {
+					got := color.NRGBAModel.Convert(img.At(x, y))
+					if want := tt.img.NRGBAAt(x, y); got != want {
+						t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeAppliesOrientation(t *testing.T) {
+	var plain bytes.Buffer
+	if err := jpeg.Encode(&plain, image.NewGray(image.Rect(0, 0, 16, 8)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(withOrientation(t, plain.Bytes(), binary.BigEndian, 6)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size != image.Pt(8, 16) {
+		t.Errorf("size = %v, want 8x16", size)
+	}
+}
+
+func TestDecodeWebP(t *testing.T) {
+	// A 1x1 lossless WebP image
+	data, err := base64.StdEncoding.DecodeString("UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA==")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size != image.Pt(1, 1) {
+		t.Errorf("size = %v, want 1x1", size)
+	}
+}
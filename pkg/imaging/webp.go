@@ -0,0 +1,335 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"sort"
+)
+
+// maxWebPSize is the largest width and height a WebP image may have
+const maxWebPSize = 1 << 14
+
+// VP8L alphabet sizes: green also holds the 24 backward reference length
+// codes, which this encoder never uses
+const (
+	greenAlphabet    = 256 + 24
+	channelAlphabet  = 256
+	distanceAlphabet = 40
+)
+
+// maxCodeLength bounds the prefix codes of pixels and maxLengthCodeLength
+// the code that writes their code lengths
+const (
+	maxCodeLength       = 15
+	maxLengthCodeLength = 7
+)
+
+// lengthCodeOrder is the order code length code lengths are written in
+var lengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// encodeWebP writes img as a lossless WebP (VP8L) image. Each pixel is
+// written as literal prefix codes after the subtract-green transform,
+// without backward references or a color cache, which keeps the encoder
+// small at the cost of larger files than libwebp makes.
+func encodeWebP(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 || width > maxWebPSize || height > maxWebPSize {
+		return fmt.Errorf("%w: webp cannot hold a %dx%d image", ErrUnsupportedFormat, width, height)
+	}
+
+	// WebP stores colors that are not premultiplied by alpha
+	src := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	var histograms [4][]int
+	histograms[0] = make([]int, greenAlphabet)
+	for i := 1; i < 4; i++ {
+		histograms[i] = make([]int, channelAlphabet)
+	}
+	alpha := false
+	for i := 0; i < len(src.Pix); i += 4 {
+		r, g, b, a := src.Pix[i], src.Pix[i+1], src.Pix[i+2], src.Pix[i+3]
+		// Subtract green: red and blue are stored as differences to green
+		src.Pix[i], src.Pix[i+2] = r-g, b-g
+		histograms[0][g]++
+		histograms[1][r-g]++
+		histograms[2][b-g]++
+		histograms[3][a]++
+		alpha = alpha || a != 0xff
+	}
+
+	var bw bitWriter
+	bw.write(0x2f, 8) // signature
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	if alpha {
+		bw.write(1, 1)
+	} else {
+		bw.write(0, 1)
+	}
+	bw.write(0, 3) // version
+
+	bw.write(1, 1) // a transform follows
+	bw.write(2, 2) // subtract green
+	bw.write(0, 1) // no more transforms
+	bw.write(0, 1) // no color cache
+	bw.write(0, 1) // a single set of prefix codes
+
+	// Green, red, blue and alpha, then the unused distance code
+	var codes [4]prefixCode
+	for i, histogram := range histograms {
+		codes[i] = writePrefixCode(&bw, histogram)
+	}
+	writePrefixCode(&bw, make([]int, distanceAlphabet))
+
+	for i := 0; i < len(src.Pix); i += 4 {
+		codes[0].write(&bw, src.Pix[i+1])
+		codes[1].write(&bw, src.Pix[i])
+		codes[2].write(&bw, src.Pix[i+2])
+		codes[3].write(&bw, src.Pix[i+3])
+	}
+	data := bw.bytes()
+
+	// RIFF chunks are padded to an even size
+	padding := len(data) & 1
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+8+len(data)+padding))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(data)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if padding > 0 {
+		_, err := w.Write([]byte{0})
+		return err
+	}
+	return nil
+}
+
+// bitWriter packs values into bytes least significant bit first, as VP8L
+// reads them
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits uint
+}
+
+func (w *bitWriter) write(value uint32, n uint) {
+	w.acc |= uint64(value) << w.nbits
+	w.nbits += n
+	for w.nbits >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.nbits -= 8
+	}
+}
+
+// bytes returns what was written, the last byte padded with zero bits
+func (w *bitWriter) bytes() []byte {
+	if w.nbits > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.nbits = 0, 0
+	}
+	return w.buf
+}
+
+// prefixCode is a canonical prefix code. Codes are stored bit-reversed, so
+// they are written first bit first.
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint16
+}
+
+func (c prefixCode) write(w *bitWriter, symbol uint8) {
+	w.write(uint32(c.codes[symbol]), uint(c.lengths[symbol]))
+}
+
+// writePrefixCode writes the code fitting histogram and returns it. Up to
+// two symbols below 256 are written as a simple code, the rest as code
+// lengths.
+func writePrefixCode(w *bitWriter, histogram []int) prefixCode {
+	var used []int
+	for symbol, count := range histogram {
+		if count > 0 {
+			used = append(used, symbol)
+		}
+	}
+
+	lengths := make([]uint8, len(histogram))
+	if len(used) <= 2 && (len(used) == 0 || used[len(used)-1] < 256) {
+		if len(used) == 0 {
+			used = []int{0}
+		}
+		w.write(1, 1) // simple code
+		w.write(uint32(len(used)-1), 1)
+		if used[0] < 2 {
+			w.write(0, 1)
+			w.write(uint32(used[0]), 1)
+		} else {
+			w.write(1, 1)
+			w.write(uint32(used[0]), 8)
+		}
+		// A single symbol takes no bits at all
+		if len(used) == 2 {
+			w.write(uint32(used[1]), 8)
+			lengths[used[0]], lengths[used[1]] = 1, 1
+		}
+		return prefixCode{lengths: lengths, codes: canonicalCodes(lengths)}
+	}
+
+	lengths = huffmanLengths(histogram, maxCodeLength)
+	w.write(0, 1) // normal code
+	writeCodeLengths(w, lengths)
+	return prefixCode{lengths: lengths, codes: canonicalCodes(lengths)}
+}
+
+// writeCodeLengths writes lengths with a prefix code of its own, without
+// the run-length codes 16 to 18
+func writeCodeLengths(w *bitWriter, lengths []uint8) {
+	histogram := make([]int, len(lengthCodeOrder))
+	for _, length := range lengths {
+		histogram[length]++
+	}
+	lengthLengths := huffmanLengths(histogram, maxLengthCodeLength)
+	lengthCodes := canonicalCodes(lengthLengths)
+
+	count := 4
+	for i, symbol := range lengthCodeOrder {
+		if lengthLengths[symbol] > 0 {
+			count = max(count, i+1)
+		}
+	}
+	w.write(uint32(count-4), 4)
+	for _, symbol := range lengthCodeOrder[:count] {
+		w.write(uint32(lengthLengths[symbol]), 3)
+	}
+
+	w.write(0, 1) // lengths follow for the whole alphabet
+	for _, length := range lengths {
+		w.write(uint32(lengthCodes[length]), uint(lengthLengths[length]))
+	}
+}
+
+// huffmanLengths returns the code lengths of a Huffman code for histogram
+// no longer than limit. Counts are halved until the code fits. Decoders
+// only accept complete codes, so at least two symbols get a length.
+func huffmanLengths(histogram []int, limit int) []uint8 {
+	counts := append([]int(nil), histogram...)
+	used := 0
+	for _, count := range counts {
+		if count > 0 {
+			used++
+		}
+	}
+	for i := range counts {
+		if used >= 2 {
+			break
+		}
+		if counts[i] == 0 {
+			counts[i] = 1
+			used++
+		}
+	}
+
+	for {
+		if lengths, ok := buildLengths(counts, limit); ok {
+			return lengths
+		}
+		for i, count := range counts {
+			if count > 0 {
+				counts[i] = (count + 1) / 2
+			}
+		}
+	}
+}
+
+type huffmanNode struct {
+	weight      int
+	symbol      int
+	left, right *huffmanNode
+}
+
+// buildLengths builds a Huffman tree over the symbols with a count and
+// reports whether its depth stays within limit
+func buildLengths(counts []int, limit int) ([]uint8, bool) {
+	var leaves []*huffmanNode
+	for symbol, count := range counts {
+		if count > 0 {
+			leaves = append(leaves, &huffmanNode{weight: count, symbol: symbol})
+		}
+	}
+	sort.SliceStable(leaves, func(i, j int) bool { return leaves[i].weight < leaves[j].weight })
+
+	// Merged nodes are made in order of weight, so two queues replace a heap
+	var merged []*huffmanNode
+	pop := func() *huffmanNode {
+		var node *huffmanNode
+		if len(merged) == 0 || (len(leaves) > 0 && leaves[0].weight <= merged[0].weight) {
+			node, leaves = leaves[0], leaves[1:]
+		} else {
+			node, merged = merged[0], merged[1:]
+		}
+		return node
+	}
+	for len(leaves)+len(merged) > 1 {
+		a, b := pop(), pop()
+		merged = append(merged, &huffmanNode{weight: a.weight + b.weight, left: a, right: b})
+	}
+
+	lengths := make([]uint8, len(counts))
+	ok := true
+	var walk func(node *huffmanNode, depth int)
+	walk = func(node *huffmanNode, depth int) {
+		if node.left == nil {
+			ok = ok && depth <= limit
+			lengths[node.symbol] = uint8(depth)
+			return
+		}
+		walk(node.left, depth+1)
+		walk(node.right, depth+1)
+	}
+	walk(pop(), 0)
+
+	return lengths, ok
+}
+
+// canonicalCodes assigns codes to lengths in order of length, then symbol,
+// and returns them bit-reversed
+func canonicalCodes(lengths []uint8) []uint16 {
+	var counts [maxCodeLength + 1]int
+	for _, length := range lengths {
+		if length > 0 {
+			counts[length]++
+		}
+	}
+
+	var next [maxCodeLength + 1]int
+	code := 0
+	for length := 1; length <= maxCodeLength; length++ {
+		code = (code + counts[length-1]) << 1
+		next[length] = code
+	}
+
+	codes := make([]uint16, len(lengths))
+	for symbol, length := range lengths {
+		if length == 0 {
+			continue
+		}
+		code := next[length]
+		next[length]++
+		var reversed uint16
+		for i := uint8(0); i < length; i++ {
+			reversed = reversed<<1 | uint16(code>>i&1)
+		}
+		codes[symbol] = reversed
+	}
+	return codes
+}
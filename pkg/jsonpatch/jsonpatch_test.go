@@ -0,0 +1,156 @@
+package jsonpatch_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yakuter/ugin/pkg/jsonpatch"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{
+			name:  "replace field",
+			doc:   `{"a":"b"}`,
+			patch: `{"a":"c"}`,
+			want:  `{"a":"c"}`,
+		},
+		{
+			name:  "null removes field",
+			doc:   `{"a":"b","c":"d"}`,
+			patch: `{"a":null}`,
+			want:  `{"c":"d"}`,
+		},
+		{
+			name:  "nested objects merge",
+			doc:   `{"a":{"b":"c","d":"e"}}`,
+			patch: `{"a":{"d":null,"f":"g"}}`,
+			want:  `{"a":{"b":"c","f":"g"}}`,
+		},
+		{
+			name:  "arrays are replaced",
+			doc:   `{"a":[1,2]}`,
+			patch: `{"a":[3]}`,
+			want:  `{"a":[3]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonpatch.MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "add to object",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux"}]`,
+			want:  `{"foo":"bar","baz":"qux"}`,
+		},
+		{
+			name:  "insert into array",
+			doc:   `{"foo":["bar","baz"]}`,
+			patch: `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			want:  `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			name:  "append to array",
+			doc:   `{"foo":["bar"]}`,
+			patch: `[{"op":"add","path":"/foo/-","value":"baz"}]`,
+			want:  `{"foo":["bar","baz"]}`,
+		},
+		{
+			name:  "remove array element",
+			doc:   `{"foo":["bar","qux","baz"]}`,
+			patch: `[{"op":"remove","path":"/foo/1"}]`,
+			want:  `{"foo":["bar","baz"]}`,
+		},
+		{
+			name:  "replace nested value",
+			doc:   `{"tags":[{"name":"go"}]}`,
+			patch: `[{"op":"replace","path":"/tags/0/name","value":"golang"}]`,
+			want:  `{"tags":[{"name":"golang"}]}`,
+		},
+		{
+			name:  "move and copy",
+			doc:   `{"a":{"b":1},"c":{}}`,
+			patch: `[{"op":"move","from":"/a/b","path":"/c/b"},{"op":"copy","from":"/c","path":"/d"}]`,
+			want:  `{"a":{},"c":{"b":1},"d":{"b":1}}`,
+		},
+		{
+			name:  "escaped pointer",
+			doc:   `{"a/b":1,"m~n":2}`,
+			patch: `[{"op":"remove","path":"/a~1b"},{"op":"replace","path":"/m~0n","value":3}]`,
+			want:  `{"m~n":3}`,
+		},
+		{
+			name:    "failed test aborts patch",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"test","path":"/foo","value":"baz"},{"op":"remove","path":"/foo"}]`,
+			wantErr: jsonpatch.ErrTestFailed,
+		},
+		{
+			name:    "replace missing path",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"replace","path":"/baz","value":1}]`,
+			wantErr: jsonpatch.ErrPathNotFound,
+		},
+		{
+			name:    "unknown operation",
+			doc:     `{}`,
+			patch:   `[{"op":"frobnicate","path":"/a"}]`,
+			wantErr: jsonpatch.ErrInvalidPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonpatch.Apply([]byte(tt.doc), []byte(tt.patch))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("invalid result %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("invalid expectation %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
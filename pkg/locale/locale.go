@@ -0,0 +1,140 @@
+// Package locale negotiates the language content is served in from the
+// locales a client asks for and the fallbacks configured between them.
+package locale
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Negotiator turns requested locales into an ordered chain of locales to try
+type Negotiator struct {
+	defaultLocale string
+	fallbacks     map[string][]string
+}
+
+// NewNegotiator creates a negotiator whose chains end in defaultLocale.
+// fallbacks maps a locale to the locales tried after it and its parents,
+// as in "pt-BR" falling back to "pt-PT".
+func NewNegotiator(defaultLocale string, fallbacks map[string][]string) (*Negotiator, error) {
+	def, err := Canonical(defaultLocale)
+	if err != nil {
+		return nil, fmt.Errorf("default locale: %w", err)
+	}
+
+	n := &Negotiator{defaultLocale: def, fallbacks: make(map[string][]string, len(fallbacks))}
+	for from, tos := range fallbacks {
+		key, err := Canonical(from)
+		if err != nil {
+			return nil, fmt.Errorf("fallback locale: %w", err)
+		}
+		for _, to := range tos {
+			value, err := Canonical(to)
+			if err != nil {
+				return nil, fmt.Errorf("fallback locale: %w", err)
+			}
+			n.fallbacks[key] = append(n.fallbacks[key], value)
+		}
+	}
+
+	return n, nil
+}
+
+// Default returns the locale chains end in
+func (n *Negotiator) Default() string {
+	return n.defaultLocale
+}
+
+// Chain returns the locales to try, most preferred first. An explicit
+// lang comes before the locales of the Accept-Language header; each is
+// followed by its parents ("de-AT", "de") and configured fallbacks. The
+// chain ends at the default locale. An invalid lang is an error, while an
+// invalid header is ignored.
+func (n *Negotiator) Chain(lang, acceptLanguage string) ([]string, error) {
+	var requested []string
+
+	if lang != "" {
+		tag, err := Canonical(lang)
+		if err != nil {
+			return nil, err
+		}
+		requested = append(requested, tag)
+	}
+
+	if acceptLanguage != "" {
+		tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+		if err == nil {
+			for _, tag := range tags {
+				// The "*" wildcard is parsed as "mul" and matches nothing in particular
+				if tag == language.Und || tag == language.Make("mul") {
+					continue
+				}
+				requested = append(requested, canonicalTag(tag))
+			}
+		}
+	}
+
+	chain := make([]string, 0, len(requested)*2+1)
+	seen := make(map[string]bool)
+	done := false
+
+	// add appends tag, its fallbacks and then its parents, stopping for
+	// good once the default locale is reached
+	var add func(tag string)
+	add = func(tag string) {
+		for ; tag != "" && !done; tag = parent(tag) {
+			if seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			chain = append(chain, tag)
+			if tag == n.defaultLocale {
+				done = true
+				return
+			}
+			for _, fallback := range n.fallbacks[tag] {
+				add(fallback)
+			}
+		}
+	}
+
+	for _, tag := range requested {
+		add(tag)
+	}
+	if !done {
+		chain = append(chain, n.defaultLocale)
+	}
+
+	return chain, nil
+}
+
+// Canonical returns locale as a canonical BCP 47 tag of its language,
+// script and region, as in "pt-BR" for "pt_br"
+func Canonical(locale string) (string, error) {
+	tag, err := language.Parse(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if err != nil || tag == language.Und {
+		return "", fmt.Errorf("invalid locale %q", locale)
+	}
+	return canonicalTag(tag), nil
+}
+
+// canonicalTag drops variants and extensions from tag
+func canonicalTag(tag language.Tag) string {
+	base, script, region := tag.Raw()
+	composed, err := language.Compose(base, script, region)
+	if err != nil {
+		return tag.String()
+	}
+	return composed.String()
+}
+
+// parent returns tag without its last subtag, or an empty string for a bare language
+func parent(tag string) string {
+	i := strings.LastIndexByte(tag, '-')
+	if i < 0 {
+		return ""
+	}
+	return tag[:i]
+}
@@ -0,0 +1,88 @@
+package locale_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yakuter/ugin/pkg/locale"
+)
+
+func TestChain(t *testing.T) {
+	n, err := locale.NewNegotiator("en", map[string][]string{
+		"pt-br": {"pt-PT"},
+		"gsw":   {"de"},
+	})
+	if err != nil {
+		t.Fatalf("NewNegotiator: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		lang   string
+		accept string
+		want   []string
+	}{
+		{"nothing requested", "", "", []string{"en"}},
+		{"parent added", "de-AT", "", []string{"de-AT", "de", "en"}},
+		{"header order", "", "fr-CH, de;q=0.9, *;q=0.5", []string{"fr-CH", "fr", "de", "en"}},
+		{"lang before header", "it", "de", []string{"it", "de", "en"}},
+		{"stops at default", "", "fr, en;q=0.8, de;q=0.5", []string{"fr", "en"}},
+		{"fallbacks", "pt_BR", "", []string{"pt-BR", "pt-PT", "pt", "en"}},
+		{"nested fallback", "gsw", "", []string{"gsw", "de", "en"}},
+		{"zero weight dropped", "", "de;q=0, fr", []string{"fr", "en"}},
+		{"invalid header ignored", "", ";;;q=x", []string{"en"}},
+		{"duplicates dropped", "de", "de-DE, de", []string{"de", "de-DE", "en"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := n.Chain(tt.lang, tt.accept)
+			if err != nil {
+				t.Fatalf("Chain: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Chain(%q, %q) = %v, want %v", tt.lang, tt.accept, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChainInvalidLang(t *testing.T) {
+	n, err := locale.NewNegotiator("en", nil)
+	if err != nil {
+		t.Fatalf("NewNegotiator: %v", err)
+	}
+	if _, err := n.Chain("not a locale", ""); err == nil {
+		t.Error("expected an error for an invalid lang")
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"en", "en"},
+		{"pt_br", "pt-BR"},
+		{"ZH-hant-tw", "zh-Hant-TW"},
+		{"de-DE-u-co-phonebk", "de-DE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := locale.Canonical(tt.in)
+			if err != nil {
+				t.Fatalf("Canonical(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("Canonical(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	for _, in := range []string{"", "und", "x"} {
+		if _, err := locale.Canonical(in); err == nil {
+			t.Errorf("Canonical(%q): expected an error", in)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package markup
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	autolink  = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^<>\s]*)>`)
+	emailLink = regexp.MustCompile(`^<([A-Za-z0-9.!#$%&'*+/=?^_{|}~-]+@[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?(?:\.[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*)>`)
+)
+
+// renderInline renders the inline elements of a block's text
+func renderInline(s string) string {
+	var out strings.Builder
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case strings.HasPrefix(s[i:], hardBreak):
+			out.WriteString("<br>\n")
+			i += len(hardBreak)
+			continue
+
+		case c == '\\' && i+1 < len(s) && isPunct(s[i+1]):
+			out.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			if code, next, ok := codeSpan(s, i); ok {
+				out.WriteString(code)
+				i = next
+				continue
+			}
+			// An unmatched backtick run is literal as a whole
+			n := runLength(s, i, '`')
+			out.WriteString(s[i : i+n])
+			i += n
+			continue
+
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			if text, dest, title, next, ok := linkAt(s, i+1); ok {
+				out.WriteString(image(text, dest, title))
+				i = next
+				continue
+			}
+
+		case c == '[':
+			if text, dest, title, next, ok := linkAt(s, i); ok {
+				out.WriteString(link(renderInline(text), dest, title))
+				i = next
+				continue
+			}
+
+		case c == '<':
+			if m := autolink.FindStringSubmatch(s[i:]); m != nil && safeURL(m[1]) {
+				out.WriteString(link(html.EscapeString(m[1]), m[1], ""))
+				i += len(m[0])
+				continue
+			}
+			if m := emailLink.FindStringSubmatch(s[i:]); m != nil {
+				out.WriteString(link(html.EscapeString(m[1]), "mailto:"+m[1], ""))
+				i += len(m[0])
+				continue
+			}
+
+		case c == '*' || c == '_' || (c == '~' && i+1 < len(s) && s[i+1] == '~'):
+			if rendered, next, ok := emphasis(s, i); ok {
+				out.WriteString(rendered)
+				i = next
+				continue
+			}
+			n := runLength(s, i, c)
+			out.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+
+		out.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+
+	return out.String()
+}
+
+// codeSpan renders the code span opened by the backtick run at s[i]
+func codeSpan(s string, i int) (string, int, bool) {
+	n := runLength(s, i, '`')
+
+	for j := i + n; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		m := runLength(s, j, '`')
+		if m == n {
+			code := strings.ReplaceAll(s[i+n:j], "\n", " ")
+			code = strings.ReplaceAll(code, hardBreak, " ")
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+				code = code[1 : len(code)-1]
+			}
+			return "<code>" + html.EscapeString(code) + "</code>", j + m, true
+		}
+		j += m
+	}
+
+	return "", 0, false
+}
+
+// emphasis renders the emphasis, strong emphasis or strikethrough opened
+// by the delimiter run at s[i]
+func emphasis(s string, i int) (string, int, bool) {
+	c := s[i]
+	run := runLength(s, i, c)
+
+	// Openers must be followed by text, and underscores must not be inside a word
+	if i+run >= len(s) || isSpace(s[i+run]) || (c == '_' && i > 0 && isAlnum(s[i-1])) {
+		return "", 0, false
+	}
+
+	var n int
+	var tag string
+	switch {
+	case c == '~':
+		n, tag = 2, "del"
+	case run >= 2:
+		n, tag = 2, "strong"
+	default:
+		n, tag = 1, "em"
+	}
+	if c == '~' && run != 2 {
+		return "", 0, false
+	}
+
+	end := closingDelimiter(s, i+n, c, n)
+	if end < 0 {
+		return "", 0, false
+	}
+
+	return "<" + tag + ">" + renderInline(s[i+n:end]) + "</" + tag + ">", end + n, true
+}
+
+// closingDelimiter returns the position of the n delimiter characters c
+// that close emphasis whose content starts at from, or -1. A closing run
+// has to follow text and be n long, or three long to close both emphasis
+// and strong emphasis.
+func closingDelimiter(s string, from int, c byte, n int) int {
+	for j := from; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+			continue
+		case '`':
+			if _, next, ok := codeSpan(s, j); ok {
+				j = next - 1
+			} else {
+				j += runLength(s, j, '`') - 1
+			}
+			continue
+		case c:
+		default:
+			continue
+		}
+
+		run := runLength(s, j, c)
+		end := j + run
+		closes := j > from && !isSpace(s[j-1]) && (run == n || (run == 3 && c != '~'))
+		if closes && (c != '_' || end == len(s) || !isAlnum(s[end])) {
+			return end - n
+		}
+		j = end - 1
+	}
+	return -1
+}
+
+// linkAt parses a link whose text starts with the bracket at s[i]
+func linkAt(s string, i int) (text, dest, title string, next int, ok bool) {
+	// Find the matching bracket, skipping escapes and code spans
+	depth := 0
+	end := -1
+	for j := i; j < len(s) && end < 0; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '`':
+			if _, after, ok := codeSpan(s, j); ok {
+				j = after - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				end = j
+			}
+		}
+	}
+	if end < 0 || end+1 >= len(s) || s[end+1] != '(' {
+		return "", "", "", 0, false
+	}
+
+	j := end + 2
+	j = skipSpace(s, j)
+
+	// Destination, either <...> or a run without spaces and with balanced parentheses
+	if j < len(s) && s[j] == '<' {
+		close := strings.IndexAny(s[j+1:], ">\n")
+		if close < 0 || s[j+1+close] != '>' {
+			return "", "", "", 0, false
+		}
+		dest = s[j+1 : j+1+close]
+		j += close + 2
+	} else {
+		start, parens := j, 0
+		for ; j < len(s) && !isSpace(s[j]); j++ {
+			if s[j] == '\\' && j+1 < len(s) {
+				j++
+			} else if s[j] == '(' {
+				parens++
+			} else if s[j] == ')' {
+				if parens == 0 {
+					break
+				}
+				parens--
+			}
+		}
+		dest = s[start:j]
+	}
+
+	j = skipSpace(s, j)
+	if j < len(s) && (s[j] == '"' || s[j] == '\'' || s[j] == '(') {
+		closer := s[j]
+		if closer == '(' {
+			closer = ')'
+		}
+		close := strings.IndexByte(s[j+1:], closer)
+		if close < 0 {
+			return "", "", "", 0, false
+		}
+		title = s[j+1 : j+1+close]
+		j = skipSpace(s, j+close+2)
+	}
+
+	if j >= len(s) || s[j] != ')' {
+		return "", "", "", 0, false
+	}
+
+	return s[i+1 : end], unescape(dest), unescape(title), j + 1, true
+}
+
+// link renders an anchor around already rendered content. Destinations
+// with unsafe schemes leave the content unlinked.
+func link(content, dest, title string) string {
+	if !safeURL(dest) {
+		return content
+	}
+	a := `<a href="` + html.EscapeString(dest) + `"`
+	if title != "" {
+		a += ` title="` + html.EscapeString(title) + `"`
+	}
+	return a + ">" + content + "</a>"
+}
+
+// image renders an image; its alt text is the plain text of the link text
+func image(text, src, title string) string {
+	alt := html.EscapeString(plainText(text))
+	if !safeURL(src) {
+		return alt
+	}
+	img := `<img src="` + html.EscapeString(src) + `" alt="` + alt + `"`
+	if title != "" {
+		img += ` title="` + html.EscapeString(title) + `"`
+	}
+	return img + ">"
+}
+
+// plainText strips the inline markup from s
+func plainText(s string) string {
+	return strings.NewReplacer("*", "", "_", "", "`", "", "~~", "", "[", "", "]", "", hardBreak, " ").Replace(unescape(s))
+}
+
+// unescape resolves backslash escapes
+func unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isPunct(s[i+1]) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func runLength(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+func skipSpace(s string, i int) int {
+	for i < len(s) && isSpace(s[i]) {
+		i++
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == 0
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+func isPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
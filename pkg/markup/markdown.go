@@ -0,0 +1,290 @@
+// Package markup renders user-written text to HTML that is safe to embed
+// in a page: Markdown, plain text, and HTML reduced to an allowlist.
+package markup
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	atxHeading  = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	thematic    = regexp.MustCompile(`^ {0,3}((?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	fenceOpen   = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`\\s]*)")
+	listItem    = regexp.MustCompile(`^( {0,3})([-*+]|[0-9]{1,9}[.)])([ \t]+|$)`)
+	blockquote  = regexp.MustCompile(`^ {0,3}> ?`)
+	indentedRow = regexp.MustCompile(`^(?: {4}|\t)`)
+)
+
+// Markdown renders a CommonMark subset to HTML: headings, paragraphs,
+// emphasis, strikethrough, inline and fenced code, block quotes, nested
+// lists, links, images, autolinks and thematic breaks. Raw HTML in the
+// source is shown as text, not interpreted; pass the result through
+// Sanitize before trusting its URLs.
+func Markdown(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	src = strings.ReplaceAll(src, "\x00", "\uFFFD")
+
+	var out strings.Builder
+	renderBlocks(&out, strings.Split(src, "\n"))
+	return out.String()
+}
+
+// Text renders plain text as HTML paragraphs, keeping its line breaks
+func Text(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+
+	var out strings.Builder
+	for _, para := range strings.Split(src, "\n\n") {
+		para = strings.Trim(para, "\n")
+		if strings.TrimSpace(para) == "" {
+			continue
+		}
+		out.WriteString("<p>" + strings.ReplaceAll(html.EscapeString(para), "\n", "<br>\n") + "</p>\n")
+	}
+	return out.String()
+}
+
+// renderBlocks renders lines as a sequence of block elements
+func renderBlocks(out *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case fenceOpen.MatchString(line):
+			i = renderFence(out, lines, i)
+
+		case atxHeading.MatchString(line):
+			m := atxHeading.FindStringSubmatch(line)
+			level := strconv.Itoa(len(m[1]))
+			out.WriteString("<h" + level + ">" + renderInline(strings.TrimSpace(m[2])) + "</h" + level + ">\n")
+			i++
+
+		case thematic.MatchString(line):
+			out.WriteString("<hr>\n")
+			i++
+
+		case blockquote.MatchString(line):
+			var quoted []string
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+				quoted = append(quoted, blockquote.ReplaceAllString(lines[i], ""))
+			}
+			out.WriteString("<blockquote>\n")
+			renderBlocks(out, quoted)
+			out.WriteString("</blockquote>\n")
+
+		case listItem.MatchString(line):
+			i = renderList(out, lines, i)
+
+		case indentedRow.MatchString(line):
+			var code []string
+			for ; i < len(lines) && (indentedRow.MatchString(lines[i]) || strings.TrimSpace(lines[i]) == ""); i++ {
+				code = append(code, indentedRow.ReplaceAllString(lines[i], ""))
+			}
+			for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+				code = code[:len(code)-1]
+			}
+			out.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "\n</code></pre>\n")
+
+		default:
+			var para []string
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != "" && (len(para) == 0 || !startsBlock(lines[i])); i++ {
+				para = append(para, lines[i])
+			}
+			out.WriteString("<p>" + renderInline(joinLines(para)) + "</p>\n")
+		}
+	}
+}
+
+// startsBlock reports whether line interrupts a paragraph
+func startsBlock(line string) bool {
+	return fenceOpen.MatchString(line) || atxHeading.MatchString(line) ||
+		thematic.MatchString(line) || blockquote.MatchString(line) || listItem.MatchString(line)
+}
+
+// renderFence renders the fenced code block starting at lines[start] and
+// returns the index of the line after it
+func renderFence(out *strings.Builder, lines []string, start int) int {
+	m := fenceOpen.FindStringSubmatch(lines[start])
+	indent, fence, info := len(m[1]), m[2], m[3]
+
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimLeft(lines[i], " ")
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]+" \t") == "" {
+			i++
+			break
+		}
+		// Content lines lose up to the indentation of the opening fence
+		line := lines[i]
+		for n := 0; n < indent && strings.HasPrefix(line, " "); n++ {
+			line = line[1:]
+		}
+		code = append(code, line)
+	}
+
+	out.WriteString("<pre><code")
+	if info != "" {
+		out.WriteString(` class="language-` + html.EscapeString(info) + `"`)
+	}
+	out.WriteString(">")
+	if len(code) > 0 {
+		out.WriteString(html.EscapeString(strings.Join(code, "\n")) + "\n")
+	}
+	out.WriteString("</code></pre>\n")
+	return i
+}
+
+// renderList renders the list starting at lines[start] and returns the
+// index of the line after it. Lines indented past an item's marker belong
+// to that item, which is how lists nest.
+func renderList(out *strings.Builder, lines []string, start int) int {
+	first := listItem.FindStringSubmatch(lines[start])
+	ordered := first[2][0] >= '0' && first[2][0] <= '9'
+	delimiter := first[2][len(first[2])-1:]
+
+	var items [][]string
+	loose := false
+	i := start
+	for i < len(lines) {
+		m := listItem.FindStringSubmatch(lines[i])
+		if m == nil || (m[2][0] >= '0' && m[2][0] <= '9') != ordered || m[2][len(m[2])-1:] != delimiter {
+			break
+		}
+
+		// Continuation lines must be indented as far as the item's text
+		width := len(m[0])
+		if strings.TrimSpace(lines[i][width:]) == "" {
+			width = len(m[1]) + len(m[2]) + 1
+		}
+		item := []string{lines[i][len(m[0]):]}
+		i++
+
+		for i < len(lines) {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// A blank line continues the item only if it is followed by indented content
+				if i+1 < len(lines) && indentOf(lines[i+1]) >= width {
+					item = append(item, "")
+					loose = true
+					i++
+					continue
+				}
+				break
+			}
+			if indentOf(line) >= width {
+				item = append(item, dedent(line, width))
+				i++
+				continue
+			}
+			if listItem.MatchString(line) || startsBlock(line) {
+				break
+			}
+			// Lazy continuation of the item's paragraph
+			item = append(item, strings.TrimLeft(line, " \t"))
+			i++
+		}
+		items = append(items, item)
+
+		// Blank lines between items make the list loose
+		if i+1 < len(lines) && strings.TrimSpace(lines[i]) == "" && listItem.MatchString(lines[i+1]) {
+			loose = true
+			i++
+		}
+	}
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+		if n, _ := strconv.Atoi(strings.TrimRight(first[2], ".)")); n != 1 {
+			out.WriteString(`<ol start="` + strconv.Itoa(n) + `">` + "\n")
+		} else {
+			out.WriteString("<ol>\n")
+		}
+	} else {
+		out.WriteString("<ul>\n")
+	}
+
+	for _, item := range items {
+		var body strings.Builder
+		renderBlocks(&body, item)
+		content := body.String()
+		// Items of tight lists hold their text without a paragraph
+		if !loose {
+			content = tightParagraphs(content)
+		}
+		out.WriteString("<li>" + strings.TrimSuffix(content, "\n") + "</li>\n")
+	}
+
+	out.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// tightParagraphs removes the paragraph tags around the text of a tight list item
+func tightParagraphs(s string) string {
+	s = strings.ReplaceAll(s, "<p>", "")
+	return strings.ReplaceAll(s, "</p>\n", "\n")
+}
+
+func indentOf(line string) int {
+	n := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			n++
+		case '\t':
+			n += 4 - n%4
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// dedent removes width columns of indentation from line
+func dedent(line string, width int) string {
+	n := 0
+	for i, r := range line {
+		if n >= width || (r != ' ' && r != '\t') {
+			return line[i:]
+		}
+		if r == '\t' {
+			n += 4 - n%4
+		} else {
+			n++
+		}
+	}
+	return ""
+}
+
+// joinLines joins the lines of a paragraph, turning a trailing backslash
+// or two trailing spaces into a hard line break
+func joinLines(lines []string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		line = strings.TrimLeft(line, " \t")
+		if i == len(lines)-1 {
+			b.WriteString(strings.TrimRight(line, " \t"))
+			break
+		}
+		switch {
+		case strings.HasSuffix(line, "  "):
+			b.WriteString(strings.TrimRight(line, " ") + hardBreak)
+		case strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`):
+			b.WriteString(strings.TrimSuffix(line, `\`) + hardBreak)
+		default:
+			b.WriteString(strings.TrimRight(line, " \t") + "\n")
+		}
+	}
+	return b.String()
+}
+
+// hardBreak marks a hard line break for renderInline; it cannot occur in text
+const hardBreak = "\x00br\x00"
@@ -0,0 +1,99 @@
+package markup_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yakuter/ugin/pkg/markup"
+)
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"paragraphs", "one\ntwo\n\nthree", "<p>one\ntwo</p>\n<p>three</p>\n"},
+		{"heading", "## Hello *world* ##", "<h2>Hello <em>world</em></h2>\n"},
+		{"emphasis", "**bold** and _it_ and ***both*** and ~~gone~~", "<p><strong>bold</strong> and <em>it</em> and <strong><em>both</em></strong> and <del>gone</del></p>\n"},
+		{"nested emphasis", "*a **b** c*", "<p><em>a <strong>b</strong> c</em></p>\n"},
+		{"intraword underscore", "snake_case_name", "<p>snake_case_name</p>\n"},
+		{"unclosed", "2 * 3 and **x", "<p>2 * 3 and **x</p>\n"},
+		{"code span", "use `a < b` here", "<p>use <code>a &lt; b</code> here</p>\n"},
+		{"escapes", `\*not em\* & <b>`, "<p>*not em* &amp; &lt;b&gt;</p>\n"},
+		{"link", `[Go *site*](https://go.dev "The Go site")`, `<p><a href="https://go.dev" title="The Go site">Go <em>site</em></a></p>` + "\n"},
+		{"unsafe link", "[click](javascript:alert(1))", "<p>click</p>\n"},
+		{"image", "![a *cat*](/cat.png)", `<p><img src="/cat.png" alt="a cat"></p>` + "\n"},
+		{"autolink", "<https://go.dev> <me@example.com>", `<p><a href="https://go.dev">https://go.dev</a> <a href="mailto:me@example.com">me@example.com</a></p>` + "\n"},
+		{"hard break", "a  \nb\\\nc", "<p>a<br>\nb<br>\nc</p>\n"},
+		{"fence", "```go\nif a < b {\n}\n```", "<pre><code class=\"language-go\">if a &lt; b {\n}\n</code></pre>\n"},
+		{"unclosed fence", "~~~\ncode", "<pre><code>code\n</code></pre>\n"},
+		{"indented code", "    x := 1\n\n    y := 2", "<pre><code>x := 1\n\ny := 2\n</code></pre>\n"},
+		{"quote", "> quoted\n> **text**", "<blockquote>\n<p>quoted\n<strong>text</strong></p>\n</blockquote>\n"},
+		{"rule", "a\n\n---\n\nb", "<p>a</p>\n<hr>\n<p>b</p>\n"},
+		{"tight list", "- one\n- two", "<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n"},
+		{"loose list", "1. one\n\n2. two", "<ol>\n<li><p>one</p></li>\n<li><p>two</p></li>\n</ol>\n"},
+		{"ordered start", "3) three\n4) four", "<ol start=\"3\">\n<li>three</li>\n<li>four</li>\n</ol>\n"},
+		{"nested list", "- a\n  - b\n  - c\n- d", "<ul>\n<li>a\n<ul>\n<li>b</li>\n<li>c</li>\n</ul></li>\n<li>d</li>\n</ul>\n"},
+		{"raw html", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markup.Markdown(tt.in); got != tt.want {
+				t.Errorf("Markdown(%q)\n got: %q\nwant: %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"allowed", `<p>Hi <strong>there</strong></p>`, `<p>Hi <strong>there</strong></p>`},
+		{"script", `a<script>alert("x")</script>b`, `ab`},
+		{"style", `<style>p{}</style><p>x</p>`, `<p>x</p>`},
+		{"event handler", `<img src="/a.png" onerror="alert(1)">`, `<img src="/a.png">`},
+		{"javascript url", `<a href="javascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"encoded javascript url", `<a href="jav&#x09;ascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"data url", `<img src="data:image/png;base64,AAAA">`, `<img>`},
+		{"safe link", `<a href="https://go.dev" target="_blank">go</a>`, `<a href="https://go.dev" rel="nofollow noopener noreferrer">go</a>`},
+		{"unknown element", `<marquee>hi</marquee>`, `hi`},
+		{"iframe", `<iframe src="https://evil"></iframe>ok`, `ok`},
+		{"style attribute", `<p style="color:red">x</p>`, `<p>x</p>`},
+		{"code class", `<code class="language-go">x</code><code class="evil">y</code>`, `<code class="language-go">x</code><code>y</code>`},
+		{"unclosed", `<p><em>open`, `<p><em>open</em></p>`},
+		{"misnested", `<b><i>x</b>y</i>`, `<b><i>x</i></b>y`},
+		{"stray end tag", `x</div>`, `x`},
+		{"comment", `a<!-- <script>x</script> -->b`, `ab`},
+		{"text escaping", `1 &lt; 2 & "q"`, `1 &lt; 2 &amp; &#34;q&#34;`},
+		{"svg", `<svg><script>alert(1)</script></svg>after`, `after`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markup.Sanitize(tt.in); got != tt.want {
+				t.Errorf("Sanitize(%q)\n got: %q\nwant: %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	got := markup.Text("a < b\nc\n\n\nd")
+	want := "<p>a &lt; b<br>\nc</p>\n<p>d</p>\n"
+	if got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
+
+func TestMarkdownIsSanitizable(t *testing.T) {
+	in := "# Title\n\n- [x](https://a.example)\n\n```\n<script>\n```\n"
+	rendered := markup.Markdown(in)
+	if sanitized := markup.Sanitize(rendered); !strings.Contains(sanitized, "&lt;script&gt;") || strings.Contains(sanitized, "<script") {
+		t.Errorf("unexpected sanitized output %q", sanitized)
+	}
+}
@@ -0,0 +1,221 @@
+package markup
+
+import (
+	"bytes"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// allowedElements maps the elements kept by Sanitize to the attributes they may carry
+var allowedElements = map[string]map[string]bool{
+	"a":          {"href": true, "title": true},
+	"abbr":       {"title": true},
+	"b":          {},
+	"blockquote": {"cite": true},
+	"br":         {},
+	"code":       {"class": true},
+	"dd":         {},
+	"del":        {},
+	"div":        {},
+	"dl":         {},
+	"dt":         {},
+	"em":         {},
+	"figcaption": {},
+	"figure":     {},
+	"h1":         {},
+	"h2":         {},
+	"h3":         {},
+	"h4":         {},
+	"h5":         {},
+	"h6":         {},
+	"hr":         {},
+	"i":          {},
+	"img":        {"src": true, "alt": true, "title": true, "width": true, "height": true},
+	"ins":        {},
+	"kbd":        {},
+	"li":         {},
+	"mark":       {},
+	"ol":         {"start": true},
+	"p":          {},
+	"pre":        {},
+	"s":          {},
+	"span":       {},
+	"strong":     {},
+	"sub":        {},
+	"sup":        {},
+	"table":      {},
+	"tbody":      {},
+	"td":         {"align": true},
+	"th":         {"align": true},
+	"thead":      {},
+	"tr":         {},
+	"u":          {},
+	"ul":         {},
+}
+
+// droppedElements are removed together with everything inside them
+var droppedElements = map[string]bool{
+	"embed":    true,
+	"frame":    true,
+	"frameset": true,
+	"head":     true,
+	"iframe":   true,
+	"math":     true,
+	"noembed":  true,
+	"noframes": true,
+	"noscript": true,
+	"object":   true,
+	"script":   true,
+	"select":   true,
+	"style":    true,
+	"svg":      true,
+	"template": true,
+	"textarea": true,
+	"title":    true,
+	"xmp":      true,
+}
+
+// voidElements have no content and no end tag
+var voidElements = map[string]bool{"br": true, "hr": true, "img": true}
+
+// urlAttributes hold URLs, which must use one of the allowed schemes
+var urlAttributes = map[string]bool{"href": true, "src": true, "cite": true}
+
+// allowedSchemes are the URL schemes links and images may use; relative URLs are always allowed
+var allowedSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+var (
+	codeClass = regexp.MustCompile(`^language-[A-Za-z0-9_+-]+$`)
+	dimension = regexp.MustCompile(`^[0-9]{1,4}%?$`)
+	alignment = map[string]bool{"left": true, "center": true, "right": true}
+)
+
+// Sanitize returns the HTML in src reduced to an allowlist of formatting
+// elements and attributes. Scripts, styles, embedded content, event
+// handlers and URLs with schemes other than http, https and mailto are
+// removed, unknown elements are unwrapped to their text, and the output
+// is always well-formed.
+func Sanitize(src string) string {
+	var out bytes.Buffer
+	tokenizer := html.NewTokenizer(strings.NewReader(src))
+
+	// open holds the allowed elements not yet closed; dropped counts the
+	// dropped elements the tokenizer is currently inside of
+	var open []string
+	dropped := 0
+
+	for {
+		tt := tokenizer.Next()
+		if tt == html.ErrorToken {
+			// io.EOF, or a read error that cannot happen on a string
+			break
+		}
+		token := tokenizer.Token()
+		name := token.Data
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedElements[name] {
+				if tt == html.StartTagToken {
+					dropped++
+				}
+				continue
+			}
+			if dropped > 0 {
+				continue
+			}
+			attrs, ok := allowedElements[name]
+			if !ok {
+				continue
+			}
+
+			writeStartTag(&out, name, token.Attr, attrs)
+			if !voidElements[name] {
+				open = append(open, name)
+			}
+
+		case html.EndTagToken:
+			if droppedElements[name] {
+				if dropped > 0 {
+					dropped--
+				}
+				continue
+			}
+			if dropped > 0 {
+				continue
+			}
+
+			// Close everything opened since the element; stray end tags are ignored
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != name {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					out.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+
+		case html.TextToken:
+			if dropped == 0 {
+				out.WriteString(html.EscapeString(token.Data))
+			}
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		out.WriteString("</" + open[i] + ">")
+	}
+
+	return out.String()
+}
+
+func writeStartTag(out *bytes.Buffer, name string, attrs []html.Attribute, allowed map[string]bool) {
+	out.WriteString("<" + name)
+
+	for _, attr := range attrs {
+		key := strings.ToLower(attr.Key)
+		if attr.Namespace != "" || !allowed[key] || !allowedValue(key, attr.Val) {
+			continue
+		}
+		out.WriteString(" " + key + `="` + html.EscapeString(attr.Val) + `"`)
+	}
+
+	// Links in user content must not pass on ranking or the opener
+	if name == "a" {
+		out.WriteString(` rel="nofollow noopener noreferrer"`)
+	}
+
+	out.WriteString(">")
+}
+
+func allowedValue(key, value string) bool {
+	switch {
+	case urlAttributes[key]:
+		return safeURL(value)
+	case key == "class":
+		return codeClass.MatchString(value)
+	case key == "width", key == "height":
+		return dimension.MatchString(value)
+	case key == "align":
+		return alignment[strings.ToLower(value)]
+	case key == "start":
+		return dimension.MatchString(value)
+	}
+	return true
+}
+
+// safeURL reports whether u is relative or uses an allowed scheme. URLs
+// that do not parse, such as ones hiding a scheme behind control
+// characters, are rejected.
+func safeURL(u string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(u))
+	if err != nil {
+		return false
+	}
+	return parsed.Scheme == "" || allowedSchemes[strings.ToLower(parsed.Scheme)]
+}
@@ -0,0 +1,77 @@
+// Package moderation screens user-written text for listed words and link spam.
+package moderation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// links matches web addresses, counting a URL and the host inside it once
+var links = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"')\]]+`)
+
+// Filter flags text that contains a listed word or phrase, or more links
+// than allowed. A Filter is safe for concurrent use.
+type Filter struct {
+	// phrases holds the listed words and phrases as sequences of words
+	phrases  [][]string
+	maxLinks int
+}
+
+// New creates a filter for the listed words and phrases, matched as whole
+// words ignoring case and punctuation. Text with more than maxLinks links
+// is flagged; a maxLinks of zero or less allows any number.
+func New(words []string, maxLinks int) *Filter {
+	f := &Filter{maxLinks: maxLinks}
+	for _, word := range words {
+		if phrase := tokenize(word); len(phrase) > 0 {
+			f.phrases = append(f.phrases, phrase)
+		}
+	}
+	return f
+}
+
+// Check returns the reasons text is flagged, or nil when it is clean
+func (f *Filter) Check(text string) []string {
+	var reasons []string
+
+	words := tokenize(text)
+	for _, phrase := range f.phrases {
+		if contains(words, phrase) {
+			reasons = append(reasons, fmt.Sprintf("contains %q", strings.Join(phrase, " ")))
+		}
+	}
+
+	if f.maxLinks > 0 {
+		if n := len(links.FindAllStringIndex(text, -1)); n > f.maxLinks {
+			reasons = append(reasons, fmt.Sprintf("has %d links, more than %d", n, f.maxLinks))
+		}
+	}
+
+	return reasons
+}
+
+// tokenize splits text into lowercase words of letters and digits
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// contains reports whether phrase appears in words as consecutive words
+func contains(words, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		match := true
+		for j, word := range phrase {
+			if words[i+j] != word {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
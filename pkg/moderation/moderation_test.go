@@ -0,0 +1,46 @@
+package moderation_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yakuter/ugin/pkg/moderation"
+)
+
+func TestFilterCheck(t *testing.T) {
+	filter := moderation.New([]string{"casino", "Free Money", "  "}, 2)
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"clean", "Getting started with Go", nil},
+		{"listed word", "Best CASINO bonuses!", []string{`contains "casino"`}},
+		{"whole words only", "Casinos and occasional posts", nil},
+		{"phrase across punctuation", "Get free, money now", []string{`contains "free money"`}},
+		{"phrase split by another word", "free and money", nil},
+		{"links within limit", "See https://go.dev and www.example.com", nil},
+		{"url counted once", "https://www.example.com/a https://www.example.com/b", nil},
+		{
+			"too many links and a word",
+			"casino http://a.example http://b.example [c](https://c.example)",
+			[]string{`contains "casino"`, "has 3 links, more than 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter.Check(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterUnlimitedLinks(t *testing.T) {
+	filter := moderation.New(nil, 0)
+	if got := filter.Check("http://a.example http://b.example http://c.example"); got != nil {
+		t.Errorf("expected no reasons, got %q", got)
+	}
+}
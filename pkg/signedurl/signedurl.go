@@ -0,0 +1,72 @@
+// Package signedurl creates and checks URLs that grant access to a path
+// until they expire, using an HMAC-SHA256 signature over path and expiry.
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Query parameters added to signed URLs
+const (
+	ExpiresParam   = "expires"
+	SignatureParam = "signature"
+)
+
+var (
+	// ErrExpired is returned for URLs used after their expiry
+	ErrExpired = errors.New("signed URL has expired")
+	// ErrInvalidSignature is returned for missing or tampered signatures
+	ErrInvalidSignature = errors.New("invalid URL signature")
+)
+
+// Signer signs and verifies URLs with a secret key
+type Signer struct {
+	key []byte
+}
+
+// New returns a signer using key
+func New(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// Sign returns path with expires and signature parameters that keep it
+// valid until expiresAt
+func (s *Signer) Sign(path string, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set(ExpiresParam, expires)
+	query.Set(SignatureParam, s.signature(path, expires))
+
+	return path + "?" + query.Encode()
+}
+
+// Verify checks the expires and signature parameters of a request for path at time now
+func (s *Signer) Verify(path, expires, signature string, now time.Time) error {
+	if !hmac.Equal([]byte(signature), []byte(s.signature(path, expires))) {
+		return ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if now.Unix() > unix {
+		return ErrExpired
+	}
+	return nil
+}
+
+func (s *Signer) signature(path, expires string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(path))
+	mac.Write([]byte{0})
+	mac.Write([]byte(expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
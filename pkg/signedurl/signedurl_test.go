@@ -0,0 +1,52 @@
+package signedurl_test
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yakuter/ugin/pkg/signedurl"
+)
+
+func TestSigner(t *testing.T) {
+	signer := signedurl.New([]byte("secret"))
+	now := time.Unix(1700000000, 0)
+	path := "/api/v1/attachments/abc/download"
+
+	signed := signer.Sign(path, now.Add(time.Minute))
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expires := u.Query().Get(signedurl.ExpiresParam)
+	signature := u.Query().Get(signedurl.SignatureParam)
+
+	tests := []struct {
+		name      string
+		path      string
+		expires   string
+		signature string
+		key       string
+		now       time.Time
+		want      error
+	}{
+		{name: "valid", path: u.Path, expires: expires, signature: signature, key: "secret", now: now},
+		{name: "last second", path: u.Path, expires: expires, signature: signature, key: "secret", now: now.Add(time.Minute)},
+		{name: "expired", path: u.Path, expires: expires, signature: signature, key: "secret", now: now.Add(time.Minute + time.Second), want: signedurl.ErrExpired},
+		{name: "other path", path: strings.Replace(u.Path, "abc", "abd", 1), expires: expires, signature: signature, key: "secret", now: now, want: signedurl.ErrInvalidSignature},
+		{name: "extended expiry", path: u.Path, expires: expires + "0", signature: signature, key: "secret", now: now, want: signedurl.ErrInvalidSignature},
+		{name: "other key", path: u.Path, expires: expires, signature: signature, key: "other", now: now, want: signedurl.ErrInvalidSignature},
+		{name: "missing signature", path: u.Path, expires: expires, key: "secret", now: now, want: signedurl.ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := signedurl.New([]byte(tt.key)).Verify(tt.path, tt.expires, tt.signature, tt.now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Package sitemap writes documents of the sitemaps.org protocol: URL sets
+// and the sitemap indexes that split large sites into several URL sets.
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"time"
+)
+
+const (
+	// MaxURLs is the most URLs a single sitemap may list
+	MaxURLs = 50000
+	// ContentType is the content type of sitemaps and sitemap indexes
+	ContentType = "application/xml; charset=utf-8"
+
+	namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+)
+
+// URL is an entry of a sitemap or sitemap index
+type URL struct {
+	Loc string
+	// LastMod is when the page last changed; the zero time omits it
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	URLs    []entry  `xml:"url"`
+}
+
+type index struct {
+	XMLName  xml.Name `xml:"sitemapindex"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Sitemaps []entry  `xml:"sitemap"`
+}
+
+type entry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// URLSet returns a sitemap listing urls, which must not exceed MaxURLs
+func URLSet(urls []URL) ([]byte, error) {
+	return marshal(urlSet{XMLNS: namespace, URLs: entries(urls)})
+}
+
+// Index returns a sitemap index listing the sitemaps at the given URLs
+func Index(sitemaps []URL) ([]byte, error) {
+	return marshal(index{XMLNS: namespace, Sitemaps: entries(sitemaps)})
+}
+
+// Split divides urls into consecutive chunks of at most size URLs
+func Split(urls []URL, size int) [][]URL {
+	if size <= 0 {
+		size = MaxURLs
+	}
+
+	chunks := make([][]URL, 0, (len(urls)+size-1)/size)
+	for len(urls) > size {
+		chunks = append(chunks, urls[:size])
+		urls = urls[size:]
+	}
+	if len(urls) > 0 {
+		chunks = append(chunks, urls)
+	}
+	return chunks
+}
+
+func entries(urls []URL) []entry {
+	out := make([]entry, len(urls))
+	for i, u := range urls {
+		out[i].Loc = u.Loc
+		if !u.LastMod.IsZero() {
+			out[i].LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+	}
+	return out
+}
+
+func marshal(doc interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,90 @@
+package sitemap_test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yakuter/ugin/pkg/sitemap"
+)
+
+func TestURLSet(t *testing.T) {
+	out, err := sitemap.URLSet([]sitemap.URL{
+		{Loc: "https://example.com/posts?Tag=a&b", LastMod: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{Loc: "https://example.com/about"},
+	})
+	if err != nil {
+		t.Fatalf("URLSet: %v", err)
+	}
+	if !strings.Contains(string(out), `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`) {
+		t.Errorf("missing sitemap namespace:\n%s", out)
+	}
+
+	var doc struct {
+		URLs []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"url"`
+	}
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+
+	if len(doc.URLs) != 2 {
+		t.Fatalf("got %d URLs, want 2", len(doc.URLs))
+	}
+	if doc.URLs[0].Loc != "https://example.com/posts?Tag=a&b" || doc.URLs[0].LastMod != "2025-03-01T10:00:00Z" {
+		t.Errorf("unexpected first URL: %+v", doc.URLs[0])
+	}
+	if strings.Count(string(out), "<lastmod>") != 1 {
+		t.Errorf("zero LastMod must be omitted:\n%s", out)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	out, err := sitemap.Index([]sitemap.URL{{Loc: "https://example.com/sitemaps/1.xml"}})
+	if err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+
+	var doc struct {
+		XMLName  xml.Name
+		Sitemaps []struct {
+			Loc string `xml:"loc"`
+		} `xml:"sitemap"`
+	}
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+	if doc.XMLName.Local != "sitemapindex" || len(doc.Sitemaps) != 1 {
+		t.Errorf("unexpected index: %+v", doc)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		urls int
+		size int
+		want []int
+	}{
+		{urls: 0, size: 2, want: []int{}},
+		{urls: 2, size: 2, want: []int{2}},
+		{urls: 5, size: 2, want: []int{2, 2, 1}},
+		{urls: 3, size: 0, want: []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d by %d", tt.urls, tt.size), func(t *testing.T) {
+			chunks := sitemap.Split(make([]sitemap.URL, tt.urls), tt.size)
+			got := make([]int, len(chunks))
+			for i, chunk := range chunks {
+				got[i] = len(chunk)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("chunk sizes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
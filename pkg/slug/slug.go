@@ -0,0 +1,73 @@
+// Package slug builds URL-friendly identifiers from arbitrary text.
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength is the maximum length of a generated slug
+const MaxLength = 200
+
+// transliterations covers letters that do not decompose into ASCII
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'œ': "oe", 'Œ': "oe", 'ø': "o", 'Ø': "o",
+	'ł': "l", 'Ł': "l", 'đ': "d", 'Đ': "d", 'ð': "d", 'Ð': "d", 'þ': "th", 'Þ': "th",
+	'ı': "i", 'ħ': "h", 'Ħ': "h",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+}
+
+// Make returns a lowercase, hyphen-separated ASCII slug for s. Accented
+// letters are reduced to their base letter and common non-Latin letters are
+// transliterated; anything else is treated as a separator. The result may
+// be empty when s contains nothing that can be transliterated.
+func Make(s string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	for _, r := range norm.NFKD.String(strings.ToLower(s)) {
+		var part string
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			part = string(r)
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks left over from decomposition
+			continue
+		default:
+			var ok bool
+			if part, ok = transliterations[r]; !ok {
+				pendingHyphen = true
+				continue
+			}
+			if part == "" {
+				continue
+			}
+		}
+
+		if pendingHyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingHyphen = false
+		b.WriteString(part)
+	}
+
+	result := b.String()
+	if len(result) > MaxLength {
+		result = strings.TrimRight(result[:MaxLength], "-")
+	}
+	return result
+}
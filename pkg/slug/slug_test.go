@@ -0,0 +1,41 @@
+package slug_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yakuter/ugin/pkg/slug"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Getting Started with Go", "getting-started-with-go"},
+		{"  Hello,   World!  ", "hello-world"},
+		{"Çok Güzel Şeyler Öğren", "cok-guzel-seyler-ogren"},
+		{"Straße & Smørrebrød", "strasse-smorrebrod"},
+		{"Привет мир", "privet-mir"},
+		{"Go 1.23 released", "go-1-23-released"},
+		{"🚀🚀", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := slug.Make(tt.in); got != tt.want {
+				t.Errorf("Make(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeMaxLength(t *testing.T) {
+	got := slug.Make(strings.Repeat("ab ", 200))
+	if len(got) > slug.MaxLength {
+		t.Errorf("slug length %d exceeds %d", len(got), slug.MaxLength)
+	}
+	if strings.HasSuffix(got, "-") {
+		t.Errorf("slug %q ends with a hyphen", got)
+	}
+}
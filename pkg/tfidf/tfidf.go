@@ -0,0 +1,199 @@
+// Package tfidf finds similar documents by the cosine similarity of their
+// TF-IDF weighted terms. The index is kept up to date as documents are
+// added and removed, and weights are computed against the current corpus
+// when it is queried.
+package tfidf
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// stopWords are common English words that say nothing about a document
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "has": true, "have": true,
+	"in": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"or": true, "that": true, "the": true, "this": true, "to": true, "was": true,
+	"were": true, "will": true, "with": true, "you": true, "your": true,
+}
+
+// Match is a document found similar to a query
+type Match struct {
+	ID string
+	// Score is the cosine similarity, from 0 to 1
+	Score float64
+}
+
+// Index holds the term frequencies of a corpus of documents
+type Index struct {
+	// docs maps document IDs to the number of times each term occurs
+	docs map[string]map[string]int
+	// postings maps terms to the documents containing them
+	postings map[string]map[string]bool
+}
+
+// New creates an empty index
+func New() *Index {
+	return &Index{
+		docs:     make(map[string]map[string]int),
+		postings: make(map[string]map[string]bool),
+	}
+}
+
+// Len returns the number of documents in the index
+func (ix *Index) Len() int {
+	return len(ix.docs)
+}
+
+// Add indexes text as the document id, replacing an earlier version
+func (ix *Index) Add(id, text string) {
+	ix.Remove(id)
+
+	terms := termCounts(text)
+	ix.docs[id] = terms
+	for term := range terms {
+		if ix.postings[term] == nil {
+			ix.postings[term] = make(map[string]bool)
+		}
+		ix.postings[term][id] = true
+	}
+}
+
+// Remove drops the document id from the index
+func (ix *Index) Remove(id string) {
+	for term := range ix.docs[id] {
+		delete(ix.postings[term], id)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	delete(ix.docs, id)
+}
+
+// Similar returns up to limit other documents most similar to the
+// document id, best first. Documents without terms in common are left out.
+func (ix *Index) Similar(id string, limit int) []Match {
+	terms, ok := ix.docs[id]
+	if !ok {
+		return nil
+	}
+	return ix.query(terms, id, limit)
+}
+
+// SimilarTo returns up to limit documents most similar to text, best first
+func (ix *Index) SimilarTo(text string, limit int) []Match {
+	return ix.query(termCounts(text), "", limit)
+}
+
+// Scores returns the similarity of the document id to every other
+// document it has terms in common with
+func (ix *Index) Scores(id string) map[string]float64 {
+	terms, ok := ix.docs[id]
+	if !ok {
+		return nil
+	}
+	return ix.scores(terms, id)
+}
+
+func (ix *Index) query(terms map[string]int, exclude string, limit int) []Match {
+	scores := ix.scores(terms, exclude)
+
+	matches := make([]Match, 0, len(scores))
+	for id, score := range scores {
+		matches = append(matches, Match{ID: id, Score: score})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// scores computes the cosine similarity of terms to the documents sharing
+// at least one of them, other than exclude
+func (ix *Index) scores(terms map[string]int, exclude string) map[string]float64 {
+	query := ix.weights(terms)
+	queryNorm := norm(query)
+	if queryNorm == 0 {
+		return nil
+	}
+
+	dots := make(map[string]float64)
+	for term, weight := range query {
+		for id := range ix.postings[term] {
+			if id != exclude {
+				dots[id] += weight * ix.weight(term, ix.docs[id][term])
+			}
+		}
+	}
+
+	scores := make(map[string]float64, len(dots))
+	for id, dot := range dots {
+		if docNorm := norm(ix.weights(ix.docs[id])); docNorm > 0 && dot > 0 {
+			scores[id] = dot / (queryNorm * docNorm)
+		}
+	}
+	return scores
+}
+
+// weights returns the TF-IDF weight of each of the terms
+func (ix *Index) weights(terms map[string]int) map[string]float64 {
+	out := make(map[string]float64, len(terms))
+	for term, n := range terms {
+		out[term] = ix.weight(term, n)
+	}
+	return out
+}
+
+// weight is the TF-IDF weight of a term occurring n times in a document,
+// using a logarithmic term frequency and a smoothed inverse document
+// frequency, so terms found in every document still count a little
+func (ix *Index) weight(term string, n int) float64 {
+	if n == 0 {
+		return 0
+	}
+	tf := 1 + math.Log(float64(n))
+	idf := math.Log(float64(1+len(ix.docs))/float64(1+len(ix.postings[term]))) + 1
+	return tf * idf
+}
+
+func norm(v map[string]float64) float64 {
+	var sum float64
+	for _, w := range v {
+		sum += w * w
+	}
+	return math.Sqrt(sum)
+}
+
+// Terms splits text into lowercase words of letters and digits, leaving
+// out stop words and single characters
+func Terms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := words[:0]
+	for _, word := range words {
+		if len([]rune(word)) > 1 && !stopWords[word] {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+func termCounts(text string) map[string]int {
+	counts := make(map[string]int)
+	for _, term := range Terms(text) {
+		counts[term]++
+	}
+	return counts
+}
@@ -0,0 +1,78 @@
+package tfidf_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/yakuter/ugin/pkg/tfidf"
+)
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "The Go Programming Language", want: []string{"go", "programming", "language"}},
+		{text: "**Markdown**, *emphasis* & [links](x)", want: []string{"markdown", "emphasis", "links"}},
+		{text: "a to b", want: []string{}},
+		{text: "Über café 2024", want: []string{"über", "café", "2024"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := tfidf.Terms(tt.text)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Terms(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimilar(t *testing.T) {
+	ix := tfidf.New()
+	ix.Add("go", "Concurrency in Go with goroutines and channels")
+	ix.Add("go2", "Go channels explained")
+	ix.Add("rust", "Ownership and borrowing in Rust")
+	ix.Add("cooking", "Baking sourdough bread")
+
+	matches := ix.Similar("go", 10)
+	if len(matches) != 1 || matches[0].ID != "go2" {
+		t.Fatalf("Similar(go) = %v, want only go2", matches)
+	}
+	if matches[0].Score <= 0 || matches[0].Score > 1 {
+		t.Errorf("score %v out of range", matches[0].Score)
+	}
+
+	if got := ix.Similar("missing", 10); got != nil {
+		t.Errorf("Similar(missing) = %v, want nil", got)
+	}
+
+	// An identical document is as similar as can be
+	ix.Add("copy", "Baking sourdough bread")
+	if m := ix.Similar("cooking", 1); len(m) != 1 || m[0].ID != "copy" || math.Abs(m[0].Score-1) > 1e-9 {
+		t.Errorf("Similar(cooking) = %v, want copy with score 1", m)
+	}
+}
+
+func TestAddReplacesAndRemove(t *testing.T) {
+	ix := tfidf.New()
+	ix.Add("a", "golang tips")
+	ix.Add("b", "golang tricks")
+	ix.Add("a", "gardening tips")
+
+	if m := ix.SimilarTo("golang", 10); len(m) != 1 || m[0].ID != "b" {
+		t.Errorf("SimilarTo(golang) = %v, want only b", m)
+	}
+
+	ix.Remove("b")
+	if ix.Len() != 1 {
+		t.Errorf("Len = %d, want 1", ix.Len())
+	}
+	if m := ix.SimilarTo("golang", 10); len(m) != 0 {
+		t.Errorf("SimilarTo(golang) after removal = %v, want none", m)
+	}
+}
@@ -0,0 +1,119 @@
+// Package trie implements a prefix tree mapping string keys to sets of
+// values, for looking up every value whose key starts with a prefix.
+package trie
+
+import "sort"
+
+// Trie maps keys to values by their runes. The zero value is empty and
+// ready to use; it is not safe for concurrent use.
+type Trie struct {
+	root node
+}
+
+type node struct {
+	children map[rune]*node
+	// values holds the values stored under the key ending at this node
+	values map[string]bool
+}
+
+// Insert stores value under key
+func (t *Trie) Insert(key, value string) {
+	n := &t.root
+	for _, r := range key {
+		if n.children == nil {
+			n.children = make(map[rune]*node)
+		}
+		child, ok := n.children[r]
+		if !ok {
+			child = &node{}
+			n.children[r] = child
+		}
+		n = child
+	}
+
+	if n.values == nil {
+		n.values = make(map[string]bool)
+	}
+	n.values[value] = true
+}
+
+// Delete removes value from key, pruning nodes left without values
+func (t *Trie) Delete(key, value string) {
+	runes := []rune(key)
+	path := make([]*node, 0, len(runes)+1)
+
+	n := &t.root
+	path = append(path, n)
+	for _, r := range runes {
+		child, ok := n.children[r]
+		if !ok {
+			return
+		}
+		n = child
+		path = append(path, n)
+	}
+
+	delete(n.values, value)
+	for i := len(runes); i > 0; i-- {
+		if len(path[i].values) > 0 || len(path[i].children) > 0 {
+			break
+		}
+		delete(path[i-1].children, runes[i-1])
+	}
+}
+
+// Search returns up to limit distinct values stored under keys starting
+// with prefix, those of shorter keys first and otherwise ordered by key.
+// A limit of zero or less returns every value.
+func (t *Trie) Search(prefix string, limit int) []string {
+	n := &t.root
+	for _, r := range prefix {
+		child, ok := n.children[r]
+		if !ok {
+			return nil
+		}
+		n = child
+	}
+
+	// Breadth-first, so shorter keys and thus closer matches come first
+	var out []string
+	seen := make(map[string]bool)
+	level := []*node{n}
+	for len(level) > 0 {
+		var next []*node
+		for _, n := range level {
+			for _, value := range sortedKeys(n.values) {
+				if !seen[value] {
+					seen[value] = true
+					out = append(out, value)
+					if limit > 0 && len(out) == limit {
+						return out
+					}
+				}
+			}
+			for _, r := range sortedRunes(n.children) {
+				next = append(next, n.children[r])
+			}
+		}
+		level = next
+	}
+	return out
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedRunes(m map[rune]*node) []rune {
+	runes := make([]rune, 0, len(m))
+	for r := range m {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return runes
+}
@@ -0,0 +1,59 @@
+package trie_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yakuter/ugin/pkg/trie"
+)
+
+func TestSearch(t *testing.T) {
+	var tr trie.Trie
+	tr.Insert("go", "tag:go")
+	tr.Insert("golang", "tag:golang")
+	tr.Insert("gopher", "post:1")
+	tr.Insert("go concurrency", "post:2")
+	tr.Insert("concurrency", "post:2")
+	tr.Insert("rust", "tag:rust")
+
+	tests := []struct {
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{prefix: "go", want: []string{"tag:go", "tag:golang", "post:1", "post:2"}},
+		{prefix: "go", limit: 2, want: []string{"tag:go", "tag:golang"}},
+		{prefix: "conc", want: []string{"post:2"}},
+		{prefix: "java", want: nil},
+		{prefix: "", limit: 1, want: []string{"tag:go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			if got := tr.Search(tt.prefix, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q, %d) = %v, want %v", tt.prefix, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	var tr trie.Trie
+	tr.Insert("go", "a")
+	tr.Insert("go", "b")
+	tr.Insert("golang", "c")
+
+	tr.Delete("go", "a")
+	if got := tr.Search("go", 0); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("after deleting a: %v", got)
+	}
+
+	tr.Delete("golang", "c")
+	tr.Delete("missing", "x")
+	if got := tr.Search("gol", 0); got != nil {
+		t.Errorf("deleted key still found: %v", got)
+	}
+	if got := tr.Search("g", 0); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("after deleting c: %v", got)
+	}
+}
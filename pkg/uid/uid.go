@@ -0,0 +1,90 @@
+// Package uid generates opaque, time-ordered public identifiers.
+package uid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	mu       sync.Mutex
+	lastMS   int64
+	sequence uint16
+)
+
+// NewV7 returns a new RFC 9562 version 7 UUID in its canonical string form.
+// UUIDs generated within the same millisecond by this process are ordered
+// by a 12-bit counter seeded randomly for every new millisecond.
+func NewV7() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+
+	mu.Lock()
+	ms := time.Now().UnixMilli()
+	if ms <= lastMS {
+		// Same millisecond or clock moved back: stay on the last timestamp
+		sequence++
+		if sequence > 0x0fff {
+			lastMS++
+			sequence = uint16(b[6]&0x07)<<8 | uint16(b[7])
+		}
+		ms = lastMS
+	} else {
+		lastMS = ms
+		sequence = uint16(b[6]&0x07)<<8 | uint16(b[7])
+	}
+	seq := sequence
+	mu.Unlock()
+
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+	b[6] = 0x70 | byte(seq>>8)&0x0f // version 7
+	b[7] = byte(seq)
+	b[8] = b[8]&0x3f | 0x80 // RFC 9562 variant
+
+	return format(b), nil
+}
+
+// IsValid reports whether s is a UUID in canonical lowercase or uppercase form
+func IsValid(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			c := s[i]
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func format(b [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
@@ -0,0 +1,50 @@
+package uid_test
+
+import (
+	"testing"
+
+	"github.com/yakuter/ugin/pkg/uid"
+)
+
+func TestNewV7(t *testing.T) {
+	prev := ""
+	for i := 0; i < 5000; i++ {
+		id, err := uid.NewV7()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !uid.IsValid(id) {
+			t.Fatalf("invalid UUID %q", id)
+		}
+		if id[14] != '7' {
+			t.Fatalf("expected version 7, got %q", id)
+		}
+		if v := id[19]; v != '8' && v != '9' && v != 'a' && v != 'b' {
+			t.Fatalf("unexpected variant in %q", id)
+		}
+		if id <= prev {
+			t.Fatalf("UUIDs not increasing: %q after %q", id, prev)
+		}
+		prev = id
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9e", true},
+		{"0190A5F2-7C1E-7B3A-9D2E-4F5A6B7C8D9E", true},
+		{"0190a5f2-7c1e-7b3a-9d2e-4f5a6b7c8d9", false},
+		{"0190a5f2x7c1e-7b3a-9d2e-4f5a6b7c8d9e", false},
+		{"getting-started-with-go-and-more-xyz", false},
+		{"42", false},
+	}
+
+	for _, tt := range tests {
+		if got := uid.IsValid(tt.in); got != tt.want {
+			t.Errorf("IsValid(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}